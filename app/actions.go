@@ -1,7 +1,10 @@
 package app
 
 import (
+	"strconv"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
 )
 
 // User action functions - triggered by keyboard shortcuts
@@ -12,6 +15,9 @@ func (a *App) confirmCancelRun() tea.Cmd {
 	if !ok || !run.IsRunning() {
 		return nil
 	}
+	if cmd := a.writeDenied("cancel"); cmd != nil {
+		return cmd
+	}
 	a.showConfirm = true
 	a.confirmMsg = "Cancel this run?"
 	a.confirmFn = func() tea.Cmd {
@@ -20,12 +26,33 @@ func (a *App) confirmCancelRun() tea.Cmd {
 	return nil
 }
 
+// writeDenied returns a flash message explaining why an action that changes
+// runs is unavailable, or nil if the token may perform it
+func (a *App) writeDenied(action string) tea.Cmd {
+	if a.permissions.CanWriteActions() {
+		return nil
+	}
+	return flashMessage("Can't "+action+": "+a.permissions.Hint, FlashDurationInfo)
+}
+
+// writeHints renders the key hints of actions that change runs, dimmed and
+// marked read-only if the token can't perform them
+func (a *App) writeHints(hints string) string {
+	if a.permissions.CanWriteActions() {
+		return hints
+	}
+	return QueuedStyle.Render(hints + " (read-only)")
+}
+
 // rerunWorkflow triggers a workflow rerun
 func (a *App) rerunWorkflow() tea.Cmd {
 	run, ok := a.runs.Selected()
 	if !ok {
 		return nil
 	}
+	if cmd := a.writeDenied("rerun"); cmd != nil {
+		return cmd
+	}
 	return rerunWorkflow(a.client, a.repo, run.ID)
 }
 
@@ -35,6 +62,9 @@ func (a *App) rerunFailedJobs() tea.Cmd {
 	if !ok || !run.IsFailed() {
 		return nil
 	}
+	if cmd := a.writeDenied("rerun"); cmd != nil {
+		return cmd
+	}
 	return rerunFailedJobs(a.client, a.repo, run.ID)
 }
 
@@ -44,6 +74,9 @@ func (a *App) triggerWorkflow() tea.Cmd {
 	if !ok {
 		return nil
 	}
+	if cmd := a.writeDenied("trigger"); cmd != nil {
+		return cmd
+	}
 	// Get workflow file name from path (e.g., ".github/workflows/ci.yml" -> "ci.yml")
 	workflowFile := wf.Path
 	if idx := len(".github/workflows/"); len(wf.Path) > idx {
@@ -68,9 +101,19 @@ func (a *App) yankURL() tea.Cmd {
 	return flashMessage("Copied: "+run.URL, FlashDurationSuccess)
 }
 
+// runJobURL returns the web URL of a job of a run, or "" if the run's URL is
+// unknown
+func runJobURL(run github.Run, jobID int64) string {
+	if run.URL == "" {
+		return ""
+	}
+	return run.URL + "/job/" + strconv.FormatInt(jobID, 10)
+}
+
 // refreshAll refreshes all data
 func (a *App) refreshAll() tea.Cmd {
 	a.loading = true
+	a.analytics = make(map[int64]WorkflowStats)
 	return a.fetchWorkflowsCmd()
 }
 
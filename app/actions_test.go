@@ -1,8 +1,10 @@
 package app
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nnnkkk7/lazyactions/github"
 )
 
@@ -119,3 +121,57 @@ func TestApp_RefreshCurrentWorkflow_WithSelection(t *testing.T) {
 		t.Error("refreshCurrentWorkflow should return command when workflow is selected")
 	}
 }
+
+func TestApp_WriteActions_ReadOnlyToken(t *testing.T) {
+	app := New()
+	app.permissions = github.Permissions{Checked: true, Hint: "token lacks the actions:write permission"}
+	app.workflows.SetItems([]github.Workflow{{ID: 1, Path: ".github/workflows/ci.yml"}})
+	app.runs.SetItems([]github.Run{{ID: 1, Status: "completed", Conclusion: "failure"}})
+
+	for name, action := range map[string]func() tea.Cmd{
+		"rerun":        app.rerunWorkflow,
+		"rerun failed": app.rerunFailedJobs,
+		"trigger":      app.triggerWorkflow,
+	} {
+		msg := drainFlash(action())
+		if !strings.Contains(msg, "actions:write") {
+			t.Errorf("%s flash = %q, want permission hint", name, msg)
+		}
+	}
+
+	app.runs.SetItems([]github.Run{{ID: 1, Status: "in_progress"}})
+	if msg := drainFlash(app.confirmCancelRun()); !strings.HasPrefix(msg, "Can't cancel") {
+		t.Errorf("cancel flash = %q, want permission hint", msg)
+	}
+	if app.showConfirm {
+		t.Error("confirm dialog shown for a read-only token")
+	}
+}
+
+func TestApp_CheckPermissionsCmd(t *testing.T) {
+	perms := github.Permissions{Checked: true, ActionsWrite: true}
+	app := New(WithClient(newMockClient(&mockClientState{permissions: perms})))
+
+	msg, ok := app.checkPermissionsCmd()().(PermissionsCheckedMsg)
+	if !ok {
+		t.Fatal("checkPermissionsCmd() should return PermissionsCheckedMsg")
+	}
+	app.Update(msg)
+	if !app.permissions.Checked || !app.permissions.CanWriteActions() {
+		t.Errorf("permissions = %+v, want checked with write access", app.permissions)
+	}
+}
+
+// drainFlash returns the text of the flash message a flashMessage command
+// shows, without waiting for it to be cleared
+func drainFlash(cmd tea.Cmd) string {
+	if cmd == nil {
+		return ""
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		return ""
+	}
+	flash, _ := batch[0]().(FlashMsg)
+	return flash.Message
+}
@@ -0,0 +1,352 @@
+package app
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// Analytics constants
+const (
+	// AnalyticsRunCount is the number of completed runs analyzed per workflow
+	AnalyticsRunCount = 50
+	// AnalyticsPageSize is the page size used when paging through runs
+	AnalyticsPageSize = 100
+	// AnalyticsTrendWindow is the number of runs in each point of the success rate trend
+	AnalyticsTrendWindow = 5
+	// AnalyticsJobNameWidth is the width of the job name column in the analytics table
+	AnalyticsJobNameWidth = 20
+)
+
+// sparkBlocks are the characters used to draw sparklines, from low to high
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// AnalyticsSort is the column the analytics job table is sorted by
+type AnalyticsSort int
+
+const (
+	SortByFailureRate AnalyticsSort = iota
+	SortByFlakiness
+	SortByP95Duration
+	SortByJobName
+)
+
+// analyticsSortNames are the display names of the sort columns
+var analyticsSortNames = map[AnalyticsSort]string{
+	SortByFailureRate: "failure rate",
+	SortByFlakiness:   "flakiness",
+	SortByP95Duration: "p95 duration",
+	SortByJobName:     "name",
+}
+
+// next returns the next sort column, wrapping around
+func (s AnalyticsSort) next() AnalyticsSort {
+	return (s + 1) % AnalyticsSort(len(analyticsSortNames))
+}
+
+// WorkflowStats holds failure and duration statistics for a workflow's recent runs.
+type WorkflowStats struct {
+	Runs         int             // Number of completed runs analyzed
+	Successes    int             // Number of successful runs
+	SuccessTrend []float64       // Rolling success rate, oldest first
+	Durations    []time.Duration // Run durations, oldest first
+	MeanDuration time.Duration
+	P95Duration  time.Duration
+	Jobs         []JobStats
+}
+
+// SuccessRate returns the fraction of successful runs.
+func (s WorkflowStats) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Runs)
+}
+
+// JobStats holds statistics for one job name across runs and attempts.
+type JobStats struct {
+	Name         string
+	Executions   int // Number of times the job ran, counting every attempt
+	Failures     int
+	FlakyCommits int // Commits where the job failed and later passed on the same SHA
+	Commits      int // Commits the job ran on
+	MeanDuration time.Duration
+	P95Duration  time.Duration
+}
+
+// FailureRate returns the fraction of executions that failed.
+func (j JobStats) FailureRate() float64 {
+	if j.Executions == 0 {
+		return 0
+	}
+	return float64(j.Failures) / float64(j.Executions)
+}
+
+// Flakiness returns the fraction of commits on which the job failed and then passed.
+func (j JobStats) Flakiness() float64 {
+	if j.Commits == 0 {
+		return 0
+	}
+	return float64(j.FlakyCommits) / float64(j.Commits)
+}
+
+// jobExecution is a single execution of a job used for flakiness detection
+type jobExecution struct {
+	createdAt time.Time
+	attempt   int
+	failed    bool
+}
+
+// computeWorkflowStats computes statistics for completed runs and the jobs of
+// all their attempts, keyed by run ID. Runs may be in any order.
+func computeWorkflowStats(runs []github.Run, jobs map[int64][]github.Job) WorkflowStats {
+	ordered := make([]github.Run, len(runs))
+	copy(ordered, runs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	stats := WorkflowStats{Runs: len(ordered)}
+	outcomes := make([]bool, 0, len(ordered))
+	for _, run := range ordered {
+		ok := run.Conclusion == "success"
+		if ok {
+			stats.Successes++
+		}
+		outcomes = append(outcomes, ok)
+		if d := run.Duration(); d > 0 {
+			stats.Durations = append(stats.Durations, d)
+		}
+	}
+	stats.SuccessTrend = rollingRate(outcomes, AnalyticsTrendWindow)
+	stats.MeanDuration, stats.P95Duration = durationStats(stats.Durations)
+
+	// Group job executions by job name, then by commit
+	type jobAccumulator struct {
+		stats     JobStats
+		durations []time.Duration
+		bySHA     map[string][]jobExecution
+	}
+	byName := map[string]*jobAccumulator{}
+	var names []string
+	for _, run := range ordered {
+		for _, job := range jobs[run.ID] {
+			if !job.IsCompleted() || job.Conclusion == "skipped" {
+				continue
+			}
+			acc, ok := byName[job.Name]
+			if !ok {
+				acc = &jobAccumulator{stats: JobStats{Name: job.Name}, bySHA: map[string][]jobExecution{}}
+				byName[job.Name] = acc
+				names = append(names, job.Name)
+			}
+			failed := job.Conclusion == "failure" || job.Conclusion == "timed_out"
+			acc.stats.Executions++
+			if failed {
+				acc.stats.Failures++
+			}
+			if d := job.Duration(); d > 0 {
+				acc.durations = append(acc.durations, d)
+			}
+			sha := run.HeadSHA
+			if sha == "" {
+				sha = strconv.FormatInt(run.ID, 10)
+			}
+			acc.bySHA[sha] = append(acc.bySHA[sha], jobExecution{createdAt: run.CreatedAt, attempt: job.RunAttempt, failed: failed})
+		}
+	}
+
+	for _, name := range names {
+		acc := byName[name]
+		acc.stats.MeanDuration, acc.stats.P95Duration = durationStats(acc.durations)
+		acc.stats.Commits = len(acc.bySHA)
+		for _, execs := range acc.bySHA {
+			if failedThenPassed(execs) {
+				acc.stats.FlakyCommits++
+			}
+		}
+		stats.Jobs = append(stats.Jobs, acc.stats)
+	}
+	return stats
+}
+
+// failedThenPassed returns true if a failed execution is followed by a
+// successful one, ordering executions by run creation time and attempt
+func failedThenPassed(execs []jobExecution) bool {
+	sort.SliceStable(execs, func(i, j int) bool {
+		if !execs[i].createdAt.Equal(execs[j].createdAt) {
+			return execs[i].createdAt.Before(execs[j].createdAt)
+		}
+		return execs[i].attempt < execs[j].attempt
+	})
+	sawFailure := false
+	for _, e := range execs {
+		if e.failed {
+			sawFailure = true
+		} else if sawFailure {
+			return true
+		}
+	}
+	return false
+}
+
+// rollingRate returns the fraction of true values in each window of outcomes.
+// If there are fewer outcomes than the window, a single point is returned.
+func rollingRate(outcomes []bool, window int) []float64 {
+	if len(outcomes) == 0 {
+		return nil
+	}
+	window = min(window, len(outcomes))
+	rates := make([]float64, 0, len(outcomes)-window+1)
+	for i := 0; i+window <= len(outcomes); i++ {
+		n := 0
+		for _, ok := range outcomes[i : i+window] {
+			if ok {
+				n++
+			}
+		}
+		rates = append(rates, float64(n)/float64(window))
+	}
+	return rates
+}
+
+// durationStats returns the mean and 95th percentile of durations
+func durationStats(durations []time.Duration) (mean, p95 time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	idx := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return total / time.Duration(len(sorted)), sorted[max(idx, 0)]
+}
+
+// sparkline draws values as a line of block characters scaled between lo and hi.
+// If lo equals hi, all values are drawn at the lowest level.
+func sparkline(values []float64, lo, hi float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[max(0, min(level, len(sparkBlocks)-1))])
+	}
+	return b.String()
+}
+
+// durationSparkline draws durations as a sparkline scaled to their range
+func durationSparkline(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return ""
+	}
+	values := make([]float64, len(durations))
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, d := range durations {
+		values[i] = d.Seconds()
+		lo = math.Min(lo, values[i])
+		hi = math.Max(hi, values[i])
+	}
+	return sparkline(values, lo, hi)
+}
+
+// sortJobStats sorts job statistics in place by the given column.
+// Rates and durations sort descending so the worst jobs come first.
+func sortJobStats(jobs []JobStats, by AnalyticsSort) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i], jobs[j]
+		switch by {
+		case SortByFlakiness:
+			if a.Flakiness() != b.Flakiness() {
+				return a.Flakiness() > b.Flakiness()
+			}
+		case SortByP95Duration:
+			if a.P95Duration != b.P95Duration {
+				return a.P95Duration > b.P95Duration
+			}
+		case SortByFailureRate:
+			if a.FailureRate() != b.FailureRate() {
+				return a.FailureRate() > b.FailureRate()
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// formatPercent formats a fraction as a whole percentage
+func formatPercent(f float64) string {
+	return strconv.Itoa(int(math.Round(f*100))) + "%"
+}
+
+// buildAnalyticsContent builds the content for the Analytics tab
+func (a *App) buildAnalyticsContent(maxWidth int) []string {
+	wf, ok := a.workflows.Selected()
+	if !ok {
+		return []string{"  Select a workflow"}
+	}
+
+	var content []string
+	content = append(content, "  Analytics: "+truncateString(wf.Name, maxWidth-13))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	stats, ok := a.analytics[wf.ID]
+	if !ok {
+		if a.analyticsLoading {
+			return append(content, "  Loading last "+strconv.Itoa(AnalyticsRunCount)+" completed runs "+a.spinner.View())
+		}
+		return append(content, "  No analytics loaded")
+	}
+	if stats.Runs == 0 {
+		return append(content, "  No completed runs")
+	}
+
+	sparkWidth := max(maxWidth-16, 10)
+	trend := stats.SuccessTrend
+	if len(trend) > sparkWidth {
+		trend = trend[len(trend)-sparkWidth:]
+	}
+	durations := stats.Durations
+	if len(durations) > sparkWidth {
+		durations = durations[len(durations)-sparkWidth:]
+	}
+
+	content = append(content, "  Runs:         "+strconv.Itoa(stats.Runs)+" completed")
+	content = append(content, "  Success rate: "+formatPercent(stats.SuccessRate())+
+		" ("+strconv.Itoa(stats.Successes)+"/"+strconv.Itoa(stats.Runs)+")")
+	content = append(content, "  Trend:        "+SuccessStyle.Render(sparkline(trend, 0, 1)))
+	content = append(content, "  Duration:     mean "+formatDuration(stats.MeanDuration)+"  p95 "+formatDuration(stats.P95Duration))
+	content = append(content, "                "+RunningStyle.Render(durationSparkline(durations)))
+	content = append(content, "")
+
+	jobs := make([]JobStats, len(stats.Jobs))
+	copy(jobs, stats.Jobs)
+	sortJobStats(jobs, a.analyticsSort)
+
+	content = append(content, "  Jobs by "+analyticsSortNames[a.analyticsSort]+" ([s] sort)")
+	content = append(content, "  "+padRight("Job", AnalyticsJobNameWidth)+" Runs  Fail%  Flaky  Mean    p95")
+	for _, job := range jobs {
+		fail := padRight(formatPercent(job.FailureRate()), 6)
+		if job.Failures > 0 {
+			fail = FailureStyle.Render(fail)
+		}
+		flaky := padRight(formatPercent(job.Flakiness()), 6)
+		if job.FlakyCommits > 0 {
+			flaky = CancelledStyle.Render(flaky)
+		}
+		content = append(content, "  "+padRight(truncateString(job.Name, AnalyticsJobNameWidth-1), AnalyticsJobNameWidth)+" "+
+			padRight(strconv.Itoa(job.Executions), 5)+" "+fail+" "+flaky+" "+
+			padRight(formatDuration(job.MeanDuration), 7)+" "+formatDuration(job.P95Duration))
+	}
+
+	return content
+}
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func analyticsTestData() ([]github.Run, map[int64][]github.Job) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	run := func(id int64, sha, conclusion string, offset, duration time.Duration) github.Run {
+		return github.Run{
+			ID: id, HeadSHA: sha, Status: "completed", Conclusion: conclusion,
+			CreatedAt: base.Add(offset), RunStartedAt: base.Add(offset), UpdatedAt: base.Add(offset + duration),
+		}
+	}
+	job := func(name, conclusion string, attempt int, duration time.Duration) github.Job {
+		return github.Job{
+			Name: name, Status: "completed", Conclusion: conclusion, RunAttempt: attempt,
+			StartedAt: base, CompletedAt: base.Add(duration),
+		}
+	}
+
+	runs := []github.Run{
+		// Newest first, as returned by the API
+		run(3, "ccc", "failure", 2*time.Hour, 4*time.Minute),
+		run(2, "bbb", "success", time.Hour, 3*time.Minute),
+		run(1, "aaa", "success", 0, 2*time.Minute),
+	}
+	jobs := map[int64][]github.Job{
+		1: {job("build", "success", 1, time.Minute), job("test", "success", 1, time.Minute)},
+		// test failed on the first attempt and passed on the rerun of the same commit
+		2: {job("build", "success", 1, time.Minute), job("test", "failure", 1, 2*time.Minute), job("test", "success", 2, 2*time.Minute)},
+		3: {job("build", "success", 1, time.Minute), job("test", "failure", 1, 3*time.Minute), job("lint", "skipped", 1, 0)},
+	}
+	return runs, jobs
+}
+
+func TestComputeWorkflowStats(t *testing.T) {
+	runs, jobs := analyticsTestData()
+
+	stats := computeWorkflowStats(runs, jobs)
+
+	if stats.Runs != 3 || stats.Successes != 2 {
+		t.Errorf("Runs/Successes = %d/%d, want 3/2", stats.Runs, stats.Successes)
+	}
+	if got := formatPercent(stats.SuccessRate()); got != "67%" {
+		t.Errorf("SuccessRate = %s, want 67%%", got)
+	}
+	// Durations are ordered oldest first
+	wantDurations := []time.Duration{2 * time.Minute, 3 * time.Minute, 4 * time.Minute}
+	for i, d := range wantDurations {
+		if stats.Durations[i] != d {
+			t.Errorf("Durations[%d] = %v, want %v", i, stats.Durations[i], d)
+		}
+	}
+	if stats.MeanDuration != 3*time.Minute || stats.P95Duration != 4*time.Minute {
+		t.Errorf("Mean/P95 = %v/%v, want 3m/4m", stats.MeanDuration, stats.P95Duration)
+	}
+
+	byName := map[string]JobStats{}
+	for _, j := range stats.Jobs {
+		byName[j.Name] = j
+	}
+	if _, ok := byName["lint"]; ok {
+		t.Error("skipped jobs should not be counted")
+	}
+	test := byName["test"]
+	if test.Executions != 4 || test.Failures != 2 {
+		t.Errorf("test Executions/Failures = %d/%d, want 4/2", test.Executions, test.Failures)
+	}
+	if test.Commits != 3 || test.FlakyCommits != 1 {
+		t.Errorf("test Commits/FlakyCommits = %d/%d, want 3/1", test.Commits, test.FlakyCommits)
+	}
+	if build := byName["build"]; build.FailureRate() != 0 || build.Flakiness() != 0 {
+		t.Errorf("build should have no failures, got %+v", build)
+	}
+}
+
+func TestRollingRate(t *testing.T) {
+	got := rollingRate([]bool{true, false, true, true}, 2)
+	want := []float64{0.5, 0.5, 1}
+	if len(got) != len(want) {
+		t.Fatalf("rollingRate() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("rollingRate()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := rollingRate([]bool{true}, 5); len(got) != 1 || got[0] != 1 {
+		t.Errorf("rollingRate() with short input = %v, want [1]", got)
+	}
+	if got := rollingRate(nil, 5); got != nil {
+		t.Errorf("rollingRate(nil) = %v, want nil", got)
+	}
+}
+
+func TestDurationStats(t *testing.T) {
+	var durations []time.Duration
+	for i := 1; i <= 20; i++ {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+
+	mean, p95 := durationStats(durations)
+
+	if mean != 10500*time.Millisecond {
+		t.Errorf("mean = %v, want 10.5s", mean)
+	}
+	if p95 != 19*time.Second {
+		t.Errorf("p95 = %v, want 19s", p95)
+	}
+	if mean, p95 := durationStats(nil); mean != 0 || p95 != 0 {
+		t.Errorf("durationStats(nil) = %v/%v, want 0/0", mean, p95)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 0.5, 1}, 0, 1); got != "▁▄█" {
+		t.Errorf("sparkline() = %q, want %q", got, "▁▄█")
+	}
+	if got := sparkline([]float64{3, 3}, 3, 3); got != "▁▁" {
+		t.Errorf("sparkline() with flat range = %q, want %q", got, "▁▁")
+	}
+}
+
+func TestSortJobStats(t *testing.T) {
+	jobs := []JobStats{
+		{Name: "b", Executions: 10, Failures: 1, P95Duration: time.Minute},
+		{Name: "a", Executions: 10, Failures: 5, Commits: 10, FlakyCommits: 1, P95Duration: 2 * time.Minute},
+		{Name: "c", Executions: 10, Commits: 10, FlakyCommits: 3, P95Duration: 3 * time.Minute},
+	}
+
+	tests := []struct {
+		by   AnalyticsSort
+		want string
+	}{
+		{SortByFailureRate, "abc"},
+		{SortByFlakiness, "cab"},
+		{SortByP95Duration, "cab"},
+		{SortByJobName, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(analyticsSortNames[tt.by], func(t *testing.T) {
+			sortJobStats(jobs, tt.by)
+			var got string
+			for _, j := range jobs {
+				got += j.Name
+			}
+			if got != tt.want {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchAnalytics(t *testing.T) {
+	runs, jobs := analyticsTestData()
+	mock := newMockClient(&mockClientState{runs: runs})
+	mock.ListJobsAllAttemptsFunc = func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
+		return jobs[runID], nil
+	}
+
+	msg := fetchAnalytics(context.Background(), mock, github.Repository{}, 7)()
+
+	result, ok := msg.(AnalyticsLoadedMsg)
+	if !ok {
+		t.Fatalf("expected AnalyticsLoadedMsg, got %T", msg)
+	}
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if result.WorkflowID != 7 || result.Stats.Runs != 3 {
+		t.Errorf("WorkflowID/Runs = %d/%d, want 7/3", result.WorkflowID, result.Stats.Runs)
+	}
+	calls := mock.ListRunsCalls()
+	if len(calls) != 1 || calls[0].Opts.Status != "completed" || calls[0].Opts.Page != 1 {
+		t.Errorf("ListRuns should request the first page of completed runs, got %+v", calls)
+	}
+	if len(mock.ListJobsAllAttemptsCalls()) != 3 {
+		t.Errorf("ListJobsAllAttempts called %d times, want 3", len(mock.ListJobsAllAttemptsCalls()))
+	}
+}
+
+func TestFetchAnalytics_Error(t *testing.T) {
+	mock := newMockClient(&mockClientState{err: errAPI})
+
+	msg := fetchAnalytics(context.Background(), mock, github.Repository{}, 7)().(AnalyticsLoadedMsg)
+
+	if msg.Err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestApp_AnalyticsTab(t *testing.T) {
+	runs, jobs := analyticsTestData()
+	mock := newMockClient(&mockClientState{runs: runs})
+	app := New(WithClient(mock))
+	app.workflows.SetItems([]github.Workflow{{ID: 7, Name: "CI"}})
+
+	// Pressing 4 switches to the tab and starts loading
+	cmd := app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+	if app.detailTab != AnalyticsTab {
+		t.Errorf("detailTab = %v, want AnalyticsTab", app.detailTab)
+	}
+	if cmd == nil || !app.analyticsLoading {
+		t.Fatal("switching to the analytics tab should fetch analytics")
+	}
+	if content := strings.Join(app.buildAnalyticsContent(80), "\n"); !strings.Contains(content, "Loading") {
+		t.Errorf("content should show loading state, got:\n%s", content)
+	}
+
+	app.Update(AnalyticsLoadedMsg{WorkflowID: 7, Stats: computeWorkflowStats(runs, jobs)})
+
+	content := strings.Join(app.buildAnalyticsContent(80), "\n")
+	for _, want := range []string{"Success rate: 67%", "Jobs by failure rate", "test"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Already loaded analytics are not fetched again
+	if cmd := app.fetchAnalyticsCmd(); cmd != nil {
+		t.Error("loaded analytics should not be fetched again")
+	}
+
+	// s cycles the sort column
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if app.analyticsSort != SortByFlakiness {
+		t.Errorf("analyticsSort = %v, want SortByFlakiness", app.analyticsSort)
+	}
+}
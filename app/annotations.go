@@ -0,0 +1,161 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// DefaultEditor is the editor used when $EDITOR is not set
+const DefaultEditor = "vi"
+
+// sortAnnotations sorts annotations by file and line so they can be shown grouped by file
+func sortAnnotations(annotations []github.Annotation) []github.Annotation {
+	sorted := make([]github.Annotation, len(annotations))
+	copy(sorted, annotations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].StartLine < sorted[j].StartLine
+	})
+	return sorted
+}
+
+// annotationIcon returns an icon for an annotation level
+func annotationIcon(level string) string {
+	switch level {
+	case "failure":
+		return FailureStyle.Render("✗")
+	case "warning":
+		return CancelledStyle.Render("!")
+	default:
+		return LogNoticeStyle.Render("i")
+	}
+}
+
+// editorCommand builds the command that opens an annotation's file at its
+// line. The path is resolved relative to the local repository root.
+func editorCommand(editor, root string, ann github.Annotation) (*exec.Cmd, error) {
+	if root == "" {
+		return nil, errors.New("repository is not checked out locally")
+	}
+	if ann.Path == "" {
+		return nil, errors.New("annotation has no file")
+	}
+	path := filepath.Join(root, filepath.FromSlash(ann.Path))
+	// Annotations come from the run, so they must not reach outside the checkout
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.New("file is outside the repository: " + ann.Path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.New("file not found locally: " + ann.Path)
+	}
+
+	// $EDITOR may contain arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{DefaultEditor}
+	}
+	if ann.StartLine > 0 {
+		args = append(args, "+"+strconv.Itoa(ann.StartLine))
+	}
+	args = append(args, path)
+	return exec.Command(args[0], args[1:]...), nil
+}
+
+// openAnnotation opens the selected annotation in $EDITOR, suspending the TUI
+// until the editor exits
+func (a *App) openAnnotation() tea.Cmd {
+	if a.annotationIdx < 0 || a.annotationIdx >= len(a.annotations) {
+		return nil
+	}
+	cmd, err := editorCommand(os.Getenv("EDITOR"), a.localRoot, a.annotations[a.annotationIdx])
+	if err != nil {
+		return flashMessage(err.Error(), FlashDurationInfo)
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return EditorClosedMsg{Err: err}
+	})
+}
+
+// navigateAnnotation moves the annotation selection by delta, staying in bounds
+func (a *App) navigateAnnotation(delta int) {
+	if len(a.annotations) == 0 {
+		return
+	}
+	a.annotationIdx = max(0, min(a.annotationIdx+delta, len(a.annotations)-1))
+}
+
+// buildAnnotationsContent builds the content for the Annotations tab,
+// listing the selected job's annotations grouped by file
+func (a *App) buildAnnotationsContent(maxWidth int) []string {
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return []string{"  Select a job"}
+	}
+
+	var content []string
+	content = append(content, "  Annotations: "+truncateString(job.Name, maxWidth-15))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	if a.annotationsJobID != job.ID {
+		if a.annotationsLoading {
+			return append(content, "  Loading annotations "+a.spinner.View())
+		}
+		return append(content, "  No annotations loaded")
+	}
+	if len(a.annotations) == 0 {
+		return append(content, "  No annotations")
+	}
+
+	if a.annotationsFocused {
+		content = append(content, "  (↑/↓ select, Enter open in $EDITOR, Esc back)")
+	} else {
+		content = append(content, "  (Enter to select annotations)")
+	}
+
+	path := ""
+	for i, ann := range a.annotations {
+		if i == 0 || ann.Path != path {
+			path = ann.Path
+			name := path
+			if name == "" {
+				name = "(no file)"
+			}
+			content = append(content, "")
+			content = append(content, "  "+LogGroupStyle.Render(truncateString(name, maxWidth-2)))
+		}
+
+		location := ""
+		if ann.StartLine > 0 {
+			location = strconv.Itoa(ann.StartLine) + ": "
+		}
+		message := ann.Message
+		if ann.Title != "" {
+			message = ann.Title + " - " + message
+		}
+		// Only the first line of multi-line messages fits in the list
+		message, _, _ = strings.Cut(message, "\n")
+		text := annotationIcon(ann.Level) + " " + truncateString(location+message, maxWidth-8)
+
+		switch {
+		case i == a.annotationIdx && a.annotationsFocused:
+			content = append(content, "  "+CursorStyle.Render(">")+" "+SelectedItemFocused.Render(text))
+		case i == a.annotationIdx:
+			content = append(content, "  "+SelectedItemUnfocused.Render("> "+text))
+		default:
+			content = append(content, "    "+NormalItem.Render(text))
+		}
+	}
+
+	return content
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestSortAnnotations(t *testing.T) {
+	annotations := []github.Annotation{
+		{Path: "b.go", StartLine: 3},
+		{Path: "a.go", StartLine: 20},
+		{Path: "a.go", StartLine: 5},
+	}
+
+	sorted := sortAnnotations(annotations)
+
+	var got []string
+	for _, a := range sorted {
+		got = append(got, a.Path+":"+strconv.Itoa(a.StartLine))
+	}
+	if want := "a.go:5,a.go:20,b.go:3"; strings.Join(got, ",") != want {
+		t.Errorf("sortAnnotations() = %q, want %q", strings.Join(got, ","), want)
+	}
+	if annotations[0].Path != "b.go" {
+		t.Error("sortAnnotations() should not modify its input")
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pkg", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ann := github.Annotation{Path: "pkg/main.go", StartLine: 12}
+
+	t.Run("editor with arguments", func(t *testing.T) {
+		cmd, err := editorCommand("code --wait", root, ann)
+		if err != nil {
+			t.Fatalf("editorCommand() unexpected error: %v", err)
+		}
+		want := []string{"code", "--wait", "+12", filepath.Join(root, "pkg", "main.go")}
+		if strings.Join(cmd.Args, " ") != strings.Join(want, " ") {
+			t.Errorf("Args = %v, want %v", cmd.Args, want)
+		}
+	})
+
+	t.Run("default editor", func(t *testing.T) {
+		cmd, err := editorCommand("", root, ann)
+		if err != nil {
+			t.Fatalf("editorCommand() unexpected error: %v", err)
+		}
+		if cmd.Args[0] != DefaultEditor {
+			t.Errorf("editor = %q, want %q", cmd.Args[0], DefaultEditor)
+		}
+	})
+
+	t.Run("no local checkout", func(t *testing.T) {
+		if _, err := editorCommand("vi", "", ann); err == nil {
+			t.Error("editorCommand() expected error without a local root")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := editorCommand("vi", root, github.Annotation{Path: "gone.go", StartLine: 1})
+		if err == nil || !strings.Contains(err.Error(), "gone.go") {
+			t.Errorf("editorCommand() error = %v, want file not found", err)
+		}
+	})
+
+	t.Run("path outside the repository", func(t *testing.T) {
+		outside := filepath.Join(filepath.Dir(root), "secret.txt")
+		if err := os.WriteFile(outside, []byte("secret\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = os.Remove(outside) })
+		for _, path := range []string{"../secret.txt", "pkg/../../secret.txt", ".."} {
+			_, err := editorCommand("vi", root, github.Annotation{Path: path, StartLine: 1})
+			if err == nil || !strings.Contains(err.Error(), "outside the repository") {
+				t.Errorf("editorCommand(%q) error = %v, want outside the repository", path, err)
+			}
+		}
+	})
+}
+
+func TestFetchAnnotations(t *testing.T) {
+	mock := newMockClient(&mockClientState{
+		annotations: []github.Annotation{{Path: "main.go", StartLine: 1, Level: "failure", Message: "boom"}},
+	})
+
+	msg := fetchAnnotations(context.Background(), mock, github.Repository{}, 42)()
+
+	result, ok := msg.(AnnotationsLoadedMsg)
+	if !ok {
+		t.Fatalf("expected AnnotationsLoadedMsg, got %T", msg)
+	}
+	if result.JobID != 42 || len(result.Annotations) != 1 || result.Err != nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if calls := mock.GetJobAnnotationsCalls(); len(calls) != 1 || calls[0].JobID != 42 {
+		t.Errorf("GetJobAnnotations calls = %+v, want one call for job 42", calls)
+	}
+}
+
+func TestApp_AnnotationsTab(t *testing.T) {
+	mock := newMockClient(nil)
+	app := New(WithClient(mock))
+	app.focusedPane = JobsPane
+	app.jobs.SetItems([]github.Job{
+		{ID: 10, Name: "lint", Status: "completed", Conclusion: "failure"},
+		{ID: 11, Name: "test", Status: "completed", Conclusion: "success"},
+	})
+
+	cmd := app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'6'}})
+	if app.detailTab != AnnotationsTab {
+		t.Fatalf("detailTab = %v, want AnnotationsTab", app.detailTab)
+	}
+	if cmd == nil || !app.annotationsLoading {
+		t.Fatal("switching to the annotations tab should fetch annotations")
+	}
+
+	// Annotations for another job are ignored
+	app.Update(AnnotationsLoadedMsg{JobID: 99, Annotations: []github.Annotation{{Path: "x.go"}}})
+	if len(app.annotations) != 0 {
+		t.Error("annotations of another job should be ignored")
+	}
+	if !app.annotationsLoading {
+		t.Error("annotations of another job should not end loading")
+	}
+
+	app.Update(AnnotationsLoadedMsg{JobID: 10, Annotations: []github.Annotation{
+		{Path: "b.go", StartLine: 7, Level: "warning", Message: "unused variable"},
+		{Path: "a.go", StartLine: 3, Level: "failure", Title: "golangci-lint", Message: "missing return\nmore details"},
+	}})
+
+	content := strings.Join(app.buildAnnotationsContent(80), "\n")
+	for _, want := range []string{"a.go", "3: golangci-lint - missing return", "b.go", "7: unused variable"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "more details") {
+		t.Error("only the first line of a message should be shown")
+	}
+	if strings.Index(content, "a.go") > strings.Index(content, "b.go") {
+		t.Error("annotations should be grouped by file in path order")
+	}
+
+	// Enter focuses the list, then up/down move the selection
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.annotationsFocused {
+		t.Fatal("Enter should focus the annotations")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.annotationIdx != 1 || app.jobs.SelectedIndex() != 0 {
+		t.Errorf("down should select the next annotation, got index %d and job %d", app.annotationIdx, app.jobs.SelectedIndex())
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.annotationIdx != 1 {
+		t.Errorf("annotation selection should stay in bounds, got %d", app.annotationIdx)
+	}
+
+	// Without a local checkout, Enter reports why the file cannot be opened
+	cmd = app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter on a focused annotation should return a command")
+	}
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.annotationsFocused {
+		t.Error("Esc should return focus to the jobs list")
+	}
+}
+
+func TestApp_AnnotationsTab_FollowsSelectedJob(t *testing.T) {
+	mock := newMockClient(nil)
+	app := New(WithClient(mock))
+	app.focusedPane = JobsPane
+	app.detailTab = AnnotationsTab
+	app.jobs.SetItems([]github.Job{
+		{ID: 10, Name: "lint", Status: "completed", Conclusion: "failure"},
+		{ID: 11, Name: "test", Status: "completed", Conclusion: "success"},
+	})
+	app.Update(AnnotationsLoadedMsg{JobID: 10, Annotations: []github.Annotation{{Path: "a.go"}, {Path: "b.go"}}})
+	app.annotationsFocused = true
+	app.annotationIdx = 1
+
+	// Selecting another job fetches its annotations and resets the cursor
+	app.jobs.SelectNext()
+	cmd := app.onJobSelectionChange()
+	if cmd == nil || !app.annotationsLoading {
+		t.Fatal("selecting another job should fetch its annotations")
+	}
+	if app.annotationsFocused || app.annotationIdx != 0 {
+		t.Errorf("annotation focus = %v, index = %d; want both reset", app.annotationsFocused, app.annotationIdx)
+	}
+
+	// Loading the jobs of another run does the same for its first job
+	app.Update(AnnotationsLoadedMsg{JobID: 11})
+	app.annotationsFocused = true
+	app.annotationIdx = 1
+	_, cmd = app.Update(JobsLoadedMsg{Jobs: []github.Job{{ID: 20, Name: "build", Status: "completed", Conclusion: "success"}}})
+	if cmd == nil || !app.annotationsLoading {
+		t.Fatal("loading jobs should fetch the annotations of the selected job")
+	}
+	if app.annotationsFocused || app.annotationIdx != 0 {
+		t.Errorf("annotation focus = %v, index = %d; want both reset", app.annotationsFocused, app.annotationIdx)
+	}
+
+	// Reloading the same jobs keeps the loaded annotations
+	app.Update(AnnotationsLoadedMsg{JobID: 20})
+	app.annotationsFocused = true
+	app.Update(JobsLoadedMsg{Jobs: []github.Job{{ID: 20, Name: "build", Status: "completed", Conclusion: "success"}}})
+	if app.annotationsLoading || !app.annotationsFocused {
+		t.Error("reloading the same job should keep its annotations")
+	}
+}
@@ -2,6 +2,8 @@
 package app
 
 import (
+	"context"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
@@ -28,8 +30,32 @@ type DetailTab int
 const (
 	LogsTab DetailTab = iota
 	InfoTab
+	TimelineTab
+	AnalyticsTab
+	DiffTab
+	AnnotationsTab
+	ProblemsTab
+	TestsTab
+	SummaryTab
 )
 
+// detailTabs lists the detail view tabs in the order shown in the tab header.
+// The position in this list is the number key that selects the tab.
+var detailTabs = []struct {
+	tab  DetailTab
+	name string
+}{
+	{InfoTab, "Info"},
+	{LogsTab, "Logs"},
+	{TimelineTab, "Timeline"},
+	{AnalyticsTab, "Analytics"},
+	{DiffTab, "Diff"},
+	{AnnotationsTab, "Annotations"},
+	{ProblemsTab, "Problems"},
+	{TestsTab, "Tests"},
+	{SummaryTab, "Summary"},
+}
+
 // Layout constants
 const (
 	// LeftPanelWidthRatio is the percentage of screen width for the left sidebar
@@ -48,6 +74,9 @@ const (
 	MinPanelHeight = 5
 	// FilterInputCharLimit is the maximum characters for the filter input
 	FilterInputCharLimit = 50
+	// GotoInputCharLimit is the maximum characters for the go-to-line input,
+	// long enough for a pasted permalink
+	GotoInputCharLimit = 200
 	// MinLogPaneWidth is the minimum width for log pane
 	MinLogPaneWidth = 20
 	// MinWorkflowsPaneWidth is the minimum width for workflows pane
@@ -85,6 +114,13 @@ type Clipboard interface {
 	WriteAll(text string) error
 }
 
+// LogCache stores the logs of completed jobs, which never change,
+// so they can be shown again without downloading them
+type LogCache interface {
+	Get(repo github.Repository, jobID int64) (string, bool)
+	Put(repo github.Repository, jobID int64, logs string) error
+}
+
 // realClipboard implements Clipboard using the system clipboard
 type realClipboard struct{}
 
@@ -105,7 +141,7 @@ type App struct {
 	detailTab   DetailTab
 	width       int
 	height      int
-	logView     *LogViewport
+	logView     *LogWindow
 
 	// State
 	loading bool
@@ -121,6 +157,10 @@ type App struct {
 	filtering   bool
 	filterInput textinput.Model
 
+	// Go-to-line prompt (: key)
+	gotoLine  bool
+	gotoInput textinput.Model
+
 	// Spinner
 	spinner spinner.Model
 
@@ -130,7 +170,22 @@ type App struct {
 	// Dependencies
 	client    github.Client
 	clipboard Clipboard
+	logCache  LogCache // Optional
+	redactor  *github.Redactor
+	matchers  *MatcherRegistry
 	keys      KeyMap
+	requests  *requestManager
+	logger    *slog.Logger
+
+	// What the token may do, withholding actions it lacks permission for
+	permissions github.Permissions
+
+	// Refresh the selected workflow's runs in the background
+	autoRefresh bool
+
+	// Generations of the latest runs and jobs requests, used to drop stale responses
+	runsGeneration uint64
+	jobsGeneration uint64
 
 	// Fullscreen log mode
 	fullscreenLog bool
@@ -140,9 +195,56 @@ type App struct {
 	mouseY int
 
 	// Step-selectable logs
-	parsedLogs      *ParsedLogs // Parsed log structure with steps
-	selectedStepIdx int         // -1 = "All logs", 0+ = specific step
-	stepListFocused bool        // Whether the step list has focus (vs log content)
+	parsedLogs      *ParsedLogs  // Parsed log structure with steps
+	logRedactions   int          // Number of values redacted from the logs
+	logColorMode    LogColorMode // How colors in the logs are displayed
+	selectedStepIdx int          // -1 = "All logs", 0+ = row of the step list
+	expandedGroups  map[int]bool // Expanded steps and groups, by their first line
+	stepListFocused bool         // Whether the step list has focus (vs log content)
+	lineNumbers     bool         // Whether log lines are numbered in a gutter
+
+	// Workflow analytics, keyed by workflow ID
+	analytics        map[int64]WorkflowStats
+	analyticsLoading bool
+	analyticsSort    AnalyticsSort
+
+	// Log diff between a marked job and the selected job
+	diffBase       *diffSide
+	diffOpts       LogDiffOptions
+	diffSideBySide bool
+	diffFocused    bool         // Whether up/down scroll the diff (vs the jobs list)
+	diffView       *LogViewport // Rendered diff
+	diffKey        diffViewKey  // Inputs of the rendered diff
+
+	// Check run annotations of the selected job, sorted by file and line
+	annotations        []github.Annotation
+	annotationsJobID   int64
+	annotationsLoading bool
+	annotationIdx      int
+	annotationsFocused bool
+	localRoot          string // Root of the local checkout, empty if unknown
+
+	// Check run summary of the selected job
+	summary        github.JobSummary
+	summaryJobID   int64
+	summaryLoading bool
+	summaryFocused bool           // Whether up/down scroll the summary (vs the jobs list)
+	summaryView    *LogViewport   // Rendered summary
+	summaryKey     summaryViewKey // Inputs of the rendered summary
+
+	// Selection in the problems recognized in the selected job's logs
+	problemIdx      int
+	problemsFocused bool
+
+	// Selection in the failed tests found in the selected job's logs
+	testIdx      int
+	testsFocused bool
+
+	// Failure summary of the selected run, shown full-screen
+	showFailureSummary    bool
+	failureSummary        *FailureSummary
+	failureSummaryLoading bool
+	failureView           *LogWindow
 }
 
 // Option is a functional option for App
@@ -169,12 +271,68 @@ func WithClipboard(cb Clipboard) Option {
 	}
 }
 
+// WithLogCache sets the cache for logs of completed jobs
+func WithLogCache(cache LogCache) Option {
+	return func(a *App) {
+		a.logCache = cache
+	}
+}
+
+// WithRedactor sets the redactor that removes secrets from logs before they
+// are shown or cached. By default only the built-in secret patterns apply.
+func WithRedactor(r *github.Redactor) Option {
+	return func(a *App) {
+		if r != nil {
+			a.redactor = r
+		}
+	}
+}
+
+// WithProblemMatchers sets the problem matchers that recognize diagnostics
+// in logs. By default only the built-in matchers apply.
+func WithProblemMatchers(r *MatcherRegistry) Option {
+	return func(a *App) {
+		if r != nil {
+			a.matchers = r
+		}
+	}
+}
+
+// WithLocalRoot sets the root directory of the local checkout of the
+// repository, used to open annotated files in an editor
+func WithLocalRoot(root string) Option {
+	return func(a *App) {
+		a.localRoot = root
+	}
+}
+
+// WithLogDiffOptions sets the masks the Diff tab starts with.
+// By default every mask is enabled.
+func WithLogDiffOptions(opts LogDiffOptions) Option {
+	return func(a *App) {
+		a.diffOpts = opts
+	}
+}
+
+// WithAutoRefresh refreshes the selected workflow's runs in the background,
+// as often as the API budget allows. By default data is only refreshed on
+// request.
+func WithAutoRefresh() Option {
+	return func(a *App) {
+		a.autoRefresh = true
+	}
+}
+
 // New creates a new App instance
 func New(opts ...Option) *App {
 	ti := textinput.New()
 	ti.Placeholder = "Filter..."
 	ti.CharLimit = FilterInputCharLimit
 
+	gi := textinput.New()
+	gi.Placeholder = "line or step:line"
+	gi.CharLimit = GotoInputCharLimit
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = RunningStyle
@@ -185,24 +343,39 @@ func New(opts ...Option) *App {
 		}),
 		runs: NewFilteredList(func(r github.Run, filter string) bool {
 			return strings.Contains(strings.ToLower(r.Branch), strings.ToLower(filter)) ||
-				strings.Contains(strings.ToLower(r.Actor), strings.ToLower(filter))
+				strings.Contains(strings.ToLower(r.Actor), strings.ToLower(filter)) ||
+				strings.Contains(strings.ToLower(r.DisplayTitle), strings.ToLower(filter)) ||
+				strings.HasPrefix(r.HeadSHA, strings.ToLower(filter))
 		}),
 		jobs: NewFilteredList(func(j github.Job, filter string) bool {
 			return strings.Contains(strings.ToLower(j.Name), strings.ToLower(filter))
 		}),
 		focusedPane:     WorkflowsPane,
-		logView:         NewLogViewport(DefaultLogViewportWidth, DefaultLogViewportHeight),
+		logView:         NewLogWindow(DefaultLogViewportWidth, DefaultLogViewportHeight),
 		filterInput:     ti,
+		gotoInput:       gi,
 		spinner:         s,
 		keys:            DefaultKeyMap(),
 		selectedStepIdx: -1, // -1 means "All logs"
+		expandedGroups:  map[int]bool{},
 		stepListFocused: true,
+		analytics:       make(map[int64]WorkflowStats),
+		diffOpts:        DefaultLogDiffOptions(),
+		diffView:        NewLogViewport(DefaultLogViewportWidth, DefaultLogViewportHeight),
+		summaryView:     NewLogViewport(DefaultLogViewportWidth, DefaultLogViewportHeight),
+		failureView:     NewLogWindow(DefaultLogViewportWidth, DefaultLogViewportHeight),
+		logger:          slog.New(slog.DiscardHandler),
+		redactor:        github.DefaultRedactor(),
+		matchers:        defaultMatchers,
 	}
 
 	for _, opt := range opts {
 		opt(a)
 	}
 
+	// Retries of fetches are logged with the rest of the debug log
+	a.requests = newRequestManager(github.ContextWithLogger(context.Background(), a.logger))
+
 	// Set default clipboard if not provided
 	if a.clipboard == nil {
 		a.clipboard = &realClipboard{}
@@ -216,11 +389,17 @@ func (a *App) Init() tea.Cmd {
 	return tea.Batch(
 		a.spinner.Tick,
 		a.fetchWorkflowsCmd(),
+		a.checkPermissionsCmd(),
+		a.pollCmd(time.Now()),
 	)
 }
 
 // Update implements tea.Model
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer a.logUpdate(msg, time.Now())
+	defer a.syncDiffView()
+	defer a.syncSummaryView()
+
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -239,7 +418,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
-		a.logView.SetSize(a.logPaneWidth(), a.logPaneHeight())
+		a.logView.SetSize(a.logPaneWidth()-ContentPadding, a.logPaneHeight())
+		a.diffView.SetSize(a.logPaneWidth(), a.logPaneHeight())
+		a.summaryView.SetSize(a.logPaneWidth(), a.logPaneHeight())
+		a.failureView.SetSize(a.failureViewSize())
 
 	case WorkflowsLoadedMsg:
 		a.loading = false
@@ -255,10 +437,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case RunsLoadedMsg:
+		// Drop responses for a previous workflow or superseded by a newer request
+		if !a.isCurrentRunsResponse(msg) {
+			break
+		}
 		a.loading = false
 		if msg.Err != nil {
 			a.err = msg.Err
 		} else {
+			// A successful load means an earlier failure has passed
+			a.err = nil
 			a.runs.SetItems(msg.Runs)
 			if a.runs.Len() > 0 {
 				if run, ok := a.runs.Selected(); ok {
@@ -268,6 +456,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case JobsLoadedMsg:
+		// Drop responses for a previous run or superseded by a newer request
+		if !a.isCurrentJobsResponse(msg) {
+			break
+		}
 		a.loading = false
 		if msg.Err != nil {
 			a.err = msg.Err
@@ -280,6 +472,17 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else if !job.IsCompleted() {
 					a.logView.SetContent(jobStatusMessage(job))
 				}
+				// The annotations tab shows the newly selected job
+				if a.detailTab == AnnotationsTab && job.ID != a.annotationsJobID {
+					a.annotationIdx = 0
+					a.annotationsFocused = false
+					cmds = append(cmds, a.fetchAnnotationsCmd())
+				}
+				// So does the summary tab
+				if a.detailTab == SummaryTab && job.ID != a.summaryJobID {
+					a.summaryFocused = false
+					cmds = append(cmds, a.fetchSummaryCmd())
+				}
 			}
 		}
 
@@ -291,6 +494,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
+		a.logRedactions = msg.Redactions
 		if msg.Err != nil {
 			a.parsedLogs = nil
 			// Don't show error for incomplete jobs - logs aren't available yet
@@ -301,10 +505,79 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			// Don't set a.err - avoid showing error in status bar
 		} else {
-			a.parsedLogs = ParseLogs(msg.Logs)
+			if msg.Parsed != nil {
+				a.parsedLogs = msg.Parsed
+			} else {
+				a.parsedLogs = ParseLogs(msg.Logs)
+			}
+			a.parsedLogs.AlignSteps(job.Steps)
 			a.updateLogViewContent()
 		}
 
+	case AnnotationsLoadedMsg:
+		// Only keep annotations for the currently selected job. A stale
+		// response leaves the loading state to the request for that job.
+		job, ok := a.jobs.Selected()
+		if !ok || job.ID != msg.JobID {
+			break
+		}
+		a.annotationsLoading = false
+		if msg.Err != nil {
+			a.err = msg.Err
+		} else {
+			a.annotations = sortAnnotations(msg.Annotations)
+			a.annotationsJobID = msg.JobID
+			a.annotationIdx = 0
+		}
+
+	case JobSummaryLoadedMsg:
+		// Only keep the summary of the currently selected job
+		job, ok := a.jobs.Selected()
+		if !ok || job.ID != msg.JobID {
+			break
+		}
+		a.summaryLoading = false
+		if msg.Err != nil {
+			a.err = msg.Err
+		} else {
+			a.summary = msg.Summary
+			a.summaryJobID = msg.JobID
+			a.summaryKey = summaryViewKey{}
+		}
+
+	case FailureSummaryLoadedMsg:
+		// Only keep the summary of the run it was opened for
+		if a.failureSummary == nil || a.failureSummary.Run.ID != msg.RunID {
+			break
+		}
+		a.failureSummaryLoading = false
+		if msg.Err != nil {
+			a.failureView.SetContent("Failed to load jobs: " + msg.Err.Error())
+			break
+		}
+		a.failureSummary = &msg.Summary
+		a.failureView.GotoTop()
+		a.failureView.SetLines(buildFailureSummaryLines(msg.Summary), nil)
+
+	case PermissionsCheckedMsg:
+		// If the check fails all actions stay available, as they were before
+		if msg.Err == nil {
+			a.permissions = msg.Permissions
+		}
+
+	case EditorClosedMsg:
+		if msg.Err != nil {
+			a.err = msg.Err
+		}
+
+	case AnalyticsLoadedMsg:
+		a.analyticsLoading = false
+		if msg.Err != nil {
+			a.err = msg.Err
+		} else {
+			a.analytics[msg.WorkflowID] = msg.Stats
+		}
+
 	case RunCancelledMsg:
 		if msg.Err != nil {
 			a.err = msg.Err
@@ -337,6 +610,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, a.refreshCurrentWorkflow())
 		}
 
+	case TickMsg:
+		// Refresh in the background, unless a request is in flight or the
+		// API asked to back off, then schedule the next poll based on the
+		// budget
+		rl := a.rateLimit()
+		if !a.loading && !rl.RetryAt.After(msg.Time) {
+			if cmd := a.refreshCurrentWorkflow(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		cmds = append(cmds, a.pollCmd(msg.Time))
+
+	case FlashMsg:
+		a.flashMsg = msg.Message
+
 	case FlashClearMsg:
 		a.flashMsg = ""
 
@@ -363,6 +651,10 @@ func (a *App) View() string {
 		return a.renderHelp()
 	}
 
+	if a.showFailureSummary {
+		return a.renderFailureSummary()
+	}
+
 	if a.showConfirm {
 		return a.renderConfirmDialog()
 	}
@@ -432,28 +724,84 @@ func (a *App) fetchWorkflowsCmd() tea.Cmd {
 		return nil
 	}
 	a.loading = true
-	return fetchWorkflows(a.client, a.repo)
+	return fetchWorkflows(a.requests.start(requestWorkflows), a.client, a.repo)
 }
 
 func (a *App) fetchRunsCmd(workflowID int64) tea.Cmd {
 	if a.client == nil {
 		return nil
 	}
-	return fetchRuns(a.client, a.repo, workflowID)
+	a.runsGeneration++
+	return fetchRuns(a.requests.start(requestRuns), a.client, a.repo, workflowID, a.runsGeneration)
 }
 
 func (a *App) fetchJobsCmd(runID int64) tea.Cmd {
 	if a.client == nil {
 		return nil
 	}
-	return fetchJobs(a.client, a.repo, runID)
+	a.jobsGeneration++
+	return fetchJobs(a.requests.start(requestJobs), a.client, a.repo, runID, a.jobsGeneration)
+}
+
+// fetchAnalyticsCmd fetches analytics for the selected workflow unless they
+// are already loaded or loading
+func (a *App) fetchAnalyticsCmd() tea.Cmd {
+	if a.client == nil || a.analyticsLoading {
+		return nil
+	}
+	wf, ok := a.workflows.Selected()
+	if !ok {
+		return nil
+	}
+	if _, loaded := a.analytics[wf.ID]; loaded {
+		return nil
+	}
+	a.analyticsLoading = true
+	return fetchAnalytics(a.requests.start(requestAnalytics), a.client, a.repo, wf.ID)
+}
+
+// fetchAnnotationsCmd fetches annotations for the selected job unless they
+// are already loaded
+func (a *App) fetchAnnotationsCmd() tea.Cmd {
+	if a.client == nil {
+		return nil
+	}
+	job, ok := a.jobs.Selected()
+	if !ok || job.ID == a.annotationsJobID {
+		return nil
+	}
+	a.annotationsLoading = true
+	return fetchAnnotations(a.requests.start(requestAnnotations), a.client, a.repo, job.ID)
+}
+
+// checkPermissionsCmd checks whether the token may change runs, so actions
+// it can't perform are marked read-only
+func (a *App) checkPermissionsCmd() tea.Cmd {
+	if a.client == nil {
+		return nil
+	}
+	return checkPermissions(a.requests.start(requestPermissions), a.client, a.repo)
+}
+
+// fetchSummaryCmd fetches the check run summary of the selected job unless
+// it is already loaded
+func (a *App) fetchSummaryCmd() tea.Cmd {
+	if a.client == nil {
+		return nil
+	}
+	job, ok := a.jobs.Selected()
+	if !ok || job.ID == a.summaryJobID {
+		return nil
+	}
+	a.summaryLoading = true
+	return fetchJobSummary(a.requests.start(requestSummary), a.client, a.repo, job.ID)
 }
 
 func (a *App) fetchLogsCmd(jobID int64) tea.Cmd {
 	if a.client == nil {
 		return nil
 	}
-	return fetchLogs(a.client, a.repo, jobID)
+	return fetchLogs(a.requests.start(requestLogs), a.client, a.logCache, a.redactor, a.matchers, a.repo, jobID)
 }
 
 // formatRunNumber formats a run ID for display
@@ -462,19 +810,20 @@ func formatRunNumber(id int64) string {
 }
 
 // Run starts the TUI application
-func Run(client github.Client, repo github.Repository) error {
+func Run(client github.Client, repo github.Repository, opts ...Option) error {
 	// Display startup banner
 	PrintBanner()
 
-	app := New(
+	app := New(append([]Option{
 		WithClient(client),
 		WithRepository(repo),
-	)
+	}, opts...)...)
 
 	p := tea.NewProgram(app,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
 	_, err := p.Run()
+	app.requests.cancelAll()
 	return err
 }
@@ -2,6 +2,7 @@ package app
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -249,6 +250,23 @@ func TestApp_Update_LogsLoadedMsg(t *testing.T) {
 	}
 }
 
+func TestApp_Update_LogsLoadedMsg_ShowsRedactionCount(t *testing.T) {
+	app := New()
+	app.jobs.SetItems([]github.Job{{ID: 1, Name: "build", Status: "completed"}})
+
+	app.Update(LogsLoadedMsg{JobID: 1, Logs: "password [REDACTED]", Redactions: 3})
+
+	header := app.buildLogsContent(80)[0]
+	if !strings.Contains(header, "3 redacted") {
+		t.Errorf("log header = %q, want redaction count", header)
+	}
+
+	app.Update(LogsLoadedMsg{JobID: 1, Logs: "clean"})
+	if header := app.buildLogsContent(80)[0]; strings.Contains(header, "redacted") {
+		t.Errorf("log header = %q, want no redaction count", header)
+	}
+}
+
 func TestApp_Update_LogsLoadedMsg_ErrorForIncompleteJob(t *testing.T) {
 	app := New()
 	app.jobs.SetItems([]github.Job{{ID: 1, Name: "build", Status: "in_progress"}})
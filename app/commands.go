@@ -2,6 +2,9 @@ package app
 
 import (
 	"context"
+	"io"
+	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,14 +14,18 @@ import (
 // fetchWorkflows creates a command to fetch workflows.
 // It captures the client and repo to avoid race conditions.
 // Retries on transient errors (rate limits, server errors).
-func fetchWorkflows(client github.Client, repo github.Repository) tea.Cmd {
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchWorkflows(ctx context.Context, client github.Client, repo github.Repository) tea.Cmd {
 	return func() tea.Msg {
 		var workflows []github.Workflow
-		err := github.RetryWithBackoff(context.Background(), 3, func() error {
+		err := github.RetryWithBackoff(ctx, 3, func() error {
 			var e error
-			workflows, e = client.ListWorkflows(context.Background(), repo)
+			workflows, e = client.ListWorkflows(ctx, repo)
 			return e
 		})
+		if ctx.Err() != nil {
+			return nil
+		}
 		return WorkflowsLoadedMsg{
 			Workflows: workflows,
 			Err:       err,
@@ -28,63 +35,321 @@ func fetchWorkflows(client github.Client, repo github.Repository) tea.Cmd {
 
 // fetchRuns creates a command to fetch runs for a workflow.
 // It captures the client, repo, and workflowID to avoid race conditions.
+// The response carries workflowID and generation so stale ones can be dropped.
 // Retries on transient errors (rate limits, server errors).
-func fetchRuns(client github.Client, repo github.Repository, workflowID int64) tea.Cmd {
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchRuns(ctx context.Context, client github.Client, repo github.Repository, workflowID int64, generation uint64) tea.Cmd {
 	return func() tea.Msg {
 		opts := &github.ListRunsOpts{
 			WorkflowID: workflowID,
 		}
 		var runs []github.Run
-		err := github.RetryWithBackoff(context.Background(), 3, func() error {
+		err := github.RetryWithBackoff(ctx, 3, func() error {
 			var e error
-			runs, e = client.ListRuns(context.Background(), repo, opts)
+			runs, e = client.ListRuns(ctx, repo, opts)
 			return e
 		})
+		if ctx.Err() != nil {
+			return nil
+		}
 		return RunsLoadedMsg{
-			Runs: runs,
-			Err:  err,
+			WorkflowID: workflowID,
+			Generation: generation,
+			Runs:       runs,
+			Err:        err,
 		}
 	}
 }
 
 // fetchJobs creates a command to fetch jobs for a run.
 // It captures the client, repo, and runID to avoid race conditions.
+// The response carries runID and generation so stale ones can be dropped.
 // Retries on transient errors (rate limits, server errors).
-func fetchJobs(client github.Client, repo github.Repository, runID int64) tea.Cmd {
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchJobs(ctx context.Context, client github.Client, repo github.Repository, runID int64, generation uint64) tea.Cmd {
 	return func() tea.Msg {
 		var jobs []github.Job
-		err := github.RetryWithBackoff(context.Background(), 3, func() error {
+		err := github.RetryWithBackoff(ctx, 3, func() error {
 			var e error
-			jobs, e = client.ListJobs(context.Background(), repo, runID)
+			jobs, e = client.ListJobs(ctx, repo, runID)
 			return e
 		})
+		if ctx.Err() != nil {
+			return nil
+		}
 		return JobsLoadedMsg{
-			Jobs: jobs,
-			Err:  err,
+			RunID:      runID,
+			Generation: generation,
+			Jobs:       jobs,
+			Err:        err,
 		}
 	}
 }
 
 // fetchLogs creates a command to fetch logs for a job.
+// It captures the client, cache, redactor, matchers, repo, and jobID to avoid race conditions.
+// Logs are loaded with loadJobLogs, off the UI goroutine.
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchLogs(ctx context.Context, client github.Client, cache LogCache, redactor *github.Redactor, matchers *MatcherRegistry, repo github.Repository, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		parsed, redactions, err := loadJobLogs(ctx, client, cache, redactor, matchers, repo, jobID)
+		if ctx.Err() != nil {
+			return nil
+		}
+		msg := LogsLoadedMsg{JobID: jobID, Parsed: parsed, Redactions: redactions, Err: err}
+		if parsed != nil {
+			msg.Logs = parsed.RawLogs
+		}
+		return msg
+	}
+}
+
+// loadJobLogs fetches and parses the logs of a job, returning them with the
+// number of values redacted. Logs are redacted to remove secrets and parsed
+// in a single pass, with the default redactor if redactor is nil. Problems
+// are recognized with matchers, or the built-in matchers if it is nil. If
+// cache is not nil, cached logs are served without a request and downloaded
+// logs are stored, already redacted, for next time. Cached logs are redacted
+// again, as the rules may have changed.
+// Retries on transient errors (rate limits, server errors) until the
+// download starts.
+func loadJobLogs(ctx context.Context, client github.Client, cache LogCache, redactor *github.Redactor, matchers *MatcherRegistry, repo github.Repository, jobID int64) (*ParsedLogs, int, error) {
+	if redactor == nil {
+		redactor = github.DefaultRedactor()
+	}
+	if cache != nil {
+		if logs, ok := cache.Get(repo, jobID); ok {
+			stream := redactor.NewStream()
+			parsed, err := parseRedactedLogs(strings.NewReader(logs), stream, matchers)
+			// Count the values redacted when the logs were cached too
+			return parsed, stream.Count() + strings.Count(logs, github.RedactionPlaceholder), err
+		}
+	}
+
+	var logs io.ReadCloser
+	err := github.RetryWithBackoff(ctx, 3, func() error {
+		var e error
+		logs, e = client.GetJobLogs(ctx, repo, jobID)
+		return e
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = logs.Close() }()
+
+	// The download is redacted and parsed as it arrives
+	stream := redactor.NewStream()
+	parsed, err := parseRedactedLogs(logs, stream, matchers)
+	if err == nil && cache != nil {
+		// Caching is best effort; the logs are shown either way
+		_ = cache.Put(repo, jobID, parsed.RawLogs)
+	}
+	return parsed, stream.Count(), err
+}
+
+// fetchAnnotations creates a command to fetch check run annotations for a job.
 // It captures the client, repo, and jobID to avoid race conditions.
-// Logs are sanitized to remove potential secrets before display.
 // Retries on transient errors (rate limits, server errors).
-func fetchLogs(client github.Client, repo github.Repository, jobID int64) tea.Cmd {
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchAnnotations(ctx context.Context, client github.Client, repo github.Repository, jobID int64) tea.Cmd {
 	return func() tea.Msg {
-		var logs string
-		err := github.RetryWithBackoff(context.Background(), 3, func() error {
+		var annotations []github.Annotation
+		err := github.RetryWithBackoff(ctx, 3, func() error {
 			var e error
-			logs, e = client.GetJobLogs(context.Background(), repo, jobID)
+			annotations, e = client.GetJobAnnotations(ctx, repo, jobID)
 			return e
 		})
-		if err == nil {
-			logs = github.SanitizeLogs(logs)
+		if ctx.Err() != nil {
+			return nil
 		}
-		return LogsLoadedMsg{
-			JobID: jobID,
-			Logs:  logs,
-			Err:   err,
+		return AnnotationsLoadedMsg{
+			JobID:       jobID,
+			Annotations: annotations,
+			Err:         err,
+		}
+	}
+}
+
+// fetchJobSummary creates a command to fetch the check run summary of a job,
+// such as the Markdown it wrote to $GITHUB_STEP_SUMMARY.
+// Retries on transient errors (rate limits, server errors).
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchJobSummary(ctx context.Context, client github.Client, repo github.Repository, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		var summary github.JobSummary
+		err := github.RetryWithBackoff(ctx, 3, func() error {
+			var e error
+			summary, e = client.GetJobSummary(ctx, repo, jobID)
+			return e
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		return JobSummaryLoadedMsg{
+			JobID:   jobID,
+			Summary: summary,
+			Err:     err,
+		}
+	}
+}
+
+// checkPermissions creates a command to check whether the token may change
+// workflow runs, so actions it can't perform are withheld up front.
+// Retries on transient errors (rate limits, server errors).
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func checkPermissions(ctx context.Context, client github.Client, repo github.Repository) tea.Cmd {
+	return func() tea.Msg {
+		var perms github.Permissions
+		err := github.RetryWithBackoff(ctx, 3, func() error {
+			var e error
+			perms, e = client.CheckPermissions(ctx, repo)
+			return e
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		return PermissionsCheckedMsg{Permissions: perms, Err: err}
+	}
+}
+
+// analyticsConcurrency is the number of runs whose jobs are fetched in parallel
+const analyticsConcurrency = 4
+
+// failureSummaryConcurrency is the number of failed jobs whose logs are fetched in parallel
+const failureSummaryConcurrency = 4
+
+// fetchAnalytics creates a command to fetch the last completed runs of a workflow,
+// page by page, and the jobs of all their attempts, and compute statistics.
+// It captures the client, repo, and workflowID to avoid race conditions.
+// Retries on transient errors (rate limits, server errors).
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchAnalytics(ctx context.Context, client github.Client, repo github.Repository, workflowID int64) tea.Cmd {
+	return func() tea.Msg {
+		var runs []github.Run
+		for page := 1; len(runs) < AnalyticsRunCount; page++ {
+			opts := &github.ListRunsOpts{
+				WorkflowID: workflowID,
+				Status:     "completed",
+				PerPage:    AnalyticsPageSize,
+				Page:       page,
+			}
+			var batch []github.Run
+			err := github.RetryWithBackoff(ctx, 3, func() error {
+				var e error
+				batch, e = client.ListRuns(ctx, repo, opts)
+				return e
+			})
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return AnalyticsLoadedMsg{WorkflowID: workflowID, Err: err}
+			}
+			runs = append(runs, batch...)
+			if len(batch) < AnalyticsPageSize {
+				break
+			}
+		}
+		if len(runs) > AnalyticsRunCount {
+			runs = runs[:AnalyticsRunCount]
+		}
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			firstErr error
+		)
+		jobs := make(map[int64][]github.Job, len(runs))
+		sem := make(chan struct{}, analyticsConcurrency)
+		for _, run := range runs {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(runID int64) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				var runJobs []github.Job
+				err := github.RetryWithBackoff(ctx, 3, func() error {
+					var e error
+					runJobs, e = client.ListJobsAllAttempts(ctx, repo, runID)
+					return e
+				})
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					return
+				}
+				jobs[runID] = runJobs
+			}(run.ID)
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if firstErr != nil {
+			return AnalyticsLoadedMsg{WorkflowID: workflowID, Err: firstErr}
+		}
+
+		return AnalyticsLoadedMsg{
+			WorkflowID: workflowID,
+			Stats:      computeWorkflowStats(runs, jobs),
+		}
+	}
+}
+
+// fetchFailureSummary creates a command to fetch the jobs of a failed run and,
+// concurrently, the logs of its failed jobs, and summarize why they failed.
+// It captures the client, cache, redactor, matchers, repo, and run to avoid race conditions.
+// Logs that cannot be loaded are reported in the summary of their job.
+// Retries on transient errors (rate limits, server errors).
+// Sends no message if ctx is cancelled, as the result is no longer wanted.
+func fetchFailureSummary(ctx context.Context, client github.Client, cache LogCache, redactor *github.Redactor, matchers *MatcherRegistry, repo github.Repository, run github.Run) tea.Cmd {
+	return func() tea.Msg {
+		var jobs []github.Job
+		err := github.RetryWithBackoff(ctx, 3, func() error {
+			var e error
+			jobs, e = client.ListJobs(ctx, repo, run.ID)
+			return e
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return FailureSummaryLoadedMsg{RunID: run.ID, Err: err}
+		}
+
+		var failed []github.Job
+		for _, job := range jobs {
+			if isFailedJob(job) {
+				failed = append(failed, job)
+			}
+		}
+
+		// Each goroutine writes its own entry, so no lock is needed
+		summary := FailureSummary{Run: run, Jobs: make([]JobFailure, len(failed))}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, failureSummaryConcurrency)
+		for i, job := range failed {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				parsed, _, err := loadJobLogs(ctx, client, cache, redactor, matchers, repo, job.ID)
+				if err != nil && parsed == nil {
+					summary.Jobs[i] = JobFailure{Job: job, Err: err}
+					return
+				}
+				summary.Jobs[i] = summarizeJobFailure(job, parsed)
+			}()
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return nil
 		}
+		return FailureSummaryLoadedMsg{RunID: run.ID, Summary: summary}
 	}
 }
 
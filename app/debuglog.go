@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithLogger sets the logger that receives a debug log of every message
+// processed by Update. By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(a *App) {
+		if logger != nil {
+			a.logger = logger
+		}
+	}
+}
+
+// logUpdate logs a message processed by Update and how long it took.
+// Spinner frames are skipped, as they arrive many times a second and carry
+// no information. Log contents and typed text are never logged.
+func (a *App) logUpdate(msg tea.Msg, start time.Time) {
+	if _, ok := msg.(spinner.TickMsg); ok {
+		return
+	}
+	attrs := append([]slog.Attr{
+		slog.String("type", fmt.Sprintf("%T", msg)),
+		slog.Duration("took", time.Since(start)),
+	}, a.msgAttrs(msg)...)
+	a.logger.LogAttrs(context.Background(), slog.LevelDebug, "update", attrs...)
+}
+
+// msgAttrs returns the fields of a message worth logging
+func (a *App) msgAttrs(msg tea.Msg) []slog.Attr {
+	var attrs []slog.Attr
+	var err error
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+		if a.filtering && msg.Type == tea.KeyRunes {
+			key = "<text>"
+		}
+		attrs = append(attrs, slog.String("key", key))
+	case tea.WindowSizeMsg:
+		attrs = append(attrs, slog.Int("width", msg.Width), slog.Int("height", msg.Height))
+	case WorkflowsLoadedMsg:
+		attrs = append(attrs, slog.Int("count", len(msg.Workflows)))
+		err = msg.Err
+	case RunsLoadedMsg:
+		attrs = append(attrs,
+			slog.Int64("workflow_id", msg.WorkflowID),
+			slog.Uint64("generation", msg.Generation),
+			slog.Int("count", len(msg.Runs)))
+		err = msg.Err
+	case JobsLoadedMsg:
+		attrs = append(attrs,
+			slog.Int64("run_id", msg.RunID),
+			slog.Uint64("generation", msg.Generation),
+			slog.Int("count", len(msg.Jobs)))
+		err = msg.Err
+	case LogsLoadedMsg:
+		attrs = append(attrs, slog.Int64("job_id", msg.JobID), slog.Int("bytes", len(msg.Logs)))
+		err = msg.Err
+	case AnnotationsLoadedMsg:
+		attrs = append(attrs, slog.Int64("job_id", msg.JobID), slog.Int("count", len(msg.Annotations)))
+		err = msg.Err
+	case AnalyticsLoadedMsg:
+		attrs = append(attrs, slog.Int64("workflow_id", msg.WorkflowID), slog.Int("runs", msg.Stats.Runs))
+		err = msg.Err
+	case PermissionsCheckedMsg:
+		attrs = append(attrs, slog.Bool("checked", msg.Permissions.Checked),
+			slog.Bool("actions_write", msg.Permissions.ActionsWrite), slog.Any("scopes", msg.Permissions.Scopes))
+		err = msg.Err
+	case RunCancelledMsg:
+		attrs = append(attrs, slog.Int64("run_id", msg.RunID))
+		err = msg.Err
+	case RunRerunMsg:
+		attrs = append(attrs, slog.Int64("run_id", msg.RunID))
+		err = msg.Err
+	case RerunFailedJobsMsg:
+		attrs = append(attrs, slog.Int64("run_id", msg.RunID))
+		err = msg.Err
+	case WorkflowTriggeredMsg:
+		attrs = append(attrs, slog.String("workflow", msg.Workflow))
+		err = msg.Err
+	case EditorClosedMsg:
+		err = msg.Err
+	case FlashMsg:
+		attrs = append(attrs, slog.String("message", msg.Message))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	return attrs
+}
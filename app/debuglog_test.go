@@ -0,0 +1,57 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestApp_LogsUpdateMessages(t *testing.T) {
+	var buf bytes.Buffer
+	app := New(WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	app.Update(RunsLoadedMsg{WorkflowID: 7, Generation: 2, Err: errors.New("boom")})
+	app.Update(LogsLoadedMsg{JobID: 9, Logs: "ghp_secret output"})
+	app.Update(spinner.TickMsg{})
+	app.filtering = true
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines (spinner ticks skipped), got %d: %s", len(lines), buf.String())
+	}
+
+	var runs map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &runs); err != nil {
+		t.Fatal(err)
+	}
+	if runs["msg"] != "update" || runs["type"] != "app.RunsLoadedMsg" ||
+		runs["workflow_id"] != float64(7) || runs["generation"] != float64(2) || runs["error"] != "boom" {
+		t.Errorf("unexpected log entry %v", runs)
+	}
+	if _, ok := runs["took"]; !ok {
+		t.Error("processing time should be logged")
+	}
+
+	if strings.Contains(buf.String(), "ghp_secret") {
+		t.Error("log contents should not be logged")
+	}
+	if !strings.Contains(lines[1], `"bytes":17`) {
+		t.Errorf("logs entry should record the size, got %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"key":"<text>"`) {
+		t.Errorf("typed filter text should be hidden, got %s", lines[2])
+	}
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	app := New(WithLogger(nil))
+	// Must not panic
+	app.Update(FlashClearMsg{})
+}
@@ -0,0 +1,300 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// FailureContextLines is the number of lines shown before each error
+const FailureContextLines = 3
+
+// FailureMaxErrors is the number of errors shown for each failed job
+const FailureMaxErrors = 5
+
+// FailureExcerpt is an error logged by a failed job, with the lines logged
+// before it
+type FailureExcerpt struct {
+	Context []string // Lines logged before the error, without timestamps
+	Message string   // Message of the ##[error] line
+}
+
+// JobFailure explains why a job failed
+type JobFailure struct {
+	Job    github.Job
+	Step   string           // Name of the failing step, empty if unknown
+	Errors []FailureExcerpt // First errors logged by the job
+	Err    error            // Why the logs could not be loaded
+}
+
+// FailureSummary explains why the jobs of a run failed
+type FailureSummary struct {
+	Run  github.Run
+	Jobs []JobFailure
+}
+
+// isFailedJob reports whether a job failed, as opposed to being cancelled
+// or skipped
+func isFailedJob(job github.Job) bool {
+	return job.Conclusion == "failure" || job.Conclusion == "timed_out"
+}
+
+// summarizeJobFailure finds the failing step of a job and the errors in its
+// logs
+func summarizeJobFailure(job github.Job, parsed *ParsedLogs) JobFailure {
+	f := JobFailure{Job: job}
+	for _, s := range job.Steps {
+		if s.Conclusion == "failure" {
+			f.Step = s.Name
+			break
+		}
+	}
+	if parsed == nil {
+		return f
+	}
+	// Align a copy, so logs shared with other readers are left as they are
+	aligned := *parsed
+	aligned.AlignSteps(job.Steps)
+	parsed = &aligned
+
+	for i, line := range parsed.AllLines {
+		text := logText(line)
+		message, ok := strings.CutPrefix(text, "##[error]")
+		if !ok {
+			continue
+		}
+		if f.Step == "" {
+			for _, s := range parsed.Steps {
+				if s.StartLine <= i && i <= s.EndLine {
+					f.Step = s.Name
+					break
+				}
+			}
+		}
+		f.Errors = append(f.Errors, FailureExcerpt{
+			Context: failureContext(parsed.AllLines, i),
+			Message: message,
+		})
+		if len(f.Errors) == FailureMaxErrors {
+			break
+		}
+	}
+	return f
+}
+
+// failureContext returns up to FailureContextLines lines logged before line
+// i, skipping blank lines and workflow commands and stopping at an earlier
+// error
+func failureContext(lines []string, i int) []string {
+	var context []string
+	for j := i - 1; j >= 0 && len(context) < FailureContextLines; j-- {
+		text := logText(lines[j])
+		if strings.HasPrefix(text, "##[error]") {
+			break
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "##[") {
+			continue
+		}
+		context = append(context, text)
+	}
+	// Collected backwards
+	for l, r := 0, len(context)-1; l < r; l, r = l+1, r-1 {
+		context[l], context[r] = context[r], context[l]
+	}
+	return context
+}
+
+// jobURL returns the web URL of a job of the run
+func (s FailureSummary) jobURL(job github.Job) string {
+	return runJobURL(s.Run, job.ID)
+}
+
+// title returns the title of the summary, naming the run
+func (s FailureSummary) title() string {
+	return s.Run.Name + " #" + strconv.Itoa(s.Run.RunNumber)
+}
+
+// Markdown formats the summary as Markdown, for a pull request comment
+func (s FailureSummary) Markdown() string {
+	var b strings.Builder
+	b.WriteString("### ❌ " + s.title() + " failed\n\n")
+	run := "Run #" + strconv.Itoa(s.Run.RunNumber)
+	if s.Run.URL != "" {
+		run = "[" + run + "](" + s.Run.URL + ")"
+	}
+	b.WriteString(run)
+	if s.Run.Branch != "" {
+		b.WriteString(" on `" + s.Run.Branch + "`")
+	}
+	if len(s.Run.HeadSHA) >= 7 {
+		b.WriteString(" (`" + s.Run.HeadSHA[:7] + "`)")
+	}
+	b.WriteString("\n")
+
+	if len(s.Jobs) == 0 {
+		b.WriteString("\nNo failed jobs.\n")
+	}
+	for _, f := range s.Jobs {
+		name := "**" + f.Job.Name + "**"
+		if url := s.jobURL(f.Job); url != "" {
+			name = "[" + name + "](" + url + ")"
+		}
+		b.WriteString("\n#### " + name)
+		if f.Step != "" {
+			b.WriteString(" — step `" + f.Step + "`")
+		}
+		b.WriteString("\n\n")
+
+		switch {
+		case f.Err != nil:
+			b.WriteString("_Logs unavailable: " + f.Err.Error() + "_\n")
+			continue
+		case len(f.Errors) == 0:
+			b.WriteString("_No errors found in the logs._\n")
+			continue
+		}
+
+		var lines []string
+		for i, e := range f.Errors {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, e.Context...)
+			lines = append(lines, "Error: "+e.Message)
+		}
+		// The fence must be longer than any backtick run in the logs
+		fence := "```"
+		for strings.Contains(strings.Join(lines, "\n"), fence) {
+			fence += "`"
+		}
+		b.WriteString(fence + "text\n" + strings.Join(lines, "\n") + "\n" + fence + "\n")
+	}
+	return b.String()
+}
+
+// failureViewSize returns the size of the failure summary content, inside
+// the border, title and hint of the full-screen view
+func (a *App) failureViewSize() (width, height int) {
+	return max(a.width-4, 0), max(a.height-StatusBarHeight-4, 1)
+}
+
+// openFailureSummary shows the failure summary of the selected run,
+// fetching its jobs and the logs of its failed jobs
+func (a *App) openFailureSummary() tea.Cmd {
+	run, ok := a.runs.Selected()
+	if !ok {
+		return nil
+	}
+	if !run.IsFailed() {
+		return flashMessage("Run has not failed", FlashDurationInfo)
+	}
+	a.showFailureSummary = true
+	a.failureSummary = &FailureSummary{Run: run}
+	a.failureSummaryLoading = true
+	a.failureView.SetSize(a.failureViewSize())
+	a.failureView.SetContent("Loading jobs and logs...")
+	return fetchFailureSummary(a.requests.start(requestFailureSummary), a.client, a.logCache, a.redactor, a.matchers, a.repo, run)
+}
+
+// closeFailureSummary hides the failure summary, stopping its requests
+func (a *App) closeFailureSummary() {
+	a.showFailureSummary = false
+	a.cancelRequests(requestFailureSummary)
+}
+
+// yankFailureSummary copies the failure summary as Markdown
+func (a *App) yankFailureSummary() tea.Cmd {
+	if a.failureSummary == nil || a.failureSummaryLoading {
+		return nil
+	}
+	if err := a.clipboard.WriteAll(a.failureSummary.Markdown()); err != nil {
+		return flashMessage("Clipboard not available", FlashDurationInfo)
+	}
+	return flashMessage("Copied failure summary as Markdown", FlashDurationSuccess)
+}
+
+// handleFailureSummaryInput handles input while the failure summary is shown
+func (a *App) handleFailureSummaryInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, a.keys.Quit):
+		a.requests.cancelAll()
+		return tea.Quit
+	case key.Matches(msg, a.keys.Escape), key.Matches(msg, a.keys.FailureSummary):
+		a.closeFailureSummary()
+	case key.Matches(msg, a.keys.Yank):
+		return a.yankFailureSummary()
+	case key.Matches(msg, a.keys.Up):
+		a.failureView.ScrollUp()
+	case key.Matches(msg, a.keys.Down):
+		a.failureView.ScrollDown()
+	}
+	return nil
+}
+
+// buildFailureSummaryLines renders the summary for the terminal
+func buildFailureSummaryLines(s FailureSummary) []string {
+	if len(s.Jobs) == 0 {
+		return []string{"No failed jobs"}
+	}
+	var lines []string
+	for i, f := range s.Jobs {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		header := FailureStyle.Render("✗") + " " + lipgloss.NewStyle().Bold(true).Render(f.Job.Name)
+		if f.Step != "" {
+			header += QueuedStyle.Render("  step: ") + f.Step
+		}
+		lines = append(lines, header)
+
+		switch {
+		case f.Err != nil:
+			lines = append(lines, "  "+CancelledStyle.Render("Logs unavailable: "+f.Err.Error()))
+			continue
+		case len(f.Errors) == 0:
+			lines = append(lines, "  "+QueuedStyle.Render("No errors found in the logs"))
+			continue
+		}
+		for j, e := range f.Errors {
+			if j > 0 {
+				lines = append(lines, "  "+QueuedStyle.Render("┆"))
+			}
+			for _, c := range e.Context {
+				lines = append(lines, "  "+QueuedStyle.Render("│ ")+stripLogLine(c))
+			}
+			lines = append(lines, "  "+LogErrorStyle.Render("Error: ")+stripLogLine(e.Message))
+		}
+	}
+	return lines
+}
+
+// renderFailureSummary renders the full-screen failure summary
+func (a *App) renderFailureSummary() string {
+	title := "Failure summary"
+	if a.failureSummary != nil {
+		title += ": " + a.failureSummary.title()
+	}
+	if a.failureSummaryLoading {
+		title += " " + a.spinner.View()
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		FocusedTitle.Render(title),
+		a.failureView.View(),
+	)
+	hint := QueuedStyle.Render("[↑/↓]scroll [y]ank as Markdown [Esc]close")
+	_, height := a.failureViewSize()
+	content = lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Height(height+1).Render(content),
+		hint,
+	)
+
+	return FocusedPane.
+		Width(a.width).
+		Height(a.height - StatusBarHeight).
+		Render(content)
+}
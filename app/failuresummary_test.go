@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// failedJobLogs is the log of a job whose tests fail
+const failedJobLogs = `2024-01-15T10:00:00.0000000Z ##[group]Run go test ./...
+2024-01-15T10:00:00.1000000Z go test ./...
+2024-01-15T10:00:00.2000000Z ##[endgroup]
+2024-01-15T10:00:01.0000000Z --- FAIL: TestHandler (0.01s)
+2024-01-15T10:00:01.1000000Z     handler_test.go:18: got 2, want 3
+2024-01-15T10:00:01.2000000Z
+2024-01-15T10:00:01.3000000Z FAIL	example.com/app	0.012s
+2024-01-15T10:00:01.4000000Z ##[error]Process completed with exit code 1.`
+
+func TestSummarizeJobFailure(t *testing.T) {
+	job := github.Job{
+		ID:   10,
+		Name: "test",
+		Steps: []github.Step{
+			{Name: "Set up job", Number: 1, Conclusion: "success"},
+			{Name: "Run go test ./...", Number: 2, Conclusion: "failure"},
+		},
+	}
+
+	f := summarizeJobFailure(job, ParseLogs(failedJobLogs))
+	if f.Step != "Run go test ./..." {
+		t.Errorf("Step = %q, want the failing step", f.Step)
+	}
+	if len(f.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(f.Errors))
+	}
+	e := f.Errors[0]
+	if e.Message != "Process completed with exit code 1." {
+		t.Errorf("Message = %q", e.Message)
+	}
+	want := []string{"--- FAIL: TestHandler (0.01s)", "    handler_test.go:18: got 2, want 3", "FAIL\texample.com/app\t0.012s"}
+	if strings.Join(e.Context, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Context = %q, want %q", e.Context, want)
+	}
+
+	t.Run("step from the logs", func(t *testing.T) {
+		job := github.Job{ID: 10, Name: "test", Conclusion: "failure"}
+		if f := summarizeJobFailure(job, ParseLogs(failedJobLogs)); f.Step != "Run go test ./..." {
+			t.Errorf("Step = %q, want the step holding the error", f.Step)
+		}
+	})
+
+	t.Run("leaves the parsed logs unchanged", func(t *testing.T) {
+		parsed := ParseLogs(failedJobLogs)
+		steps := parsed.Steps
+		summarizeJobFailure(job, parsed)
+		if len(parsed.Steps) != len(steps) || &parsed.Steps[0] != &steps[0] {
+			t.Error("summarizeJobFailure should not align the steps of the parsed logs it was given")
+		}
+	})
+
+	t.Run("error limit", func(t *testing.T) {
+		logs := strings.Repeat("##[error]boom\n", FailureMaxErrors+3)
+		f := summarizeJobFailure(job, ParseLogs(logs))
+		if len(f.Errors) != FailureMaxErrors {
+			t.Errorf("got %d errors, want %d", len(f.Errors), FailureMaxErrors)
+		}
+		if len(f.Errors[1].Context) != 0 {
+			t.Errorf("context should stop at the previous error, got %q", f.Errors[1].Context)
+		}
+	})
+}
+
+func TestFailureSummary_Markdown(t *testing.T) {
+	s := FailureSummary{
+		Run: github.Run{ID: 1, Name: "CI", RunNumber: 42, Branch: "main", HeadSHA: "abc1234def", URL: "https://github.com/o/r/actions/runs/1"},
+		Jobs: []JobFailure{
+			{
+				Job:  github.Job{ID: 10, Name: "test"},
+				Step: "Run go test ./...",
+				Errors: []FailureExcerpt{
+					{Context: []string{"--- FAIL: TestHandler (0.01s)", "uses ```fences```"}, Message: "Process completed with exit code 1."},
+				},
+			},
+			{Job: github.Job{ID: 11, Name: "lint"}, Err: errors.New("logs expired")},
+		},
+	}
+
+	md := s.Markdown()
+	for _, want := range []string{
+		"### ❌ CI #42 failed",
+		"[Run #42](https://github.com/o/r/actions/runs/1) on `main` (`abc1234`)",
+		"#### [**test**](https://github.com/o/r/actions/runs/1/job/10) — step `Run go test ./...`",
+		"````text\n--- FAIL: TestHandler (0.01s)\nuses ```fences```\nError: Process completed with exit code 1.\n````",
+		"#### [**lint**](https://github.com/o/r/actions/runs/1/job/11)",
+		"_Logs unavailable: logs expired_",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestFetchFailureSummary(t *testing.T) {
+	mock := newMockClient(&mockClientState{
+		jobs: []github.Job{
+			{ID: 10, Name: "test", Status: "completed", Conclusion: "failure"},
+			{ID: 11, Name: "build", Status: "completed", Conclusion: "success"},
+			{ID: 12, Name: "lint", Status: "completed", Conclusion: "failure"},
+		},
+	})
+	mock.GetJobLogsFunc = func(ctx context.Context, repo github.Repository, jobID int64) (io.ReadCloser, error) {
+		if jobID == 12 {
+			return nil, errAPI
+		}
+		return io.NopCloser(strings.NewReader(failedJobLogs)), nil
+	}
+	run := github.Run{ID: 1, Name: "CI", Conclusion: "failure"}
+
+	msg := fetchFailureSummary(context.Background(), mock, nil, nil, nil, github.Repository{Owner: "o", Name: "r"}, run)().(FailureSummaryLoadedMsg)
+	if msg.Err != nil || msg.RunID != 1 {
+		t.Fatalf("msg = %+v", msg)
+	}
+	jobs := msg.Summary.Jobs
+	if len(jobs) != 2 || jobs[0].Job.Name != "test" || jobs[1].Job.Name != "lint" {
+		t.Fatalf("jobs = %+v, want the failed jobs in order", jobs)
+	}
+	if len(jobs[0].Errors) != 1 {
+		t.Errorf("test errors = %+v", jobs[0].Errors)
+	}
+	if !errors.Is(jobs[1].Err, errAPI) {
+		t.Errorf("lint Err = %v, want the logs error", jobs[1].Err)
+	}
+	for _, call := range mock.GetJobLogsCalls() {
+		if call.JobID == 11 {
+			t.Error("logs of successful jobs should not be fetched")
+		}
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if msg := fetchFailureSummary(ctx, mock, nil, nil, nil, github.Repository{}, run)(); msg != nil {
+			t.Errorf("cancelled fetch should send no message, got %T", msg)
+		}
+	})
+}
+
+// fakeClipboard records what is copied
+type fakeClipboard struct{ text string }
+
+func (c *fakeClipboard) WriteAll(text string) error {
+	c.text = text
+	return nil
+}
+
+func TestApp_FailureSummary(t *testing.T) {
+	clipboard := &fakeClipboard{}
+	app := New(WithClient(newMockClient(nil)), WithClipboard(clipboard))
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.focusedPane = RunsPane
+	run := github.Run{ID: 1, Name: "CI", RunNumber: 42, Status: "completed", Conclusion: "failure"}
+	app.runs.SetItems([]github.Run{run, {ID: 2, Status: "completed", Conclusion: "success"}})
+
+	cmd := app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if !app.showFailureSummary || !app.failureSummaryLoading || cmd == nil {
+		t.Fatal("F should open the failure summary and fetch it")
+	}
+
+	// Copying does nothing until the summary is loaded
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if clipboard.text != "" {
+		t.Error("nothing should be copied while loading")
+	}
+
+	// Summaries of another run are ignored
+	app.Update(FailureSummaryLoadedMsg{RunID: 2})
+	if !app.failureSummaryLoading {
+		t.Error("a summary of another run should be ignored")
+	}
+
+	summary := FailureSummary{Run: run, Jobs: []JobFailure{summarizeJobFailure(github.Job{ID: 10, Name: "test"}, ParseLogs(failedJobLogs))}}
+	app.Update(FailureSummaryLoadedMsg{RunID: 1, Summary: summary})
+	view := app.View()
+	for _, want := range []string{"Failure summary: CI #42", "test", "step: Run go test ./...", "handler_test.go:18", "Error: Process completed with exit code 1."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if clipboard.text != summary.Markdown() {
+		t.Errorf("y should copy the summary as Markdown, got %q", clipboard.text)
+	}
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.showFailureSummary {
+		t.Error("Esc should close the failure summary")
+	}
+
+	// Runs that did not fail have no summary
+	app.runs.SelectNext()
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if app.showFailureSummary {
+		t.Error("the failure summary should not open for a successful run")
+	}
+}
@@ -12,13 +12,24 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 		return a.handleFilterInput(msg)
 	}
 
+	// Handle the go-to-line prompt
+	if a.gotoLine {
+		return a.handleGotoInput(msg)
+	}
+
 	// Handle confirm dialog
 	if a.showConfirm {
 		return a.handleConfirmInput(msg)
 	}
 
+	// Handle the failure summary
+	if a.showFailureSummary {
+		return a.handleFailureSummaryInput(msg)
+	}
+
 	switch {
 	case key.Matches(msg, a.keys.Quit):
+		a.requests.cancelAll()
 		return tea.Quit
 
 	case key.Matches(msg, a.keys.Help):
@@ -32,16 +43,69 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 		} else if a.detailTab == LogsTab && a.focusedPane == JobsPane && !a.stepListFocused {
 			// Return focus to step list from log content
 			a.stepListFocused = true
+		} else if a.detailTab == DiffTab && a.diffFocused {
+			// Return focus to the jobs list from the diff
+			a.diffFocused = false
+		} else if a.detailTab == AnnotationsTab && a.annotationsFocused {
+			// Return focus to the jobs list from the annotations
+			a.annotationsFocused = false
+		} else if a.detailTab == ProblemsTab && a.problemsFocused {
+			// Return focus to the jobs list from the problems
+			a.problemsFocused = false
+		} else if a.detailTab == TestsTab && a.testsFocused {
+			// Return focus to the jobs list from the failed tests
+			a.testsFocused = false
+		} else if a.detailTab == SummaryTab && a.summaryFocused {
+			// Return focus to the jobs list from the summary
+			a.summaryFocused = false
 		} else if a.err != nil {
 			a.err = nil
 			return a.refreshAll()
 		}
 
+	case key.Matches(msg, a.keys.ToggleGroup):
+		if a.detailTab == LogsTab && a.focusedPane == JobsPane && a.stepListFocused {
+			a.toggleStepGroup()
+		}
+
 	case key.Matches(msg, a.keys.Enter):
 		// When in Logs tab with step list focused, Enter focuses on log content
 		if a.detailTab == LogsTab && a.focusedPane == JobsPane && a.stepListFocused {
 			a.stepListFocused = false
 		}
+		// When in Diff tab, Enter focuses the diff for scrolling
+		if a.detailTab == DiffTab && a.focusedPane == JobsPane && a.diffBase != nil {
+			a.diffFocused = true
+		}
+		// When in Annotations tab, Enter selects annotations, then opens the selected one
+		if a.detailTab == AnnotationsTab && a.focusedPane == JobsPane && len(a.annotations) > 0 {
+			if a.annotationsFocused {
+				return a.openAnnotation()
+			}
+			a.annotationsFocused = true
+		}
+		// When in Problems tab, Enter selects problems, then jumps to the selected one in the logs
+		if a.detailTab == ProblemsTab && a.focusedPane == JobsPane && len(a.diagnostics()) > 0 {
+			if a.problemsFocused {
+				a.problemsFocused = false
+				a.jumpToProblem()
+				return nil
+			}
+			a.problemsFocused = true
+		}
+		// When in Tests tab, Enter selects failed tests, then jumps to the selected one in the logs
+		if a.detailTab == TestsTab && a.focusedPane == JobsPane && len(a.testFailures()) > 0 {
+			if a.testsFocused {
+				a.testsFocused = false
+				a.jumpToTest()
+				return nil
+			}
+			a.testsFocused = true
+		}
+		// When in Summary tab, Enter focuses the summary for scrolling
+		if a.detailTab == SummaryTab && a.focusedPane == JobsPane && a.hasJobSummary() {
+			a.summaryFocused = true
+		}
 
 	case key.Matches(msg, a.keys.Up):
 		return a.navigateUp()
@@ -93,6 +157,46 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, a.keys.Yank):
 		return a.yankURL()
 
+	case key.Matches(msg, a.keys.Permalink):
+		if a.logContentShown() {
+			return a.yankLogPermalink()
+		}
+
+	case key.Matches(msg, a.keys.LineNumbers):
+		if a.logContentShown() {
+			a.toggleLineNumbers()
+		}
+
+	case key.Matches(msg, a.keys.GotoLine):
+		if a.logContentShown() {
+			a.openGotoLine()
+		}
+
+	case key.Matches(msg, a.keys.PageUp):
+		if a.logContentShown() {
+			a.logView.PageUp()
+		}
+
+	case key.Matches(msg, a.keys.PageDown):
+		if a.logContentShown() {
+			a.logView.PageDown()
+		}
+
+	case key.Matches(msg, a.keys.Top):
+		if a.logContentShown() {
+			a.logView.GotoTop()
+		}
+
+	case key.Matches(msg, a.keys.Bottom):
+		if a.logContentShown() {
+			a.logView.GotoBottom()
+		}
+
+	case key.Matches(msg, a.keys.FailureSummary):
+		if a.focusedPane == RunsPane {
+			return a.openFailureSummary()
+		}
+
 	case key.Matches(msg, a.keys.Refresh):
 		return a.refreshAll()
 
@@ -101,6 +205,58 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 
 	case key.Matches(msg, a.keys.LogsTab):
 		a.detailTab = LogsTab
+
+	case key.Matches(msg, a.keys.TimelineTab):
+		a.detailTab = TimelineTab
+
+	case key.Matches(msg, a.keys.AnalyticsTab):
+		a.detailTab = AnalyticsTab
+		return a.fetchAnalyticsCmd()
+
+	case key.Matches(msg, a.keys.Sort):
+		if a.detailTab == AnalyticsTab {
+			a.analyticsSort = a.analyticsSort.next()
+		}
+
+	case key.Matches(msg, a.keys.DiffTab):
+		a.detailTab = DiffTab
+
+	case key.Matches(msg, a.keys.AnnotationsTab):
+		a.detailTab = AnnotationsTab
+		return a.fetchAnnotationsCmd()
+
+	case key.Matches(msg, a.keys.ProblemsTab):
+		a.detailTab = ProblemsTab
+
+	case key.Matches(msg, a.keys.TestsTab):
+		a.detailTab = TestsTab
+
+	case key.Matches(msg, a.keys.SummaryTab):
+		a.detailTab = SummaryTab
+		return a.fetchSummaryCmd()
+
+	case key.Matches(msg, a.keys.MarkDiff):
+		if a.focusedPane == JobsPane {
+			return a.markDiffBase()
+		}
+
+	case key.Matches(msg, a.keys.DiffMode):
+		if a.detailTab == DiffTab {
+			a.diffSideBySide = !a.diffSideBySide
+		}
+
+	case key.Matches(msg, a.keys.DiffMasks):
+		if a.detailTab == DiffTab {
+			a.diffOpts = a.diffOpts.next()
+		}
+
+	case key.Matches(msg, a.keys.LogColors):
+		if a.detailTab == LogsTab {
+			a.logColorMode = a.logColorMode.next()
+			if len(a.selectedLogLines()) > 0 {
+				a.logView.SetFormat(a.logColorMode.formatter())
+			}
+		}
 	}
 
 	return nil
@@ -172,6 +328,31 @@ func (a *App) navigateUp() tea.Cmd {
 			a.logView.ScrollUp()
 			return nil
 		}
+		// If the diff is focused, scroll it
+		if a.detailTab == DiffTab && a.diffFocused {
+			a.diffView.ScrollUp()
+			return nil
+		}
+		// If the annotations are focused, move the annotation selection
+		if a.detailTab == AnnotationsTab && a.annotationsFocused {
+			a.navigateAnnotation(-1)
+			return nil
+		}
+		// If the problems are focused, move the problem selection
+		if a.detailTab == ProblemsTab && a.problemsFocused {
+			a.navigateProblem(-1)
+			return nil
+		}
+		// If the failed tests are focused, move the test selection
+		if a.detailTab == TestsTab && a.testsFocused {
+			a.navigateTest(-1)
+			return nil
+		}
+		// If the summary is focused, scroll it
+		if a.detailTab == SummaryTab && a.summaryFocused {
+			a.summaryView.ScrollUp()
+			return nil
+		}
 		a.jobs.SelectPrev()
 		return a.onJobSelectionChange()
 	}
@@ -198,6 +379,31 @@ func (a *App) navigateDown() tea.Cmd {
 			a.logView.ScrollDown()
 			return nil
 		}
+		// If the diff is focused, scroll it
+		if a.detailTab == DiffTab && a.diffFocused {
+			a.diffView.ScrollDown()
+			return nil
+		}
+		// If the annotations are focused, move the annotation selection
+		if a.detailTab == AnnotationsTab && a.annotationsFocused {
+			a.navigateAnnotation(1)
+			return nil
+		}
+		// If the problems are focused, move the problem selection
+		if a.detailTab == ProblemsTab && a.problemsFocused {
+			a.navigateProblem(1)
+			return nil
+		}
+		// If the failed tests are focused, move the test selection
+		if a.detailTab == TestsTab && a.testsFocused {
+			a.navigateTest(1)
+			return nil
+		}
+		// If the summary is focused, scroll it
+		if a.detailTab == SummaryTab && a.summaryFocused {
+			a.summaryView.ScrollDown()
+			return nil
+		}
 		a.jobs.SelectNext()
 		return a.onJobSelectionChange()
 	}
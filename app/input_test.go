@@ -197,6 +197,14 @@ func TestApp_HandleKeyPress_DetailTabSwitch(t *testing.T) {
 	if app.detailTab != LogsTab {
 		t.Errorf("After pressing 2: detailTab = %v, want LogsTab", app.detailTab)
 	}
+
+	// Press 3 to switch to TimelineTab
+	msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}}
+	app.handleKeyPress(msg)
+
+	if app.detailTab != TimelineTab {
+		t.Errorf("After pressing 3: detailTab = %v, want TimelineTab", app.detailTab)
+	}
 }
 
 func TestApp_HandleFilterInput_Escape(t *testing.T) {
@@ -365,6 +373,25 @@ func TestApp_ApplyFilterOnRunsPane(t *testing.T) {
 	}
 }
 
+func TestApp_ApplyFilterOnRunsPane_TitleAndCommit(t *testing.T) {
+	app := New()
+	app.focusedPane = RunsPane
+	app.runs.SetItems([]github.Run{
+		{ID: 1, Branch: "main", DisplayTitle: "Fix login redirect", HeadSHA: "abc1234"},
+		{ID: 2, Branch: "main", DisplayTitle: "Bump dependencies", HeadSHA: "def5678"},
+	})
+
+	app.applyFilter("login")
+	if app.runs.Len() != 1 {
+		t.Errorf("runs.Len() = %d, want 1 after title filter", app.runs.Len())
+	}
+
+	app.applyFilter("def5")
+	if run, ok := app.runs.Selected(); !ok || run.ID != 2 {
+		t.Errorf("commit filter should select run 2, got %+v", run)
+	}
+}
+
 func TestApp_ApplyFilterOnJobsPane(t *testing.T) {
 	app := New()
 	app.focusedPane = JobsPane
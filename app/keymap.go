@@ -4,28 +4,49 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines all keybindings for the application
 type KeyMap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Left        key.Binding
-	Right       key.Binding
-	PanelUp     key.Binding
-	PanelDown   key.Binding
-	Tab         key.Binding
-	ShiftTab    key.Binding
-	Enter       key.Binding
-	Trigger     key.Binding
-	Cancel      key.Binding
-	Rerun       key.Binding
-	RerunFailed key.Binding
-	Yank        key.Binding
-	Filter      key.Binding
-	Refresh     key.Binding
-	FullLog     key.Binding
-	Help        key.Binding
-	Quit        key.Binding
-	Escape      key.Binding
-	InfoTab     key.Binding
-	LogsTab     key.Binding
+	Up             key.Binding
+	Down           key.Binding
+	Left           key.Binding
+	Right          key.Binding
+	PanelUp        key.Binding
+	PanelDown      key.Binding
+	Tab            key.Binding
+	ShiftTab       key.Binding
+	Enter          key.Binding
+	Trigger        key.Binding
+	Cancel         key.Binding
+	Rerun          key.Binding
+	RerunFailed    key.Binding
+	Yank           key.Binding
+	Filter         key.Binding
+	Refresh        key.Binding
+	FullLog        key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+	Escape         key.Binding
+	InfoTab        key.Binding
+	LogsTab        key.Binding
+	TimelineTab    key.Binding
+	AnalyticsTab   key.Binding
+	Sort           key.Binding
+	DiffTab        key.Binding
+	MarkDiff       key.Binding
+	DiffMode       key.Binding
+	DiffMasks      key.Binding
+	AnnotationsTab key.Binding
+	ProblemsTab    key.Binding
+	TestsTab       key.Binding
+	SummaryTab     key.Binding
+	FailureSummary key.Binding
+	LogColors      key.Binding
+	ToggleGroup    key.Binding
+	LineNumbers    key.Binding
+	GotoLine       key.Binding
+	PageUp         key.Binding
+	PageDown       key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+	Permalink      key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings
@@ -119,5 +140,89 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("2"),
 			key.WithHelp("2", "logs tab"),
 		),
+		TimelineTab: key.NewBinding(
+			key.WithKeys("3"),
+			key.WithHelp("3", "timeline tab"),
+		),
+		AnalyticsTab: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "analytics tab"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "change sort"),
+		),
+		DiffTab: key.NewBinding(
+			key.WithKeys("5"),
+			key.WithHelp("5", "diff tab"),
+		),
+		MarkDiff: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "mark job for diff"),
+		),
+		DiffMode: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "unified/side-by-side diff"),
+		),
+		DiffMasks: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "cycle diff masks"),
+		),
+		AnnotationsTab: key.NewBinding(
+			key.WithKeys("6"),
+			key.WithHelp("6", "annotations tab"),
+		),
+		ProblemsTab: key.NewBinding(
+			key.WithKeys("7"),
+			key.WithHelp("7", "problems tab"),
+		),
+		TestsTab: key.NewBinding(
+			key.WithKeys("8"),
+			key.WithHelp("8", "tests tab"),
+		),
+		SummaryTab: key.NewBinding(
+			key.WithKeys("9"),
+			key.WithHelp("9", "job summary tab"),
+		),
+		FailureSummary: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "run failure summary"),
+		),
+		LogColors: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "cycle log colors"),
+		),
+		ToggleGroup: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "expand/collapse step groups"),
+		),
+		LineNumbers: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "toggle log line numbers"),
+		),
+		GotoLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "go to log line"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "page up in logs"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdn", "page down in logs"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "first log line"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "last log line"),
+		),
+		Permalink: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy permalink to log line"),
+		),
 	}
 }
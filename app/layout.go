@@ -11,6 +11,10 @@ func (a *App) leftPanelWidth() int {
 	return w
 }
 
+func (a *App) detailContentWidth() int {
+	return a.width - a.leftPanelWidth() - ContentPadding
+}
+
 func (a *App) logPaneWidth() int {
 	// 50% of width for logs pane
 	w := int(float64(a.width) * LogPaneWidthRatio)
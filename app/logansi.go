@@ -0,0 +1,146 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// LogColorMode selects how colors in job logs are displayed
+type LogColorMode int
+
+const (
+	// LogColorsColorized keeps the colors tools emit and highlights
+	// markers and keywords on lines without colors of their own
+	LogColorsColorized LogColorMode = iota
+	// LogColorsRaw shows lines as logged, with only the tools' colors
+	LogColorsRaw
+	// LogColorsStripped shows plain text without any colors
+	LogColorsStripped
+)
+
+// logColorModeNames are the display names of the color modes
+var logColorModeNames = map[LogColorMode]string{
+	LogColorsColorized: "colorized",
+	LogColorsRaw:       "raw",
+	LogColorsStripped:  "stripped",
+}
+
+// next returns the next color mode, wrapping around
+func (m LogColorMode) next() LogColorMode {
+	return (m + 1) % LogColorMode(len(logColorModeNames))
+}
+
+func (m LogColorMode) String() string {
+	return logColorModeNames[m]
+}
+
+// formatter returns the function log lines are passed through in this mode
+func (m LogColorMode) formatter() func(string) string {
+	switch m {
+	case LogColorsRaw:
+		return sanitizeLogLine
+	case LogColorsStripped:
+		return stripLogLine
+	default:
+		return FormatLogLineWithColor
+	}
+}
+
+// ansiReset ends all colors and text attributes
+const ansiReset = "\x1b[0m"
+
+// LogTabWidth is the number of spaces a tab in a log line is expanded to
+const LogTabWidth = 4
+
+var (
+	// escapeRegex matches CSI and OSC sequences, other two-byte escapes and,
+	// failing those, single control characters other than tab and newline
+	escapeRegex = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])|[\x00-\x08\x0b-\x1f\x7f]`)
+	// sgrRegex matches Select Graphic Rendition sequences, which set colors
+	sgrRegex = regexp.MustCompile(`\x1b\[([0-9;:]*)m`)
+)
+
+// sanitizeLogLine makes a log line safe to draw in a pane. Colors are kept,
+// but sequences that move the cursor or clear the screen and other control
+// characters are removed. Only the text after the last carriage return is
+// kept, as a terminal would show for progress output, and tabs are expanded
+// so the display width is known. Colored lines end with a reset, so their
+// colors never leak into the rest of the screen.
+func sanitizeLogLine(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.ReplaceAll(line, "\t", strings.Repeat(" ", LogTabWidth))
+	if !escapeRegex.MatchString(line) {
+		return line
+	}
+
+	colored := false
+	line = escapeRegex.ReplaceAllStringFunc(line, func(seq string) string {
+		if sgrRegex.MatchString(seq) {
+			colored = true
+			return seq
+		}
+		return ""
+	})
+	if colored && !strings.HasSuffix(line, ansiReset) {
+		line += ansiReset
+	}
+	return line
+}
+
+// stripLogLine returns the plain text of a log line
+func stripLogLine(line string) string {
+	return ansi.Strip(sanitizeLogLine(line))
+}
+
+// hasANSIColor reports whether a line sets its own colors
+func hasANSIColor(line string) bool {
+	return strings.Contains(line, "\x1b[") && sgrRegex.MatchString(line)
+}
+
+// wrapANSI hard-wraps a line to width cells without breaking escape
+// sequences. Colors active at a break are reset at the end of the row and
+// restored at the start of the next, so each row renders on its own.
+func wrapANSI(line string, width int) []string {
+	rows := strings.Split(ansi.Hardwrap(line, width, true), "\n")
+	if len(rows) == 1 || !strings.ContainsRune(line, '\x1b') {
+		return rows
+	}
+	return carrySGR(rows)
+}
+
+// carrySGR resets the colors active at the end of each wrapped row and
+// restores them at the start of the next
+func carrySGR(rows []string) []string {
+	active := ""
+	for i, row := range rows {
+		if active != "" {
+			rows[i] = active + row
+		}
+		active = activeSGR(active, row)
+		if active != "" {
+			rows[i] += ansiReset
+		}
+	}
+	return rows
+}
+
+// activeSGR returns the color sequences still in effect after text, given
+// those in effect before it
+func activeSGR(active, text string) string {
+	for _, m := range sgrRegex.FindAllStringSubmatch(text, -1) {
+		switch params := m[1]; {
+		case params == "" || params == "0":
+			active = ""
+		case strings.HasPrefix(params, "0;"):
+			active = m[0]
+		default:
+			active += m[0]
+		}
+	}
+	return active
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestSanitizeLogLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"plain", "go test ./...", "go test ./..."},
+		{"keeps colors and resets", "\x1b[32mok\x1b[0m  pkg", "\x1b[32mok\x1b[0m  pkg\x1b[0m"},
+		{"color already reset", "\x1b[31mFAIL\x1b[0m", "\x1b[31mFAIL\x1b[0m"},
+		{"drops cursor movement", "\x1b[2K\x1b[1Gdone", "done"},
+		{"drops OSC hyperlinks", "\x1b]8;;https://example.com\x07link\x1b]8;;\x07", "link"},
+		{"keeps last carriage return segment", "10%\r50%\r100%", "100%"},
+		{"trailing carriage return", "line\r", "line"},
+		{"expands tabs", "a\tb", "a    b"},
+		{"drops control characters", "bell\x07 here", "bell here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeLogLine(tt.line); got != tt.want {
+				t.Errorf("sanitizeLogLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripLogLine(t *testing.T) {
+	if got := stripLogLine("\x1b[1;31m--- FAIL\x1b[0m: TestX\x1b[K"); got != "--- FAIL: TestX" {
+		t.Errorf("stripLogLine() = %q", got)
+	}
+}
+
+func TestFormatLogLineWithColor_KeepsToolColors(t *testing.T) {
+	line := "2024-01-15T10:00:00.0000000Z \x1b[31mFAIL\x1b[0m error in test"
+	got := FormatLogLineWithColor(line)
+
+	if !strings.Contains(got, "\x1b[31mFAIL\x1b[0m error in test") {
+		t.Errorf("tool colors should be kept and keywords not highlighted, got %q", got)
+	}
+	if !strings.HasPrefix(ansi.Strip(got), "10:00:00 ") {
+		t.Errorf("timestamp should still be shortened, got %q", ansi.Strip(got))
+	}
+}
+
+func TestFormatLogLineWithColor_HighlightsUncoloredLines(t *testing.T) {
+	line := "build failed"
+	if got := FormatLogLineWithColor(line); got != highlightKeywords(line) {
+		t.Errorf("uncolored line should be highlighted, got %q", got)
+	}
+}
+
+func TestWrapANSI_CarriesColorsAcrossRows(t *testing.T) {
+	rows := wrapANSI("\x1b[31m"+strings.Repeat("r", 8)+"\x1b[0m"+"xy", 4)
+
+	want := []string{
+		"\x1b[31mrrrr\x1b[0m", // Reset added at the break
+		"\x1b[31mrrrr\x1b[0m", // Color restored, then reset by the line itself
+		"xy",
+	}
+	if strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Fatalf("wrapANSI() = %q, want %q", rows, want)
+	}
+	for _, row := range rows {
+		if w := ansi.StringWidth(row); w > 4 {
+			t.Errorf("row %q is %d cells wide", row, w)
+		}
+	}
+}
+
+func TestActiveSGR(t *testing.T) {
+	tests := []struct {
+		active, text, want string
+	}{
+		{"", "\x1b[1m\x1b[31mx", "\x1b[1m\x1b[31m"},
+		{"\x1b[31m", "x\x1b[0m", ""},
+		{"\x1b[31m", "x\x1b[m", ""},
+		{"\x1b[31m", "\x1b[0;32mx", "\x1b[0;32m"},
+		{"\x1b[31m", "plain", "\x1b[31m"},
+	}
+	for _, tt := range tests {
+		if got := activeSGR(tt.active, tt.text); got != tt.want {
+			t.Errorf("activeSGR(%q, %q) = %q, want %q", tt.active, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateToWidth_IgnoresEscapes(t *testing.T) {
+	colored := "\x1b[31m" + strings.Repeat("a", 20) + "\x1b[0m"
+
+	got := truncateToWidth(colored, 10)
+	if w := ansi.StringWidth(got); w != 10 {
+		t.Errorf("truncated width = %d, want 10 (%q)", w, got)
+	}
+	if !strings.HasSuffix(got, "\x1b[0m") {
+		t.Errorf("reset after the cut should be kept, got %q", got)
+	}
+	if got := truncateToWidth("\x1b[31mshort\x1b[0m", 10); got != "\x1b[31mshort\x1b[0m" {
+		t.Errorf("fitting string should be unchanged, got %q", got)
+	}
+	if got := padRight("日本語テキスト", 5); ansi.StringWidth(got) != 5 {
+		t.Errorf("padRight() with wide characters is %d cells wide", ansi.StringWidth(got))
+	}
+}
+
+func TestLogColorMode_Formatters(t *testing.T) {
+	line := "2024-01-15T10:00:00.0000000Z \x1b[32mok\x1b[0m\x1b[K"
+
+	if got := LogColorsRaw.formatter()(line); got != "2024-01-15T10:00:00.0000000Z \x1b[32mok\x1b[0m" {
+		t.Errorf("raw = %q", got)
+	}
+	if got := LogColorsStripped.formatter()(line); got != "2024-01-15T10:00:00.0000000Z ok" {
+		t.Errorf("stripped = %q", got)
+	}
+	if LogColorsStripped.next() != LogColorsColorized {
+		t.Error("color modes should wrap around")
+	}
+}
+
+func TestApp_CycleLogColors(t *testing.T) {
+	app := New()
+	app.focusedPane = JobsPane
+	app.detailTab = LogsTab
+	app.jobs.SetItems([]github.Job{{ID: 10, Name: "test", Status: "completed", Conclusion: "success"}})
+	app.Update(LogsLoadedMsg{JobID: 10, Logs: "\x1b[32mok\x1b[0m  pkg\nbuild failed"})
+
+	press := func() { app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}}) }
+
+	press()
+	if app.logColorMode != LogColorsRaw {
+		t.Fatalf("logColorMode = %v, want raw", app.logColorMode)
+	}
+	content := strings.Join(app.buildLogsContent(80), "\n")
+	if !strings.Contains(content, "· raw") || !strings.Contains(content, "build failed") {
+		t.Errorf("raw mode should be shown in the header and leave lines unhighlighted, got:\n%s", content)
+	}
+
+	press()
+	content = strings.Join(app.buildLogsContent(80), "\n")
+	if strings.Contains(content, "\x1b[32m") {
+		t.Errorf("stripped mode should remove tool colors, got %q", content)
+	}
+
+	press()
+	if app.logColorMode != LogColorsColorized {
+		t.Errorf("logColorMode = %v, want colorized", app.logColorMode)
+	}
+}
@@ -0,0 +1,545 @@
+package app
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Log diff constants
+const (
+	// DiffContextLines is the number of unchanged lines kept around each change
+	DiffContextLines = 3
+	// MaxDiffCells caps the size of the line comparison table. Larger
+	// sections are shown as fully removed and added instead of being compared.
+	MaxDiffCells = 1 << 21
+)
+
+// Normalization masks applied before comparing logs
+var (
+	// durationMaskRegex matches durations such as "12ms", "1m30.5s" or "3 seconds"
+	durationMaskRegex = regexp.MustCompile(`\b\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h)(?:\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))*\b|\b\d+(?:\.\d+)? ?(?:seconds?|secs?|minutes?|mins?)\b`)
+	// hashMaskRegex matches hex strings long enough to be commit SHAs or
+	// digests. Only matches with both digits and letters are masked, see isHash.
+	hashMaskRegex = regexp.MustCompile(`\b[0-9a-f]{7,64}\b`)
+)
+
+// LogDiffOptions controls how logs are normalized before they are compared.
+// Timestamps are always stripped.
+type LogDiffOptions struct {
+	MaskDurations bool // Replace durations with <duration>
+	MaskHashes    bool // Replace commit SHAs and digests with <hash>
+}
+
+// DefaultLogDiffOptions returns options with every mask enabled
+func DefaultLogDiffOptions() LogDiffOptions {
+	return LogDiffOptions{MaskDurations: true, MaskHashes: true}
+}
+
+// next cycles through the mask combinations: all, durations only, hashes only, none
+func (o LogDiffOptions) next() LogDiffOptions {
+	switch {
+	case o.MaskDurations && o.MaskHashes:
+		return LogDiffOptions{MaskDurations: true}
+	case o.MaskDurations:
+		return LogDiffOptions{MaskHashes: true}
+	case o.MaskHashes:
+		return LogDiffOptions{}
+	default:
+		return DefaultLogDiffOptions()
+	}
+}
+
+// String describes the enabled masks
+func (o LogDiffOptions) String() string {
+	var masks []string
+	if o.MaskDurations {
+		masks = append(masks, "durations")
+	}
+	if o.MaskHashes {
+		masks = append(masks, "hashes")
+	}
+	if len(masks) == 0 {
+		return "none"
+	}
+	return strings.Join(masks, "+")
+}
+
+// normalizeLogLine strips the timestamp of a log line and applies the enabled masks
+func normalizeLogLine(line string, opts LogDiffOptions) string {
+	line = timestampRegex.ReplaceAllString(line, "")
+	if opts.MaskHashes {
+		line = hashMaskRegex.ReplaceAllStringFunc(line, func(match string) string {
+			if !isHash(match) {
+				return match
+			}
+			return "<hash>"
+		})
+	}
+	if opts.MaskDurations {
+		line = durationMaskRegex.ReplaceAllString(line, "<duration>")
+	}
+	return line
+}
+
+// isHash reports whether a hex string looks like a hash rather than a plain
+// number or a word made of the letters a-f, such as "defaced"
+func isHash(s string) bool {
+	return strings.ContainsAny(s, "0123456789") && strings.ContainsAny(s, "abcdef")
+}
+
+// diffKind is the kind of a line in a diff
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffRemoved
+	diffAdded
+	diffSkipped // Collapsed run of unchanged lines
+)
+
+// diffLine is a line of a diff. Equal lines set both sides, removed lines
+// only Left and added lines only Right. Skipped lines record how many
+// unchanged lines were collapsed.
+type diffLine struct {
+	Kind    diffKind
+	Left    string
+	Right   string
+	Skipped int
+}
+
+// diffOp is an edit operation on two sequences, indexing into each side.
+// A is -1 for insertions and B is -1 for deletions.
+type diffOp struct {
+	A, B int
+}
+
+// diffSequences compares two sequences of lengths n and m using a longest
+// common subsequence and returns the operations in order. Deletions come
+// before insertions between matches.
+func diffSequences(n, m int, eq func(i, j int) bool) []diffOp {
+	var ops []diffOp
+
+	// Trim the common prefix and suffix, which keeps the table small for
+	// mostly identical logs
+	prefix := 0
+	for prefix < n && prefix < m && eq(prefix, prefix) {
+		ops = append(ops, diffOp{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && eq(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+
+	rows, cols := n-prefix-suffix, m-prefix-suffix
+	if rows > 0 && cols > 0 && rows*cols <= MaxDiffCells {
+		// lcs[i][j] is the LCS length of the remaining sequences from i and j
+		lcs := make([][]int32, rows+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, cols+1)
+		}
+		for i := rows - 1; i >= 0; i-- {
+			for j := cols - 1; j >= 0; j-- {
+				if eq(prefix+i, prefix+j) {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		var removed, added []diffOp
+		flush := func() {
+			ops = append(ops, removed...)
+			ops = append(ops, added...)
+			removed, added = removed[:0], added[:0]
+		}
+		for i < rows && j < cols {
+			switch {
+			case eq(prefix+i, prefix+j):
+				flush()
+				ops = append(ops, diffOp{prefix + i, prefix + j})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				removed = append(removed, diffOp{prefix + i, -1})
+				i++
+			default:
+				added = append(added, diffOp{-1, prefix + j})
+				j++
+			}
+		}
+		for ; i < rows; i++ {
+			removed = append(removed, diffOp{prefix + i, -1})
+		}
+		for ; j < cols; j++ {
+			added = append(added, diffOp{-1, prefix + j})
+		}
+		flush()
+	} else {
+		for i := 0; i < rows; i++ {
+			ops = append(ops, diffOp{prefix + i, -1})
+		}
+		for j := 0; j < cols; j++ {
+			ops = append(ops, diffOp{-1, prefix + j})
+		}
+	}
+
+	for k := suffix; k > 0; k-- {
+		ops = append(ops, diffOp{n - k, m - k})
+	}
+	return ops
+}
+
+// diffLines compares two sequences of normalized lines
+func diffLines(a, b []string) []diffLine {
+	ops := diffSequences(len(a), len(b), func(i, j int) bool { return a[i] == b[j] })
+	lines := make([]diffLine, 0, len(ops))
+	for _, op := range ops {
+		switch {
+		case op.B < 0:
+			lines = append(lines, diffLine{Kind: diffRemoved, Left: a[op.A]})
+		case op.A < 0:
+			lines = append(lines, diffLine{Kind: diffAdded, Right: b[op.B]})
+		default:
+			lines = append(lines, diffLine{Kind: diffEqual, Left: a[op.A], Right: b[op.B]})
+		}
+	}
+	return lines
+}
+
+// collapseUnchanged replaces runs of unchanged lines that are further than
+// context lines from any change with a single skipped line
+func collapseUnchanged(lines []diffLine, context int) []diffLine {
+	var result []diffLine
+	for i := 0; i < len(lines); {
+		if lines[i].Kind != diffEqual {
+			result = append(result, lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && lines[end].Kind == diffEqual {
+			end++
+		}
+		// Keep context after the previous change and before the next one
+		keepHead, keepTail := context, context
+		if i == 0 {
+			keepHead = 0
+		}
+		if end == len(lines) {
+			keepTail = 0
+		}
+		if end-i > keepHead+keepTail {
+			result = append(result, lines[i:i+keepHead]...)
+			result = append(result, diffLine{Kind: diffSkipped, Skipped: end - i - keepHead - keepTail})
+			result = append(result, lines[end-keepTail:end]...)
+		} else {
+			result = append(result, lines[i:end]...)
+		}
+		i = end
+	}
+	return result
+}
+
+// logDiffSection is the diff of one step, aligned by name across both logs
+type logDiffSection struct {
+	Name    string
+	InLeft  bool
+	InRight bool
+	Lines   []diffLine
+}
+
+// changes returns the number of removed and added lines in the section
+func (s logDiffSection) changes() (removed, added int) {
+	for _, l := range s.Lines {
+		switch l.Kind {
+		case diffRemoved:
+			removed++
+		case diffAdded:
+			added++
+		}
+	}
+	return removed, added
+}
+
+// diffSteps returns the steps of parsed logs as named sections of normalized
+// lines, without their group markers. Logs without steps form a single section.
+func diffSteps(p *ParsedLogs, opts LogDiffOptions) ([]string, [][]string) {
+	normalize := func(lines []string) []string {
+		out := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if groupStartRegex.MatchString(line) || groupEndRegex.MatchString(line) {
+				continue
+			}
+			out = append(out, normalizeLogLine(line, opts))
+		}
+		return out
+	}
+
+	if p == nil {
+		return nil, nil
+	}
+	if len(p.Steps) == 0 {
+		return []string{"All logs"}, [][]string{normalize(p.AllLines)}
+	}
+	names := make([]string, len(p.Steps))
+	lines := make([][]string, len(p.Steps))
+	for i, step := range p.Steps {
+		names[i] = step.Name
+		lines[i] = normalize(step.Lines)
+	}
+	return names, lines
+}
+
+// diffLogs compares two parsed logs step by step. Steps with the same name
+// are aligned and diffed line by line; steps present in only one log are
+// shown as fully removed or added.
+func diffLogs(left, right *ParsedLogs, opts LogDiffOptions) []logDiffSection {
+	leftNames, leftLines := diffSteps(left, opts)
+	rightNames, rightLines := diffSteps(right, opts)
+
+	ops := diffSequences(len(leftNames), len(rightNames), func(i, j int) bool {
+		return leftNames[i] == rightNames[j]
+	})
+
+	sections := make([]logDiffSection, 0, len(ops))
+	for _, op := range ops {
+		switch {
+		case op.B < 0:
+			sections = append(sections, logDiffSection{
+				Name: leftNames[op.A], InLeft: true, Lines: diffLines(leftLines[op.A], nil),
+			})
+		case op.A < 0:
+			sections = append(sections, logDiffSection{
+				Name: rightNames[op.B], InRight: true, Lines: diffLines(nil, rightLines[op.B]),
+			})
+		default:
+			sections = append(sections, logDiffSection{
+				Name: leftNames[op.A], InLeft: true, InRight: true,
+				Lines: diffLines(leftLines[op.A], rightLines[op.B]),
+			})
+		}
+	}
+	return sections
+}
+
+// renderDiffSectionHeader renders the header line of a section
+func renderDiffSectionHeader(s logDiffSection) string {
+	removed, added := s.changes()
+	switch {
+	case !s.InRight:
+		return LogErrorStyle.Render("- " + s.Name + " (only in base)")
+	case !s.InLeft:
+		return LogSuccessKeyword.Render("+ " + s.Name + " (only in target)")
+	case removed == 0 && added == 0:
+		return QueuedStyle.Render("= " + s.Name + " (identical)")
+	default:
+		return LogGroupStyle.Render("~ "+s.Name) + " " +
+			FailureStyle.Render("-"+strconv.Itoa(removed)) + " " +
+			SuccessStyle.Render("+"+strconv.Itoa(added))
+	}
+}
+
+// renderSkipped renders a collapsed run of unchanged lines
+func renderSkipped(n int) string {
+	return QueuedStyle.Render("  ⋯ " + strconv.Itoa(n) + " unchanged lines")
+}
+
+// renderUnifiedDiff renders the sections as a unified diff, with lines truncated to width
+func renderUnifiedDiff(sections []logDiffSection, width int) []string {
+	var out []string
+	for _, s := range sections {
+		out = append(out, renderDiffSectionHeader(s))
+		if s.InLeft && s.InRight {
+			if removed, added := s.changes(); removed == 0 && added == 0 {
+				continue
+			}
+		}
+		for _, l := range collapseUnchanged(s.Lines, DiffContextLines) {
+			switch l.Kind {
+			case diffSkipped:
+				out = append(out, renderSkipped(l.Skipped))
+			case diffRemoved:
+				out = append(out, FailureStyle.Render("-"+truncateString(l.Left, width-1)))
+			case diffAdded:
+				out = append(out, SuccessStyle.Render("+"+truncateString(l.Right, width-1)))
+			default:
+				out = append(out, " "+truncateString(l.Left, width-1))
+			}
+		}
+		out = append(out, "")
+	}
+	return out
+}
+
+// renderSideBySideDiff renders the sections in two columns. Runs of removed
+// lines are paired row by row with the added lines that follow them.
+func renderSideBySideDiff(sections []logDiffSection, width int) []string {
+	colWidth := max((width-3)/2, 1)
+	row := func(left, right string) string {
+		return padRight(left, colWidth) + QueuedStyle.Render(" │ ") + right
+	}
+	cell := func(text string, kind diffKind) string {
+		text = truncateString(text, colWidth)
+		switch kind {
+		case diffRemoved:
+			return FailureStyle.Render(text)
+		case diffAdded:
+			return SuccessStyle.Render(text)
+		}
+		return text
+	}
+
+	var out []string
+	for _, s := range sections {
+		out = append(out, renderDiffSectionHeader(s))
+		if s.InLeft && s.InRight {
+			if removed, added := s.changes(); removed == 0 && added == 0 {
+				continue
+			}
+		}
+		lines := collapseUnchanged(s.Lines, DiffContextLines)
+		for i := 0; i < len(lines); {
+			switch lines[i].Kind {
+			case diffSkipped:
+				out = append(out, renderSkipped(lines[i].Skipped))
+				i++
+			case diffEqual:
+				out = append(out, row(cell(lines[i].Left, diffEqual), cell(lines[i].Right, diffEqual)))
+				i++
+			default:
+				var removed, added []string
+				for ; i < len(lines) && lines[i].Kind == diffRemoved; i++ {
+					removed = append(removed, lines[i].Left)
+				}
+				for ; i < len(lines) && lines[i].Kind == diffAdded; i++ {
+					added = append(added, lines[i].Right)
+				}
+				for k := 0; k < max(len(removed), len(added)); k++ {
+					var left, right string
+					if k < len(removed) {
+						left = cell(removed[k], diffRemoved)
+					}
+					if k < len(added) {
+						right = cell(added[k], diffAdded)
+					}
+					out = append(out, row(left, right))
+				}
+			}
+		}
+		out = append(out, "")
+	}
+	return out
+}
+
+// diffSide is a job whose logs take part in a diff
+type diffSide struct {
+	JobID int64
+	Label string
+	Logs  *ParsedLogs
+}
+
+// diffViewKey identifies the inputs of the rendered diff, so it is only
+// recomputed when one of them changes
+type diffViewKey struct {
+	baseJobID  int64
+	target     *ParsedLogs
+	opts       LogDiffOptions
+	sideBySide bool
+	width      int
+}
+
+// markDiffBase marks the selected job as the base of the log diff
+func (a *App) markDiffBase() tea.Cmd {
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return nil
+	}
+	if a.parsedLogs == nil {
+		return flashMessage("Logs are not loaded yet", FlashDurationInfo)
+	}
+	label := job.Name
+	if run, ok := a.runs.Selected(); ok {
+		label += " (#" + strconv.Itoa(run.RunNumber) + ")"
+	}
+	a.diffBase = &diffSide{JobID: job.ID, Label: label, Logs: a.parsedLogs}
+	return flashMessage("Marked "+label+" for diff, select another job and press 5", FlashDurationInfo)
+}
+
+// syncDiffView renders the diff of the marked and the selected job into
+// diffView when the Diff tab is shown and one of its inputs changed. It runs
+// after every Update, so rendering the tab never changes state.
+func (a *App) syncDiffView() {
+	if a.detailTab != DiffTab || a.diffBase == nil || a.parsedLogs == nil {
+		return
+	}
+	job, ok := a.jobs.Selected()
+	if !ok || job.ID == a.diffBase.JobID {
+		return
+	}
+
+	width := a.detailContentWidth()
+	key := diffViewKey{
+		baseJobID:  a.diffBase.JobID,
+		target:     a.parsedLogs,
+		opts:       a.diffOpts,
+		sideBySide: a.diffSideBySide,
+		width:      width,
+	}
+	if key == a.diffKey {
+		return
+	}
+	sections := diffLogs(a.diffBase.Logs, a.parsedLogs, a.diffOpts)
+	var lines []string
+	if a.diffSideBySide {
+		lines = renderSideBySideDiff(sections, width-2)
+	} else {
+		lines = renderUnifiedDiff(sections, width-2)
+	}
+	a.diffView.SetContent(strings.Join(lines, "\n"))
+	a.diffView.GotoTop()
+	a.diffKey = key
+}
+
+// buildDiffContent builds the content for the Diff tab, comparing the marked
+// job's logs with the selected job's logs as rendered by syncDiffView
+func (a *App) buildDiffContent(maxWidth int) []string {
+	if a.diffBase == nil {
+		return []string{"  Press [d] on a job to mark it as the diff base,", "  then select another job to compare"}
+	}
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return []string{"  Select a job to compare with " + a.diffBase.Label}
+	}
+	if job.ID == a.diffBase.JobID {
+		return []string{"  Select another job to compare with " + a.diffBase.Label}
+	}
+	if a.parsedLogs == nil {
+		return []string{"  Waiting for logs of " + job.Name}
+	}
+
+	targetLabel := job.Name
+	if run, ok := a.runs.Selected(); ok {
+		targetLabel += " (#" + strconv.Itoa(run.RunNumber) + ")"
+	}
+
+	mode := "unified"
+	if a.diffSideBySide {
+		mode = "side-by-side"
+	}
+
+	var content []string
+	content = append(content, "  Diff: "+truncateString(a.diffBase.Label+" → "+targetLabel, maxWidth-8))
+	content = append(content, "  "+QueuedStyle.Render("[v] "+mode+"  [m] masks: "+a.diffOpts.String()))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	for _, l := range strings.Split(a.diffView.View(), "\n") {
+		content = append(content, "  "+l)
+	}
+	return content
+}
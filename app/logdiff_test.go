@@ -0,0 +1,294 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestNormalizeLogLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		opts LogDiffOptions
+		want string
+	}{
+		{
+			name: "strips timestamp",
+			line: "2024-01-15T10:00:00.1234567Z Running tests",
+			opts: LogDiffOptions{},
+			want: "Running tests",
+		},
+		{
+			name: "masks durations",
+			line: "--- PASS: TestFoo (0.01s) took 1m30.5s and 3 seconds",
+			opts: LogDiffOptions{MaskDurations: true},
+			want: "--- PASS: TestFoo (<duration>) took <duration> and <duration>",
+		},
+		{
+			name: "masks hashes but not plain numbers",
+			line: "HEAD is now at 3f2a9c1d build 1234567",
+			opts: LogDiffOptions{MaskHashes: true},
+			want: "HEAD is now at <hash> build 1234567",
+		},
+		{
+			name: "does not mask words of hex letters",
+			line: "the database was defaced by deadbeef",
+			opts: LogDiffOptions{MaskHashes: true},
+			want: "the database was defaced by deadbeef",
+		},
+		{
+			name: "masks disabled",
+			line: "HEAD is now at 3f2a9c1d in 5s",
+			opts: LogDiffOptions{},
+			want: "HEAD is now at 3f2a9c1d in 5s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeLogLine(tt.line, tt.opts); got != tt.want {
+				t.Errorf("normalizeLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogDiffOptions_Next(t *testing.T) {
+	opts := DefaultLogDiffOptions()
+	var seen []string
+	for i := 0; i < 4; i++ {
+		seen = append(seen, opts.String())
+		opts = opts.next()
+	}
+	want := "durations+hashes,durations,hashes,none"
+	if got := strings.Join(seen, ","); got != want {
+		t.Errorf("mask cycle = %q, want %q", got, want)
+	}
+	if opts != DefaultLogDiffOptions() {
+		t.Error("mask cycle should wrap around to the defaults")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "x", "c", "d", "e"}
+
+	lines := diffLines(a, b)
+
+	var got []string
+	for _, l := range lines {
+		switch l.Kind {
+		case diffEqual:
+			got = append(got, " "+l.Left)
+		case diffRemoved:
+			got = append(got, "-"+l.Left)
+		case diffAdded:
+			got = append(got, "+"+l.Right)
+		}
+	}
+	want := " a,-b,+x, c, d,+e"
+	if strings.Join(got, ",") != want {
+		t.Errorf("diffLines() = %q, want %q", strings.Join(got, ","), want)
+	}
+}
+
+func TestDiffSequences_TooLargeFallsBack(t *testing.T) {
+	n := 2048
+	ops := diffSequences(n, n+MaxDiffCells/n, func(i, j int) bool { return false })
+
+	removed, added := 0, 0
+	for _, op := range ops {
+		if op.B < 0 {
+			removed++
+		} else if op.A < 0 {
+			added++
+		}
+	}
+	if removed != n || added != n+MaxDiffCells/n {
+		t.Errorf("removed/added = %d/%d, want every line removed and added", removed, added)
+	}
+}
+
+func TestCollapseUnchanged(t *testing.T) {
+	var lines []diffLine
+	for i := 0; i < 10; i++ {
+		lines = append(lines, diffLine{Kind: diffEqual})
+	}
+	lines = append(lines, diffLine{Kind: diffAdded})
+	for i := 0; i < 10; i++ {
+		lines = append(lines, diffLine{Kind: diffEqual})
+	}
+
+	collapsed := collapseUnchanged(lines, 2)
+
+	// skipped(8), 2 context, added, 2 context, skipped(8)
+	if len(collapsed) != 7 {
+		t.Fatalf("collapseUnchanged() returned %d lines, want 7", len(collapsed))
+	}
+	if collapsed[0].Kind != diffSkipped || collapsed[0].Skipped != 8 {
+		t.Errorf("first line = %+v, want 8 skipped lines", collapsed[0])
+	}
+	if collapsed[3].Kind != diffAdded {
+		t.Errorf("middle line = %+v, want the added line", collapsed[3])
+	}
+	if collapsed[6].Kind != diffSkipped || collapsed[6].Skipped != 8 {
+		t.Errorf("last line = %+v, want 8 skipped lines", collapsed[6])
+	}
+}
+
+const diffBaseLogs = `2024-01-15T10:00:00.000Z ##[group]Set up job
+2024-01-15T10:00:01.000Z Runner version 2.311.0
+2024-01-15T10:00:02.000Z ##[endgroup]
+2024-01-15T10:00:03.000Z ##[group]Run go test ./...
+2024-01-15T10:00:04.000Z ok  	example.com/pkg	0.52s
+2024-01-15T10:00:05.000Z ##[endgroup]`
+
+const diffTargetLogs = `2024-01-16T11:00:00.000Z ##[group]Set up job
+2024-01-16T11:00:01.000Z Runner version 2.311.0
+2024-01-16T11:00:02.000Z ##[endgroup]
+2024-01-16T11:00:03.000Z ##[group]Install tools
+2024-01-16T11:00:04.000Z installing
+2024-01-16T11:00:05.000Z ##[endgroup]
+2024-01-16T11:00:06.000Z ##[group]Run go test ./...
+2024-01-16T11:00:07.000Z --- FAIL: TestFoo (1.20s)
+2024-01-16T11:00:08.000Z FAIL	example.com/pkg	1.31s
+2024-01-16T11:00:09.000Z ##[endgroup]`
+
+func TestDiffLogs_AlignsSteps(t *testing.T) {
+	sections := diffLogs(ParseLogs(diffBaseLogs), ParseLogs(diffTargetLogs), DefaultLogDiffOptions())
+
+	if len(sections) != 3 {
+		t.Fatalf("diffLogs() returned %d sections, want 3", len(sections))
+	}
+
+	setup := sections[0]
+	if setup.Name != "Set up job" || !setup.InLeft || !setup.InRight {
+		t.Errorf("first section = %+v, want matched Set up job", setup)
+	}
+	if removed, added := setup.changes(); removed != 0 || added != 0 {
+		t.Errorf("Set up job should be identical once timestamps are stripped, got -%d +%d", removed, added)
+	}
+
+	if tools := sections[1]; tools.Name != "Install tools" || tools.InLeft || !tools.InRight {
+		t.Errorf("second section = %+v, want Install tools only in target", tools)
+	}
+
+	test := sections[2]
+	if test.Name != "Run go test ./..." || !test.InLeft || !test.InRight {
+		t.Errorf("third section = %+v, want matched test step", test)
+	}
+	if removed, added := test.changes(); removed != 1 || added != 2 {
+		t.Errorf("test step changes = -%d +%d, want -1 +2", removed, added)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	sections := diffLogs(ParseLogs(diffBaseLogs), ParseLogs(diffTargetLogs), DefaultLogDiffOptions())
+
+	unified := strings.Join(renderUnifiedDiff(sections, 80), "\n")
+	for _, want := range []string{"Set up job (identical)", "Install tools (only in target)", "-ok", "+--- FAIL: TestFoo (<duration>)"} {
+		if !strings.Contains(unified, want) {
+			t.Errorf("unified diff should contain %q, got:\n%s", want, unified)
+		}
+	}
+
+	sideBySide := renderSideBySideDiff(sections, 80)
+	found := false
+	for _, line := range sideBySide {
+		if strings.Contains(line, "ok") && strings.Contains(line, "│") && strings.Contains(line, "--- FAIL") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("side-by-side diff should pair the removed and added lines, got:\n%s", strings.Join(sideBySide, "\n"))
+	}
+}
+
+func TestApp_LogDiff(t *testing.T) {
+	app := New()
+	app.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	app.focusedPane = JobsPane
+	app.runs.SetItems([]github.Run{{ID: 1, RunNumber: 7}})
+	app.jobs.SetItems([]github.Job{
+		{ID: 10, Name: "test", Status: "completed", Conclusion: "success"},
+		{ID: 11, Name: "test-branch", Status: "completed", Conclusion: "failure"},
+	})
+
+	// Marking requires loaded logs
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if app.diffBase != nil {
+		t.Fatal("job without logs should not be marked")
+	}
+
+	app.parsedLogs = ParseLogs(diffBaseLogs)
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if app.diffBase == nil || app.diffBase.JobID != 10 || app.diffBase.Label != "test (#7)" {
+		t.Fatalf("diffBase = %+v, want job 10 marked", app.diffBase)
+	}
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}})
+	if app.detailTab != DiffTab {
+		t.Fatalf("detailTab = %v, want DiffTab", app.detailTab)
+	}
+	if content := strings.Join(app.buildDiffContent(80), "\n"); !strings.Contains(content, "Select another job") {
+		t.Errorf("diff against the marked job itself should ask for another job, got:\n%s", content)
+	}
+
+	app.jobs.SelectNext()
+	app.parsedLogs = ParseLogs(diffTargetLogs)
+	app.syncDiffView()
+	content := strings.Join(app.buildDiffContent(80), "\n")
+	for _, want := range []string{"Diff: test (#7) → test-branch (#7)", "unified", "masks: durations+hashes", "Install tools"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("diff content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// v and m switch the mode and masks
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	app.syncDiffView()
+	content = strings.Join(app.buildDiffContent(80), "\n")
+	if !strings.Contains(content, "│") {
+		t.Errorf("the diff should be rendered side by side, got:\n%s", content)
+	}
+	if !strings.Contains(content, "side-by-side") || !strings.Contains(content, "masks: durations") {
+		t.Errorf("diff header should reflect the new mode and masks, got:\n%s", content)
+	}
+
+	// Enter focuses the diff so up/down scroll it instead of changing the job
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.diffFocused {
+		t.Fatal("Enter should focus the diff")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyUp})
+	if app.jobs.SelectedIndex() != 1 {
+		t.Error("up should scroll the focused diff, not change the job")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.diffFocused {
+		t.Error("Esc should return focus to the jobs list")
+	}
+
+	// Rendering only reads the diff rendered by Update
+	key := app.diffKey
+	app.diffSideBySide = false
+	_ = app.View()
+	if app.diffKey != key {
+		t.Error("View should not render the diff again")
+	}
+	app.Update(FlashClearMsg{})
+	if app.diffKey == key || app.diffKey.sideBySide {
+		t.Error("Update should render the diff again after its inputs change")
+	}
+}
+
+func TestWithLogDiffOptions(t *testing.T) {
+	app := New(WithLogDiffOptions(LogDiffOptions{MaskHashes: true}))
+	if app.diffOpts != (LogDiffOptions{MaskHashes: true}) {
+		t.Errorf("diffOpts = %+v, want only hashes masked", app.diffOpts)
+	}
+}
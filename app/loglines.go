@@ -0,0 +1,177 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logContentShown reports whether the keys that move around the logs apply:
+// the Logs tab or the full-screen log is shown for the selected job
+func (a *App) logContentShown() bool {
+	return a.focusedPane == JobsPane && (a.detailTab == LogsTab || a.fullscreenLog) &&
+		a.parsedLogs != nil && len(a.selectedLogLines()) > 0
+}
+
+// setLogGutter numbers the lines of the log view as GitHub does, from 1 in
+// each step, if line numbers are on
+func (a *App) setLogGutter() {
+	if !a.lineNumbers || a.parsedLogs == nil || len(a.selectedLogLines()) == 0 {
+		a.logView.SetGutter(nil, 0)
+		return
+	}
+	parsed, start := a.parsedLogs, a.selectedLogStart()
+	longest := len(parsed.AllLines)
+	if len(parsed.Steps) > 0 {
+		longest = 0
+		for _, s := range parsed.Steps {
+			longest = max(longest, s.EndLine-s.StartLine+1)
+		}
+	}
+	a.logView.SetGutter(func(i int) string {
+		return strconv.Itoa(parsed.lineNumber(start + i))
+	}, len(strconv.Itoa(longest)))
+}
+
+// toggleLineNumbers shows or hides the log line numbers
+func (a *App) toggleLineNumbers() {
+	a.lineNumbers = !a.lineNumbers
+	a.setLogGutter()
+}
+
+// currentLogLine returns the index in ParsedLogs.AllLines of the current
+// line of the log view
+func (a *App) currentLogLine() int {
+	return a.selectedLogStart() + a.logView.Current()
+}
+
+// logPosition describes where the current log line is, as the step and line
+// of GitHub's log anchors and how far into the shown lines it is
+func (a *App) logPosition() string {
+	if a.parsedLogs == nil || len(a.selectedLogLines()) == 0 {
+		return ""
+	}
+	i := a.currentLogLine()
+	position := "line " + strconv.Itoa(a.parsedLogs.lineNumber(i))
+	if s := a.parsedLogs.stepAt(i); s != nil && s.Number > 0 {
+		position = "step " + strconv.Itoa(s.Number) + " " + position
+	}
+	percent := (a.logView.Current() + 1) * 100 / a.logView.Len()
+	return position + " · " + strconv.Itoa(percent) + "%"
+}
+
+// logPermalink returns the URL of the current log line in GitHub's log view,
+// and whether it points at the line. Lines outside the job's steps link to
+// the job.
+func (a *App) logPermalink() (string, bool) {
+	run, ok := a.runs.Selected()
+	if !ok {
+		return "", false
+	}
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return "", false
+	}
+	url := runJobURL(run, job.ID)
+	if url == "" || a.parsedLogs == nil {
+		return url, false
+	}
+	i := a.currentLogLine()
+	s := a.parsedLogs.stepAt(i)
+	if s == nil || s.Number == 0 {
+		return url, false
+	}
+	return url + "#step:" + strconv.Itoa(s.Number) + ":" + strconv.Itoa(a.parsedLogs.lineNumber(i)), true
+}
+
+// yankLogPermalink copies the permalink of the current log line
+func (a *App) yankLogPermalink() tea.Cmd {
+	url, toLine := a.logPermalink()
+	if url == "" {
+		return nil
+	}
+	if err := a.clipboard.WriteAll(url); err != nil {
+		// Show URL in flash message so user can copy manually
+		return flashMessage("URL: "+url, FlashDurationInfo)
+	}
+	if !toLine {
+		return flashMessage("Copied job URL, the line is not in a job step: "+url, FlashDurationSuccess)
+	}
+	return flashMessage("Copied: "+url, FlashDurationSuccess)
+}
+
+// openGotoLine shows the go-to-line prompt
+func (a *App) openGotoLine() {
+	a.gotoLine = true
+	a.gotoInput.SetValue("")
+	a.gotoInput.Focus()
+}
+
+// handleGotoInput handles input when the go-to-line prompt is shown
+func (a *App) handleGotoInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		a.gotoLine = false
+		a.gotoInput.Blur()
+	case "enter":
+		a.gotoLine = false
+		a.gotoInput.Blur()
+		return a.goToLogLine(a.gotoInput.Value())
+	default:
+		var cmd tea.Cmd
+		a.gotoInput, cmd = a.gotoInput.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// goToLogLine makes a log line current, given as L for line L of the current
+// step, or N:L for line L of step N. The step:N:L anchors of GitHub's log
+// view are accepted too, so a shared permalink can be followed.
+func (a *App) goToLogLine(input string) tea.Cmd {
+	if a.parsedLogs == nil || len(a.parsedLogs.AllLines) == 0 {
+		return nil
+	}
+	target := strings.TrimSpace(input)
+	if i := strings.LastIndexByte(target, '#'); i >= 0 {
+		target = target[i+1:]
+	}
+	target = strings.TrimPrefix(target, "step:")
+	stepText, lineText, hasStep := strings.Cut(target, ":")
+	if !hasStep {
+		lineText = stepText
+	}
+	number, err := strconv.Atoi(lineText)
+	if err != nil || number < 1 {
+		return flashMessage("Invalid line: "+input, FlashDurationInfo)
+	}
+
+	var step *StepLog
+	if hasStep {
+		n, err := strconv.Atoi(stepText)
+		if err == nil {
+			step = a.parsedLogs.stepByNumber(n)
+		}
+		if step == nil {
+			return flashMessage("No step "+stepText+" in the logs", FlashDurationInfo)
+		}
+	} else if len(a.selectedLogLines()) > 0 {
+		step = a.parsedLogs.stepAt(a.currentLogLine())
+	}
+
+	line := min(number-1, len(a.parsedLogs.AllLines)-1)
+	if step != nil {
+		line = min(step.StartLine+number-1, step.EndLine)
+	}
+
+	// Stay on the shown step or group if it holds the line
+	start := a.selectedLogStart()
+	if a.detailTab == LogsTab && line >= start && line < start+len(a.selectedLogLines()) {
+		a.stepListFocused = false
+		a.logView.ScrollTo(line - start)
+		return nil
+	}
+	a.jumpToLogLine(line)
+	return nil
+}
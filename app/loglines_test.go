@@ -0,0 +1,159 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// newLogLinesApp returns an app showing all logs of a job of a run with a
+// web URL
+func newLogLinesApp(t *testing.T) (*App, *fakeClipboard) {
+	t.Helper()
+	clipboard := &fakeClipboard{}
+	app := newStepListApp(t)
+	app.clipboard = clipboard
+	app.runs.SetItems([]github.Run{{ID: 1, URL: "https://github.com/o/r/actions/runs/1"}})
+	app.stepListFocused = false
+	app.logView.SetSize(80, 5)
+	return app, clipboard
+}
+
+// typeKeys sends each rune of s as a key press
+func typeKeys(app *App, s string) {
+	for _, r := range s {
+		app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestParsedLogs_LineNumber(t *testing.T) {
+	parsed := ParseLogs(realisticJobLogs)
+	parsed.AlignSteps(realisticJobSteps())
+
+	test := parsed.stepByNumber(5)
+	if test == nil || test.Name != "Run go test ./..." {
+		t.Fatalf("step 5 = %+v", test)
+	}
+	if n := parsed.lineNumber(test.StartLine); n != 1 {
+		t.Errorf("first line of a step is numbered %d, want 1", n)
+	}
+	if n := parsed.lineNumber(test.StartLine + 2); n != 3 {
+		t.Errorf("third line of a step is numbered %d, want 3", n)
+	}
+	if parsed.stepByNumber(4) != nil {
+		t.Error("skipped steps have no logs")
+	}
+}
+
+func TestApp_LogPermalink(t *testing.T) {
+	app, clipboard := newLogLinesApp(t)
+
+	typeKeys(app, ":5:3")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.gotoLine {
+		t.Fatal("Enter should close the go-to-line prompt")
+	}
+	if line := logText(app.parsedLogs.AllLines[app.currentLogLine()]); line != "ok  pkg 0.01s" {
+		t.Errorf("current line = %q, want line 3 of step 5", line)
+	}
+	if pos := app.logPosition(); !strings.HasPrefix(pos, "step 5 line 3") {
+		t.Errorf("position = %q", pos)
+	}
+
+	typeKeys(app, "Y")
+	if want := "https://github.com/o/r/actions/runs/1/job/10#step:5:3"; clipboard.text != want {
+		t.Errorf("Y copied %q, want %q", clipboard.text, want)
+	}
+
+	// A line of the current step, and anchors of shared links
+	typeKeys(app, ":1")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.parsedLogs.lineNumber(app.currentLogLine()) != 1 || app.parsedLogs.stepAt(app.currentLogLine()).Number != 5 {
+		t.Error(":1 should go to the first line of the current step")
+	}
+	typeKeys(app, ":https://github.com/o/r/actions/runs/1/job/10#step:2:2")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if line := logText(app.parsedLogs.AllLines[app.currentLogLine()]); line != "with:" {
+		t.Errorf("current line = %q, want line 2 of step 2", line)
+	}
+
+	// Lines past the end of a step stop at its last line
+	typeKeys(app, ":2:99")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if i := app.currentLogLine(); i != app.parsedLogs.stepByNumber(2).EndLine {
+		t.Errorf("current line = %d, want the last line of step 2", i)
+	}
+
+	// Invalid input leaves the position alone
+	before := app.currentLogLine()
+	for _, input := range []string{"abc", "0", "9:1"} {
+		typeKeys(app, ":"+input)
+		if cmd := app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+			t.Errorf("%q should report an error", input)
+		}
+	}
+	if app.currentLogLine() != before {
+		t.Error("invalid input should not move the log view")
+	}
+
+	// Esc closes the prompt without moving
+	typeKeys(app, ":1")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.gotoLine || app.currentLogLine() != before {
+		t.Error("Esc should close the prompt without moving")
+	}
+}
+
+func TestApp_LogGotoOtherStep(t *testing.T) {
+	app, _ := newLogLinesApp(t)
+	app.selectedStepIdx = 0
+	app.updateLogViewContent()
+
+	// Lines of another step select the step holding them
+	typeKeys(app, ":5:1")
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if rows := app.stepListRows(); rows[app.selectedStepIdx].number != 5 {
+		t.Errorf("selected row = %+v, want step 5", rows[app.selectedStepIdx])
+	}
+	if app.logView.Current() != 0 {
+		t.Errorf("current line = %d, want the first of the step", app.logView.Current())
+	}
+}
+
+func TestApp_LogLineNumbersAndPaging(t *testing.T) {
+	app, _ := newLogLinesApp(t)
+	app.selectedStepIdx = 4 // Run go test ./...
+	app.updateLogViewContent()
+
+	typeKeys(app, "g#")
+	if !app.lineNumbers {
+		t.Fatal("# should turn line numbers on")
+	}
+	rows := app.logView.Rows()
+	if len(rows) == 0 || !strings.HasPrefix(rows[0], "1 ") {
+		t.Errorf("rows should be numbered from 1 in the step, got %q", rows)
+	}
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyPgDown})
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyPgUp})
+	if app.logView.Offset() != 0 {
+		t.Errorf("offset = %d after paging down and up", app.logView.Offset())
+	}
+	typeKeys(app, "G")
+	if app.logView.Current() != app.logView.Len()-1 {
+		t.Error("G should make the last line current")
+	}
+
+	// Line numbers stay on for other steps
+	app.navigateStepUp()
+	if rows := app.logView.Rows(); len(rows) == 0 || !strings.HasPrefix(strings.TrimSpace(rows[0]), "1 ") {
+		t.Errorf("rows of another step should be numbered, got %q", rows)
+	}
+
+	typeKeys(app, "#")
+	if rows := app.logView.Rows(); strings.HasPrefix(rows[0], "1 ") {
+		t.Errorf("# should turn line numbers off, got %q", rows)
+	}
+}
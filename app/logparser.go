@@ -1,23 +1,97 @@
 package app
 
 import (
+	"io"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nnnkkk7/lazyactions/github"
 )
 
-// StepLog represents a parsed step with its log lines
+// StepLog represents a step of the job with its log lines. Before the steps
+// are aligned with the job's steps, every top-level group starts a step.
 type StepLog struct {
-	Name      string   // Step name extracted from ##[group]
-	Lines     []string // Log lines for this step
-	StartLine int      // Starting line number in the original logs
-	EndLine   int      // Ending line number in the original logs
+	Name      string     // Job step name, or the name of the group that starts the step
+	Lines     []string   // Log lines for this step
+	StartLine int        // Starting line number in the original logs
+	EndLine   int        // Ending line number in the original logs
+	Groups    []LogGroup // Groups nested in the step
+	Number    int        // Number of the matching job step, 0 if not aligned
+
+	kind      stepKind
+	startedAt time.Time // Timestamp of the first line, zero if unknown
+}
+
+// LogGroup is a ##[group] section within a step. Groups nest, for example
+// the steps of composite actions and groups printed by the tools a step runs.
+type LogGroup struct {
+	Name      string
+	StartLine int // Line of the ##[group] marker in the original logs
+	EndLine   int // Line of the ##[endgroup] marker, or the last line if unclosed
+	Groups    []LogGroup
 }
 
 // ParsedLogs represents the parsed structure of GitHub Actions logs
 type ParsedLogs struct {
-	Steps    []StepLog // Parsed steps
-	RawLogs  string    // Original raw logs
-	AllLines []string  // All lines split from raw logs
+	Steps       []StepLog    // Parsed steps
+	RawLogs     string       // Original raw logs
+	AllLines    []string     // All lines split from raw logs
+	Diagnostics []Diagnostic // Problems recognized by the problem matchers, in log order
+	Tests       TestReport   // Results of the tests run by the job
+
+	sections []StepLog // Steps as parsed, before AlignSteps merges them
+}
+
+// stepKind classifies steps by the runner's markers, so log sections can be
+// matched with job steps whose names don't appear in the logs
+type stepKind int
+
+const (
+	stepKindGroup    stepKind = iota // A group that doesn't start a job step
+	stepKindSetup                    // "Set up job"
+	stepKindRun                      // "Run …", the header of an action or script step
+	stepKindPost                     // "Post …" steps, which log "Post job cleanup."
+	stepKindComplete                 // "Complete job"
+)
+
+// Names and markers of the steps the runner adds to every job
+const (
+	setUpJobStepName    = "Set up job"
+	completeJobStepName = "Complete job"
+	postStepPrefix      = "Post "
+	runStepPrefix       = "Run "
+	postJobMarker       = "Post job cleanup."
+	completeJobMarker   = "Cleaning up orphan processes"
+)
+
+// sectionKind returns the kind of step a log section with the given name starts
+func sectionKind(name string) stepKind {
+	switch {
+	case name == setUpJobStepName:
+		return stepKindSetup
+	case name == completeJobStepName:
+		return stepKindComplete
+	case name == postJobMarker || strings.HasPrefix(name, postStepPrefix):
+		return stepKindPost
+	case strings.HasPrefix(name, runStepPrefix):
+		return stepKindRun
+	default:
+		return stepKindGroup
+	}
+}
+
+// jobStepKind returns the kind of log section a job step starts with
+func jobStepKind(name string) stepKind {
+	switch kind := sectionKind(name); kind {
+	case stepKindGroup:
+		// Named steps still log a "Run …" header
+		return stepKindRun
+	default:
+		return kind
+	}
 }
 
 // groupStartRegex matches ##[group]<step name>
@@ -29,69 +103,263 @@ var groupEndRegex = regexp.MustCompile(`##\[endgroup\]`)
 // timestampRegex matches ISO 8601 timestamps at the start of log lines
 var timestampRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T(\d{2}:\d{2}:\d{2})\.\d+Z)\s*`)
 
-// ParseLogs parses GitHub Actions log output and extracts steps
-func ParseLogs(rawLogs string) *ParsedLogs {
-	parsed := &ParsedLogs{
-		RawLogs:  rawLogs,
-		Steps:    []StepLog{},
-		AllLines: []string{},
+// logText returns the text a tool printed on a log line, without the
+// timestamp and colors. Indentation is kept, as it is part of the output.
+func logText(line string) string {
+	text := line
+	if m := timestampRegex.FindStringSubmatchIndex(line); m != nil {
+		text = strings.TrimPrefix(line[m[3]:], " ")
+	}
+	if strings.ContainsRune(text, '\x1b') {
+		text = ansi.Strip(text)
 	}
+	return text
+}
 
-	if rawLogs == "" {
-		return parsed
+// ParseLogs parses GitHub Actions log output and extracts steps, test
+// results and the problems recognized by the built-in problem matchers
+func ParseLogs(rawLogs string) *ParsedLogs {
+	p := newLogParser(defaultMatchers)
+	if rawLogs != "" {
+		for _, line := range strings.Split(rawLogs, "\n") {
+			p.addLine(line)
+		}
 	}
+	parsed := p.finish()
+	parsed.RawLogs = rawLogs
+	return parsed
+}
 
-	lines := strings.Split(rawLogs, "\n")
-	parsed.AllLines = lines
+// parseRedactedLogs redacts logs with stream and parses them in a single
+// pass over the lines, so large logs are not split and scanned repeatedly.
+// RawLogs holds the redacted logs. Problems are recognized with matchers,
+// or the built-in matchers if it is nil.
+func parseRedactedLogs(src io.Reader, stream *github.RedactStream, matchers *MatcherRegistry) (*ParsedLogs, error) {
+	if matchers == nil {
+		matchers = defaultMatchers
+	}
+	p := newLogParser(matchers)
+	var raw strings.Builder
+	err := stream.Lines(src, func(line string) {
+		if len(p.parsed.AllLines) > 0 {
+			raw.WriteByte('\n')
+		}
+		raw.WriteString(line)
+		p.addLine(line)
+	})
+	parsed := p.finish()
+	parsed.RawLogs = raw.String()
+	return parsed, err
+}
 
-	var currentStep *StepLog
-	var inGroup bool
+// logParser builds ParsedLogs one line at a time
+type logParser struct {
+	parsed   *ParsedLogs
+	open     []LogGroup // Groups not closed yet, innermost last
+	problems *problemScanner
+	tests    *testReportParser
+}
 
-	for i, line := range lines {
-		// Check for group start
-		if match := groupStartRegex.FindStringSubmatch(line); match != nil {
-			// Close previous unclosed group if any
-			if currentStep != nil && inGroup {
-				currentStep.EndLine = i - 1
-				parsed.Steps = append(parsed.Steps, *currentStep)
-			}
+func newLogParser(matchers *MatcherRegistry) *logParser {
+	return &logParser{
+		parsed: &ParsedLogs{
+			Steps:    []StepLog{},
+			AllLines: []string{},
+		},
+		problems: matchers.newScanner(),
+		tests:    newTestReportParser(),
+	}
+}
 
-			currentStep = &StepLog{
-				Name:      match[1],
-				Lines:     []string{line},
-				StartLine: i,
-			}
-			inGroup = true
-			continue
+// addLine adds the next line of the logs. A top-level group, or the marker
+// of a post step or of the job's completion, starts a new section. Groups
+// inside a group are nested in it.
+func (p *logParser) addLine(line string) {
+	i := len(p.parsed.AllLines)
+	p.parsed.AllLines = append(p.parsed.AllLines, line)
+	text := logText(line)
+	p.problems.addLine(i, text)
+	p.tests.addLine(i, text)
+
+	// Check for group start
+	if match := groupStartRegex.FindStringSubmatch(line); match != nil {
+		if len(p.open) == 0 {
+			p.startSection(match[1], i, line)
 		}
+		p.open = append(p.open, LogGroup{Name: match[1], StartLine: i})
+		return
+	}
 
-		// Check for group end
-		if groupEndRegex.MatchString(line) {
-			if currentStep != nil && inGroup {
-				currentStep.Lines = append(currentStep.Lines, line)
-				currentStep.EndLine = i
-				parsed.Steps = append(parsed.Steps, *currentStep)
-				currentStep = nil
-				inGroup = false
-			}
-			continue
+	// Check for group end
+	if groupEndRegex.MatchString(line) {
+		if len(p.open) > 0 {
+			p.closeGroup(i)
 		}
+		return
+	}
 
-		// Add line to current step if in a group
-		if currentStep != nil && inGroup {
-			currentStep.Lines = append(currentStep.Lines, line)
+	if len(p.open) == 0 {
+		switch text := strings.TrimSpace(timestampRegex.ReplaceAllString(line, "")); text {
+		case postJobMarker:
+			p.startSection(postJobMarker, i, line)
+		case completeJobMarker:
+			p.startSection(completeJobStepName, i, line)
 		}
 	}
+}
 
-	// Handle unclosed group (still running)
-	if currentStep != nil && inGroup {
-		currentStep.EndLine = len(lines) - 1
-		parsed.Steps = append(parsed.Steps, *currentStep)
+// startSection starts a new section at line i
+func (p *logParser) startSection(name string, i int, line string) {
+	section := StepLog{Name: name, StartLine: i, kind: sectionKind(name)}
+	if match := timestampRegex.FindStringSubmatch(line); match != nil {
+		section.startedAt, _ = time.Parse(time.RFC3339Nano, match[1])
+	}
+	p.parsed.sections = append(p.parsed.sections, section)
+}
+
+// closeGroup closes the innermost open group at line i. A closed top-level
+// group is the header of its section, so only the groups within it are kept.
+func (p *logParser) closeGroup(i int) {
+	g := p.open[len(p.open)-1]
+	p.open = p.open[:len(p.open)-1]
+	g.EndLine = i
+	if len(p.open) > 0 {
+		parent := &p.open[len(p.open)-1]
+		parent.Groups = append(parent.Groups, g)
+		return
+	}
+	section := &p.parsed.sections[len(p.parsed.sections)-1]
+	section.Groups = append(section.Groups, g.Groups...)
+}
+
+// finish closes groups still open at the end of the logs and returns the
+// parsed logs. Lines before the first section are the job's setup.
+func (p *logParser) finish() *ParsedLogs {
+	// Handle unclosed groups (still running)
+	for len(p.open) > 0 {
+		p.closeGroup(len(p.parsed.AllLines) - 1)
 	}
 
+	parsed := p.parsed
+	parsed.Diagnostics = p.problems.diagnostics
+	parsed.Tests = p.tests.finish()
+	if len(parsed.sections) > 0 && parsed.sections[0].StartLine > 0 {
+		setup := StepLog{Name: setUpJobStepName, kind: stepKindSetup}
+		if match := timestampRegex.FindStringSubmatch(parsed.AllLines[0]); match != nil {
+			setup.startedAt, _ = time.Parse(time.RFC3339Nano, match[1])
+		}
+		parsed.sections = append([]StepLog{setup}, parsed.sections...)
+	}
+	for i := range parsed.sections {
+		end := len(parsed.AllLines)
+		if i+1 < len(parsed.sections) {
+			end = parsed.sections[i+1].StartLine
+		}
+		parsed.sections[i].EndLine = end - 1
+		parsed.sections[i].Lines = parsed.AllLines[parsed.sections[i].StartLine:end]
+	}
+	parsed.Steps = parsed.sections
 	return parsed
 }
 
+// AlignSteps regroups the parsed sections into the steps of the job, so
+// that Steps lines up with the job's steps. Sections are matched with job
+// steps in order, by name where the logs contain it, and otherwise by the
+// kind of step and its start time. Sections that don't start a job step,
+// such as groups printed by a tool or the steps of a composite action, are
+// nested in the step before them. Skipped steps have no logs and are left out.
+func (p *ParsedLogs) AlignSteps(jobSteps []github.Step) {
+	if p == nil || len(p.sections) == 0 {
+		return
+	}
+
+	var steps []StepLog
+	first, next := -1, 0
+	for _, js := range jobSteps {
+		if js.Conclusion == "skipped" {
+			continue
+		}
+		m := p.matchSection(js, next)
+		if m < 0 {
+			continue
+		}
+		if first < 0 {
+			first = m
+		} else {
+			for _, s := range p.sections[next:m] {
+				steps[len(steps)-1].nest(s)
+			}
+		}
+		step := p.sections[m]
+		step.Name = js.Name
+		step.Number = js.Number
+		step.Groups = slices.Clone(step.Groups)
+		steps = append(steps, step)
+		next = m + 1
+	}
+	if len(steps) == 0 {
+		p.Steps = p.sections
+		return
+	}
+
+	// Sections before the first step and after the last belong to them
+	for _, s := range p.sections[:first] {
+		steps[0].nest(s)
+	}
+	for _, s := range p.sections[next:] {
+		steps[len(steps)-1].nest(s)
+	}
+	for i := range steps {
+		steps[i].Lines = p.AllLines[steps[i].StartLine : steps[i].EndLine+1]
+	}
+	p.Steps = steps
+}
+
+// matchSection returns the index of the section at or after from that
+// starts the job step, or -1 if there is none
+func (p *ParsedLogs) matchSection(js github.Step, from int) int {
+	kind := jobStepKind(js.Name)
+	byOrder, byTime := -1, -1
+	startedAt := js.StartedAt.Truncate(time.Second)
+	for i := from; i < len(p.sections); i++ {
+		s := p.sections[i]
+		if s.kind != kind {
+			continue
+		}
+		if s.Name == js.Name || s.Name == runStepPrefix+js.Name {
+			return i
+		}
+		if byOrder < 0 {
+			byOrder = i
+		}
+		if byTime < 0 && !js.StartedAt.IsZero() && !s.startedAt.IsZero() && !s.startedAt.Before(startedAt) {
+			byTime = i
+		}
+	}
+	if byTime >= 0 {
+		return byTime
+	}
+	return byOrder
+}
+
+// nest adds an adjacent section to the step as a group, keeping the groups
+// in the order of the logs
+func (s *StepLog) nest(section StepLog) {
+	g := LogGroup{
+		Name:      section.Name,
+		StartLine: section.StartLine,
+		EndLine:   section.EndLine,
+		Groups:    section.Groups,
+	}
+	if section.StartLine < s.StartLine {
+		s.StartLine = section.StartLine
+		s.Groups = append([]LogGroup{g}, s.Groups...)
+		return
+	}
+	s.EndLine = max(s.EndLine, section.EndLine)
+	s.Groups = append(s.Groups, g)
+}
+
 // GetStepLogs returns the log content for a specific step
 // stepIndex = -1 returns all logs, otherwise returns the specific step's logs
 func (p *ParsedLogs) GetStepLogs(stepIndex int) string {
@@ -112,6 +380,52 @@ func (p *ParsedLogs) GetStepLogs(stepIndex int) string {
 	return strings.Join(p.Steps[stepIndex].Lines, "\n")
 }
 
+// StepLines returns the log lines of a specific step, or all lines for
+// stepIndex -1, without joining them
+func (p *ParsedLogs) StepLines(stepIndex int) []string {
+	if p == nil {
+		return nil
+	}
+	if stepIndex == -1 {
+		return p.AllLines
+	}
+	if stepIndex < 0 || stepIndex >= len(p.Steps) {
+		return nil
+	}
+	return p.Steps[stepIndex].Lines
+}
+
+// stepAt returns the step holding line i of AllLines, or nil if none does
+func (p *ParsedLogs) stepAt(i int) *StepLog {
+	for j := range p.Steps {
+		if p.Steps[j].StartLine <= i && i <= p.Steps[j].EndLine {
+			return &p.Steps[j]
+		}
+	}
+	return nil
+}
+
+// stepByNumber returns the step aligned with the job step of the given
+// number, or nil if there is none
+func (p *ParsedLogs) stepByNumber(number int) *StepLog {
+	for j := range p.Steps {
+		if number > 0 && p.Steps[j].Number == number {
+			return &p.Steps[j]
+		}
+	}
+	return nil
+}
+
+// lineNumber returns the number GitHub's log view gives line i of AllLines:
+// its position in its step, counted from 1. Lines outside the steps are
+// numbered from the start of the logs.
+func (p *ParsedLogs) lineNumber(i int) int {
+	if s := p.stepAt(i); s != nil {
+		return i - s.StartLine + 1
+	}
+	return i + 1
+}
+
 // formatStepLogsWithFunc formats all lines in the logs using the provided formatter function
 func (p *ParsedLogs) formatStepLogsWithFunc(stepIndex int, formatter func(string) string) string {
 	logs := p.GetStepLogs(stepIndex)
@@ -138,8 +452,10 @@ var (
 )
 
 // FormatLogLineWithColor applies syntax highlighting to a log line
-// It colors timestamps, GitHub Actions markers, and error/warning keywords
+// It colors timestamps, GitHub Actions markers, and error/warning keywords.
+// Lines colored by the tool that wrote them keep their own colors.
 func FormatLogLineWithColor(line string) string {
+	line = sanitizeLogLine(line)
 	if line == "" {
 		return ""
 	}
@@ -154,6 +470,14 @@ func FormatLogLineWithColor(line string) string {
 		rest = line
 	}
 
+	// Tool colors take precedence over highlighting
+	if hasANSIColor(rest) {
+		if timestamp != "" {
+			return LogTimestampStyle.Render(timestamp) + " " + rest
+		}
+		return rest
+	}
+
 	// Check for GitHub Actions markers (these color the entire line)
 	if errorMarkerRegex.MatchString(rest) {
 		if timestamp != "" {
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogWindowMargin is the number of lines kept formatted above and below the
+// visible window, so scrolling a few lines does not format them again
+const LogWindowMargin = 50
+
+// LogWindow is a virtualized view of log lines. Only the lines in the
+// visible window, plus a margin on either side, are formatted and wrapped,
+// and they are formatted lazily when first shown. Rendering and scrolling
+// therefore cost time proportional to the height of the window, not the
+// length of the logs. It scrolls by whole log lines and, like LogViewport,
+// follows the end of the logs until the user scrolls up.
+//
+// The current line is the line last jumped to, or the first visible line
+// after scrolling. An optional gutter labels each line, such as with its
+// number, and highlights the current line.
+type LogWindow struct {
+	lines      []string
+	format     func(string) string // Applied to a line before wrapping, nil for plain text
+	width      int
+	height     int
+	offset     int // Index of the first visible line
+	current    int // Index of the current line
+	autoscroll bool
+
+	gutter      func(i int) string // Label of the line at index i, nil for no gutter
+	gutterWidth int                // Width of the labels
+
+	rows      map[int][]string // Formatted, wrapped rows of lines near the window
+	maxOffset int              // Offset showing the end of the logs, -1 if not computed
+}
+
+// NewLogWindow creates an empty LogWindow with the specified dimensions.
+func NewLogWindow(width, height int) *LogWindow {
+	return &LogWindow{
+		width:      width,
+		height:     height,
+		autoscroll: true,
+		rows:       map[int][]string{},
+		maxOffset:  -1,
+	}
+}
+
+// SetLines replaces the lines shown in the window. Lines are passed through
+// format when they come into view. If autoscroll is enabled, the window
+// moves to the end of the lines.
+func (w *LogWindow) SetLines(lines []string, format func(string) string) {
+	w.lines = lines
+	w.format = format
+	w.invalidate()
+	w.offset = 0
+	if w.autoscroll {
+		w.GotoBottom()
+	}
+}
+
+// SetFormat changes how lines are formatted, keeping the scroll position.
+func (w *LogWindow) SetFormat(format func(string) string) {
+	w.format = format
+	w.invalidate()
+	if w.autoscroll {
+		w.GotoBottom()
+	} else {
+		w.offset = min(w.offset, w.bottomOffset())
+	}
+}
+
+// SetContent shows plain text, such as a status message, in the window,
+// without a gutter.
+func (w *LogWindow) SetContent(content string) {
+	w.gutter = nil
+	w.SetLines(strings.Split(content, "\n"), nil)
+}
+
+// SetGutter labels each line with label(i), right-aligned in width cells,
+// keeping the scroll position. A nil label removes the gutter.
+func (w *LogWindow) SetGutter(label func(i int) string, width int) {
+	w.gutter = label
+	w.gutterWidth = width
+	w.invalidate()
+	if w.autoscroll {
+		w.GotoBottom()
+	} else {
+		w.offset = min(w.offset, w.bottomOffset())
+	}
+}
+
+// SetSize resizes the window. Lines are wrapped to the new width.
+func (w *LogWindow) SetSize(width, height int) {
+	if width != w.width {
+		w.rows = map[int][]string{}
+	}
+	w.width = width
+	w.height = height
+	w.maxOffset = -1
+	if w.autoscroll {
+		w.GotoBottom()
+	} else {
+		w.offset = min(w.offset, w.bottomOffset())
+	}
+}
+
+// invalidate drops everything derived from the current lines
+func (w *LogWindow) invalidate() {
+	w.rows = map[int][]string{}
+	w.maxOffset = -1
+}
+
+// Len returns the number of log lines in the window.
+func (w *LogWindow) Len() int {
+	return len(w.lines)
+}
+
+// Offset returns the index of the first visible line.
+func (w *LogWindow) Offset() int {
+	return w.offset
+}
+
+// Current returns the index of the current line.
+func (w *LogWindow) Current() int {
+	return w.current
+}
+
+// wrapWidth returns the width lines are wrapped to, next to the gutter
+func (w *LogWindow) wrapWidth() int {
+	width := w.width
+	if width <= 0 {
+		width = DefaultWrapWidth
+	}
+	if w.gutter != nil {
+		width -= w.gutterWidth + 1
+	}
+	return max(width, 1)
+}
+
+// lineRows returns the formatted, wrapped rows of the line at index i,
+// formatting it on first use
+func (w *LogWindow) lineRows(i int) []string {
+	if rows, ok := w.rows[i]; ok {
+		return rows
+	}
+	line := w.lines[i]
+	if w.format != nil {
+		line = w.format(line)
+	}
+	rows := wrapANSI(line, w.wrapWidth())
+	w.rows[i] = rows
+	return rows
+}
+
+// bottomOffset returns the largest offset that still fills the window,
+// found by wrapping lines backwards from the end until the window is full
+func (w *LogWindow) bottomOffset() int {
+	if w.maxOffset >= 0 {
+		return w.maxOffset
+	}
+	offset, filled := len(w.lines), 0
+	for offset > 0 {
+		n := len(w.lineRows(offset - 1))
+		if filled+n > w.height && filled > 0 {
+			break
+		}
+		filled += n
+		offset--
+	}
+	w.maxOffset = offset
+	return offset
+}
+
+// isAtBottom returns true if the end of the lines is visible.
+func (w *LogWindow) isAtBottom() bool {
+	return w.offset >= w.bottomOffset()
+}
+
+// ScrollUp scrolls the window up by one line.
+func (w *LogWindow) ScrollUp() {
+	w.offset = max(w.offset-ScrollLineCount, 0)
+	w.current = w.offset
+	w.autoscroll = false
+}
+
+// ScrollDown scrolls the window down by one line.
+func (w *LogWindow) ScrollDown() {
+	w.offset = min(w.offset+ScrollLineCount, w.bottomOffset())
+	w.current = w.offset
+	w.autoscroll = w.isAtBottom()
+}
+
+// PageUp scrolls the window up by a page, so the first visible line ends
+// up just below it.
+func (w *LogWindow) PageUp() {
+	offset, filled := w.offset, 0
+	for offset > 0 {
+		n := len(w.lineRows(offset - 1))
+		if filled+n > w.height && filled > 0 {
+			break
+		}
+		filled += n
+		offset--
+	}
+	w.offset = offset
+	w.current = w.offset
+	w.autoscroll = false
+}
+
+// PageDown scrolls the window down by a page, so the first line that is not
+// fully visible becomes the first visible line.
+func (w *LogWindow) PageDown() {
+	n, filled := 0, 0
+	for w.offset+n < len(w.lines) {
+		filled += len(w.lineRows(w.offset + n))
+		if filled > w.height && n > 0 {
+			break
+		}
+		n++
+	}
+	w.offset = min(w.offset+max(n, 1), w.bottomOffset())
+	w.current = w.offset
+	w.autoscroll = w.isAtBottom()
+}
+
+// GotoTop scrolls to the first line.
+func (w *LogWindow) GotoTop() {
+	w.offset = 0
+	w.current = 0
+	w.autoscroll = false
+}
+
+// ScrollTo makes the line at index i the current line and scrolls so it is
+// the first visible line, or as close to it as the end of the lines allows.
+func (w *LogWindow) ScrollTo(i int) {
+	w.offset = max(0, min(i, w.bottomOffset()))
+	w.current = max(0, min(i, len(w.lines)-1))
+	w.autoscroll = w.isAtBottom()
+}
+
+// GotoBottom scrolls to the end of the lines, making the last line current.
+func (w *LogWindow) GotoBottom() {
+	w.offset = w.bottomOffset()
+	w.current = max(len(w.lines)-1, 0)
+	w.autoscroll = true
+}
+
+// Rows returns the rows visible in the window, at most its height. Only
+// the visible lines are formatted, and formatted lines outside the margin
+// are forgotten.
+func (w *LogWindow) Rows() []string {
+	if w.height <= 0 {
+		return nil
+	}
+	var rows []string
+	end := w.offset
+	for end < len(w.lines) && len(rows) < w.height {
+		if w.gutter != nil {
+			rows = append(rows, w.withGutter(end, w.lineRows(end))...)
+		} else {
+			rows = append(rows, w.lineRows(end)...)
+		}
+		end++
+	}
+	if len(rows) > w.height {
+		rows = rows[:w.height]
+	}
+	w.prune(w.offset-LogWindowMargin, end+LogWindowMargin)
+	return rows
+}
+
+// withGutter prefixes the rows of the line at index i with its label, and
+// the rows it wraps onto with blanks
+func (w *LogWindow) withGutter(i int, rows []string) []string {
+	style := LogGutterStyle
+	if i == w.current {
+		style = LogGutterCurrentStyle
+	}
+	label := style.Render(fmt.Sprintf("%*s", w.gutterWidth, w.gutter(i))) + " "
+	blank := strings.Repeat(" ", w.gutterWidth+1)
+	labeled := make([]string, len(rows))
+	for j, row := range rows {
+		if j == 0 {
+			labeled[j] = label + row
+		} else {
+			labeled[j] = blank + row
+		}
+	}
+	return labeled
+}
+
+// prune forgets formatted lines outside [from, to). The cache never holds
+// much more than the window and its margins, so this is cheap.
+func (w *LogWindow) prune(from, to int) {
+	if len(w.rows) <= (to-from)+w.height {
+		return
+	}
+	for i := range w.rows {
+		if i < from || i >= to {
+			delete(w.rows, i)
+		}
+	}
+}
+
+// View returns the visible rows joined by newlines.
+func (w *LogWindow) View() string {
+	return strings.Join(w.Rows(), "\n")
+}
@@ -0,0 +1,244 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns n lines "line 0" … "line n-1"
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = "line " + strconv.Itoa(i)
+	}
+	return lines
+}
+
+func TestLogWindow_FollowsEndOfLines(t *testing.T) {
+	w := NewLogWindow(80, 3)
+	w.SetLines(numberedLines(10), nil)
+
+	if got := w.View(); got != "line 7\nline 8\nline 9" {
+		t.Errorf("View() = %q, want the last 3 lines", got)
+	}
+	if !w.isAtBottom() {
+		t.Error("expected window to be at the bottom")
+	}
+}
+
+func TestLogWindow_Scrolling(t *testing.T) {
+	w := NewLogWindow(80, 3)
+	w.SetLines(numberedLines(10), nil)
+
+	w.GotoTop()
+	if got := w.View(); got != "line 0\nline 1\nline 2" {
+		t.Errorf("after GotoTop View() = %q", got)
+	}
+	w.ScrollUp()
+	if w.Offset() != 0 {
+		t.Errorf("ScrollUp at top moved to offset %d", w.Offset())
+	}
+
+	w.ScrollDown()
+	if got := w.View(); got != "line 1\nline 2\nline 3" {
+		t.Errorf("after ScrollDown View() = %q", got)
+	}
+	if w.autoscroll {
+		t.Error("autoscroll should stay off until the bottom is reached")
+	}
+
+	for range 20 {
+		w.ScrollDown()
+	}
+	if w.Offset() != 7 {
+		t.Errorf("ScrollDown past the end reached offset %d, want 7", w.Offset())
+	}
+	if !w.autoscroll {
+		t.Error("autoscroll should be re-enabled at the bottom")
+	}
+}
+
+func TestLogWindow_NoAutoscrollAfterScrollUp(t *testing.T) {
+	w := NewLogWindow(80, 3)
+	w.SetLines(numberedLines(10), nil)
+	w.GotoTop()
+
+	w.SetLines(numberedLines(20), nil)
+	if w.Offset() != 0 {
+		t.Errorf("SetLines without autoscroll moved to offset %d", w.Offset())
+	}
+}
+
+func TestLogWindow_WrapsLongLines(t *testing.T) {
+	w := NewLogWindow(10, 5)
+	w.SetLines([]string{strings.Repeat("a", 25), "short"}, nil)
+	w.GotoTop()
+
+	rows := w.Rows()
+	want := []string{"aaaaaaaaaa", "aaaaaaaaaa", "aaaaa", "short"}
+	if strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Errorf("Rows() = %q, want %q", rows, want)
+	}
+
+	// The end of the logs only needs the wrapped line's last rows to fill the window
+	w.SetSize(10, 2)
+	w.GotoBottom()
+	if w.Offset() != 1 {
+		t.Errorf("bottom offset = %d, want 1", w.Offset())
+	}
+	if got := w.Rows(); len(got) != 1 || got[0] != "short" {
+		t.Errorf("Rows() at bottom = %q", got)
+	}
+}
+
+func TestLogWindow_RowsNeverExceedHeight(t *testing.T) {
+	w := NewLogWindow(5, 2)
+	w.SetLines([]string{strings.Repeat("x", 50)}, nil)
+
+	if rows := w.Rows(); len(rows) != 2 {
+		t.Errorf("got %d rows, want 2", len(rows))
+	}
+}
+
+func TestLogWindow_ResizeRewraps(t *testing.T) {
+	w := NewLogWindow(10, 5)
+	w.SetLines([]string{strings.Repeat("a", 20)}, nil)
+	if rows := w.Rows(); len(rows) != 2 {
+		t.Fatalf("got %d rows at width 10, want 2", len(rows))
+	}
+
+	w.SetSize(20, 5)
+	if rows := w.Rows(); len(rows) != 1 {
+		t.Errorf("got %d rows at width 20, want 1", len(rows))
+	}
+}
+
+func TestLogWindow_FormatsOnlyVisibleLines(t *testing.T) {
+	const total, height = 200_000, 40
+	formatted := 0
+	format := func(s string) string {
+		formatted++
+		return "> " + s
+	}
+
+	w := NewLogWindow(80, height)
+	w.SetLines(numberedLines(total), format)
+	if got := w.Rows(); got[len(got)-1] != "> line 199999" {
+		t.Errorf("last row = %q", got[len(got)-1])
+	}
+
+	w.GotoTop()
+	for range 100 {
+		w.ScrollDown()
+		w.Rows()
+	}
+	if w.Rows()[0] != "> line 100" {
+		t.Errorf("first row = %q, want line 100", w.Rows()[0])
+	}
+
+	// The bottom window, the top window and the 100 lines scrolled into view
+	if limit := 2*height + 100; formatted > limit {
+		t.Errorf("formatted %d lines, want at most %d", formatted, limit)
+	}
+	if limit := 2*LogWindowMargin + 3*height; len(w.rows) > limit {
+		t.Errorf("cached %d lines, want at most %d", len(w.rows), limit)
+	}
+}
+
+func TestLogWindow_SetContent(t *testing.T) {
+	w := NewLogWindow(80, 10)
+	w.SetContent("Loading logs...")
+	if got := w.View(); got != "Loading logs..." {
+		t.Errorf("View() = %q", got)
+	}
+
+	w.SetContent("Job is queued.\nLogs will be available when job starts.")
+	if w.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", w.Len())
+	}
+}
+
+func TestLogWindow_EmptyAndZeroSize(t *testing.T) {
+	w := NewLogWindow(0, 0)
+	if got := w.View(); got != "" {
+		t.Errorf("empty window View() = %q", got)
+	}
+	w.SetLines(numberedLines(5), nil)
+	if got := w.View(); got != "" {
+		t.Errorf("zero height View() = %q", got)
+	}
+	w.ScrollDown()
+	w.ScrollUp()
+}
+
+func BenchmarkLogWindow(b *testing.B) {
+	w := NewLogWindow(120, 50)
+	w.SetLines(numberedLines(200_000), FormatLogLineWithColor)
+	w.GotoTop()
+
+	b.ResetTimer()
+	for i := range b.N {
+		if i%1000 == 0 {
+			w.GotoTop()
+		}
+		w.ScrollDown()
+		_ = w.View()
+	}
+}
+
+func TestLogWindow_Paging(t *testing.T) {
+	w := NewLogWindow(80, 3)
+	w.SetLines(numberedLines(10), nil)
+	w.GotoTop()
+
+	w.PageDown()
+	if w.Offset() != 3 || w.Current() != 3 {
+		t.Errorf("PageDown moved to offset %d, current %d, want 3", w.Offset(), w.Current())
+	}
+	w.PageDown()
+	w.PageDown()
+	if w.Offset() != 7 || !w.autoscroll {
+		t.Errorf("PageDown past the end reached offset %d, want the bottom", w.Offset())
+	}
+	w.PageUp()
+	if w.Offset() != 4 || w.autoscroll {
+		t.Errorf("PageUp moved to offset %d, want 4", w.Offset())
+	}
+
+	// Pages are measured in rows, so wrapped lines make them shorter
+	w = NewLogWindow(10, 3)
+	w.SetLines([]string{"0", strings.Repeat("x", 25), "2", "3"}, nil)
+	w.GotoTop()
+	w.PageDown()
+	if w.Offset() != 1 {
+		t.Errorf("PageDown moved to offset %d, want the wrapped line that did not fit", w.Offset())
+	}
+
+	w.GotoBottom()
+	if w.Current() != 3 {
+		t.Errorf("GotoBottom made line %d current, want the last", w.Current())
+	}
+}
+
+func TestLogWindow_Gutter(t *testing.T) {
+	w := NewLogWindow(12, 4)
+	w.SetLines([]string{"short", "a long line that wraps"}, nil)
+	w.GotoTop()
+	w.SetGutter(func(i int) string { return strconv.Itoa(i + 9) }, 2)
+
+	want := []string{" 9 short", "10 a long li", "   ne that w", "   raps"}
+	if got := w.Rows(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Rows() = %q, want %q", got, want)
+	}
+
+	w.ScrollTo(1)
+	if w.Current() != 1 {
+		t.Errorf("ScrollTo made line %d current, want 1", w.Current())
+	}
+
+	w.SetContent("Loading")
+	if got := w.View(); got != "Loading" {
+		t.Errorf("SetContent should drop the gutter, got %q", got)
+	}
+}
@@ -0,0 +1,483 @@
+package app
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Block syntax recognized by the Markdown renderer
+var (
+	mdHeadingRegex   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))??(?:\s+#+)?\s*$`)
+	mdFenceRegex     = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})\\s*([^`]*)$")
+	mdRuleRegex      = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdSetextRegex    = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	mdListItemRegex  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(?:\s+(.*))?$`)
+	mdTaskRegex      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdDelimiterRegex = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// Inline syntax recognized by the Markdown renderer
+var (
+	mdCommentRegex  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	mdAutolinkRegex = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	mdSummaryRegex  = regexp.MustCompile(`(?i)<summary>(.*?)</summary>`)
+	mdBreakRegex    = regexp.MustCompile(`(?i)<br\s*/?>`)
+	mdImgTagRegex   = regexp.MustCompile(`(?i)<img\s[^>]*?alt="([^"]*)"[^>]*>`)
+	mdTagRegex      = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdImageRegex    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)[^)]*\)`)
+	mdLinkRegex     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	mdBoldRegex     = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdStrikeRegex   = regexp.MustCompile(`~~(.+?)~~`)
+	mdItalicRegex   = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	mdUnderRegex    = regexp.MustCompile(`(^|[^\w])_([^_\s](?:[^_]*[^_\s])?)_($|[^\w])`)
+	mdHeldRegex     = regexp.MustCompile("\x1a(\\d+)\x1a")
+)
+
+// mdBullets are the list markers of nested list levels
+var mdBullets = []string{"•", "◦", "▪"}
+
+// Column alignment in a Markdown table
+const (
+	alignLeft = iota
+	alignCenter
+	alignRight
+)
+
+// renderMarkdown renders Markdown, such as a job summary, as lines of at
+// most width cells. It handles the GitHub Flavored Markdown that summaries
+// use: headings, paragraphs, lists, block quotes, code blocks, tables,
+// links and simple HTML.
+func renderMarkdown(src string, width int) []string {
+	width = max(width, 10)
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out []string
+	// Lines of the open paragraph or list item, and the prefixes of its
+	// first and following rows
+	var text []string
+	var first, rest string
+	flush := func() {
+		if len(text) > 0 {
+			out = append(out, wrapMarkdown(renderInline(strings.Join(text, " ")), first, rest, width)...)
+			text = nil
+		}
+	}
+	blank := func() {
+		flush()
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// A line of = or - under a paragraph makes it a heading
+		if m := mdSetextRegex.FindStringSubmatch(line); m != nil && len(text) > 0 && first == "" {
+			heading := strings.Join(text, " ")
+			text = nil
+			level := 2
+			if m[1][0] == '=' {
+				level = 1
+			}
+			out = append(out, renderHeading(level, heading, width)...)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			blank()
+
+		case mdFenceRegex.MatchString(line):
+			flush()
+			m := mdFenceRegex.FindStringSubmatch(line)
+			var code []string
+			for i++; i < len(lines); i++ {
+				if closing := strings.TrimSpace(lines[i]); strings.HasPrefix(closing, m[2]) && strings.Trim(closing, m[2][:1]) == "" {
+					break
+				}
+				code = append(code, strings.TrimPrefix(lines[i], m[1]))
+			}
+			out = append(out, renderCodeBlock(code, width)...)
+
+		case mdHeadingRegex.MatchString(line):
+			flush()
+			m := mdHeadingRegex.FindStringSubmatch(line)
+			out = append(out, renderHeading(len(m[1]), m[2], width)...)
+
+		case mdRuleRegex.MatchString(line):
+			flush()
+			out = append(out, MarkdownRule.Render(strings.Repeat("─", width)))
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines); i++ {
+				q, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), ">")
+				if !ok {
+					break
+				}
+				quote = append(quote, strings.TrimPrefix(q, " "))
+			}
+			i--
+			for _, row := range renderMarkdown(strings.Join(quote, "\n"), width-2) {
+				out = append(out, MarkdownRule.Render("│ ")+row)
+			}
+
+		case strings.Contains(line, "|") && i+1 < len(lines) && mdDelimiterRegex.MatchString(lines[i+1]):
+			flush()
+			header, delimiter := lines[i], lines[i+1]
+			var rows []string
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				rows = append(rows, lines[i])
+			}
+			i--
+			out = append(out, renderTable(header, delimiter, rows, width)...)
+
+		case mdListItemRegex.MatchString(line):
+			flush()
+			m := mdListItemRegex.FindStringSubmatch(line)
+			indent := strings.Repeat("  ", min(len(strings.ReplaceAll(m[1], "\t", "  "))/2, 4))
+			marker := m[2]
+			if marker == "-" || marker == "*" || marker == "+" {
+				marker = mdBullets[(len(indent)/2)%len(mdBullets)]
+			}
+			item := m[3]
+			if t := mdTaskRegex.FindStringSubmatch(item); t != nil {
+				item = item[len(t[0]):]
+				if t[1] == " " {
+					marker += " ☐"
+				} else {
+					marker += " " + SuccessStyle.Render("☑")
+				}
+			}
+			first = indent + marker + " "
+			rest = strings.Repeat(" ", ansi.StringWidth(first))
+			text = []string{item}
+
+		case strings.HasPrefix(trimmed, "<"):
+			// HTML blocks, such as <details>, stand on their own
+			flush()
+			if s := strings.TrimSpace(renderInline(trimmed)); s != "" {
+				out = append(out, wrapMarkdown(s, "", "", width)...)
+			}
+
+		default:
+			// Paragraph text, or the continuation of a list item
+			if len(text) == 0 {
+				first, rest = "", ""
+			}
+			text = append(text, trimmed)
+		}
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// wrapMarkdown word-wraps rendered text to width, starting the first row
+// with first and the others with rest
+func wrapMarkdown(s, first, rest string, width int) []string {
+	rows := strings.Split(ansi.Wrap(s, max(width-ansi.StringWidth(first), 1), ""), "\n")
+	if strings.ContainsRune(s, '\x1b') {
+		rows = carrySGR(rows)
+	}
+	for i := range rows {
+		if i == 0 {
+			rows[i] = first + rows[i]
+		} else {
+			rows[i] = rest + rows[i]
+		}
+	}
+	return rows
+}
+
+// renderHeading renders a heading, underlining the top two levels
+func renderHeading(level int, text string, width int) []string {
+	rows := wrapMarkdown(MarkdownHeading.Render(ansi.Strip(renderInline(text))), "", "", width)
+	if level <= 2 {
+		underline := "━"
+		if level == 2 {
+			underline = "─"
+		}
+		w := 0
+		for _, row := range rows {
+			w = max(w, ansi.StringWidth(row))
+		}
+		rows = append(rows, MarkdownRule.Render(strings.Repeat(underline, max(w, 1))))
+	}
+	return rows
+}
+
+// renderCodeBlock renders the lines of a fenced code block, wrapping long
+// lines
+func renderCodeBlock(code []string, width int) []string {
+	var out []string
+	for _, line := range code {
+		for _, row := range wrapANSI(sanitizeLogLine(line), width-2) {
+			out = append(out, MarkdownRule.Render("▏ ")+MarkdownCode.Render(row))
+		}
+	}
+	return out
+}
+
+// renderTable renders a table with box-drawing borders, shrinking the widest
+// columns and truncating their cells when the table is wider than width
+func renderTable(header, delimiter string, rows []string, width int) []string {
+	var aligns []int
+	for _, d := range splitTableRow(delimiter) {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			aligns = append(aligns, alignCenter)
+		case strings.HasSuffix(d, ":"):
+			aligns = append(aligns, alignRight)
+		default:
+			aligns = append(aligns, alignLeft)
+		}
+	}
+	cols := len(aligns)
+
+	// Render the cells, one row per table line, with the header first
+	var cells [][]string
+	for _, line := range append([]string{header}, rows...) {
+		row := make([]string, cols)
+		for j, cell := range splitTableRow(line) {
+			if j < cols {
+				row[j] = renderInline(cell)
+			}
+		}
+		cells = append(cells, row)
+	}
+	for j := range cells[0] {
+		cells[0][j] = MarkdownBold.Render(cells[0][j])
+	}
+
+	widths := make([]int, cols)
+	for _, row := range cells {
+		for j, cell := range row {
+			widths[j] = max(widths[j], ansi.StringWidth(cell), 1)
+		}
+	}
+	// Each column takes its width plus a space on both sides and a border
+	for {
+		total, widest := cols*3+1, 0
+		for j, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = j
+			}
+		}
+		if total <= width || widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	border := func(left, mid, right string) string {
+		var b strings.Builder
+		b.WriteString(left)
+		for j, w := range widths {
+			if j > 0 {
+				b.WriteString(mid)
+			}
+			b.WriteString(strings.Repeat("─", w+2))
+		}
+		b.WriteString(right)
+		return MarkdownRule.Render(b.String())
+	}
+	bar := MarkdownRule.Render("│")
+
+	out := []string{border("┌", "┬", "┐")}
+	for i, row := range cells {
+		var b strings.Builder
+		b.WriteString(bar)
+		for j, cell := range row {
+			b.WriteString(" " + alignCell(ansi.Truncate(cell, widths[j], "…"), widths[j], aligns[j]) + " " + bar)
+		}
+		out = append(out, b.String())
+		if i == 0 {
+			out = append(out, border("├", "┼", "┤"))
+		}
+	}
+	out = append(out, border("└", "┴", "┘"))
+
+	// Tables that cannot shrink enough are cut at the edge
+	for i, line := range out {
+		if ansi.StringWidth(line) > width {
+			out[i] = ansi.Truncate(line, width, "")
+		}
+	}
+	return out
+}
+
+// splitTableRow splits a table row into its trimmed cells, leaving escaped
+// pipes and pipes in code spans inside their cell
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			inCode = !inCode
+			cell.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// alignCell pads a cell to width cells
+func alignCell(cell string, width, align int) string {
+	pad := max(width-ansi.StringWidth(cell), 0)
+	switch align {
+	case alignRight:
+		return strings.Repeat(" ", pad) + cell
+	case alignCenter:
+		return strings.Repeat(" ", pad/2) + cell + strings.Repeat(" ", pad-pad/2)
+	default:
+		return cell + strings.Repeat(" ", pad)
+	}
+}
+
+// inlineRenderer renders inline Markdown. Text that must not be parsed
+// further, such as code spans and rendered links, is held aside behind a
+// placeholder until the end.
+type inlineRenderer struct {
+	held []string
+}
+
+// hold sets s aside and returns its placeholder
+func (r *inlineRenderer) hold(s string) string {
+	r.held = append(r.held, s)
+	return "\x1a" + strconv.Itoa(len(r.held)-1) + "\x1a"
+}
+
+// restore replaces placeholders with the text they hold
+func (r *inlineRenderer) restore(s string) string {
+	for strings.ContainsRune(s, '\x1a') {
+		next := mdHeldRegex.ReplaceAllStringFunc(s, func(m string) string {
+			i, _ := strconv.Atoi(m[1 : len(m)-1])
+			return r.held[i]
+		})
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+// renderInline renders the inline Markdown of a line: code spans, links,
+// images, emphasis, HTML tags and entities
+func renderInline(s string) string {
+	r := &inlineRenderer{}
+	s = r.holdCode(sanitizeLogLine(s))
+
+	s = mdCommentRegex.ReplaceAllString(s, "")
+	s = mdAutolinkRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return r.hold(MarkdownLink.Render(m[1 : len(m)-1]))
+	})
+	s = mdSummaryRegex.ReplaceAllString(s, "▸ $1")
+	s = mdBreakRegex.ReplaceAllString(s, " ")
+	s = mdImgTagRegex.ReplaceAllString(s, "![$1]()")
+	s = mdTagRegex.ReplaceAllString(s, "")
+
+	s = mdImageRegex.ReplaceAllStringFunc(s, func(m string) string {
+		alt := mdImageRegex.FindStringSubmatch(m)[1]
+		if alt == "" {
+			return r.hold(QueuedStyle.Render("[image]"))
+		}
+		return r.hold(QueuedStyle.Render("[image: " + html.UnescapeString(alt) + "]"))
+	})
+	s = mdLinkRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRegex.FindStringSubmatch(m)
+		text, url := renderEmphasis(sub[1]), sub[2]
+		if ansi.Strip(r.restore(text)) == url || strings.HasPrefix(url, "#") {
+			return r.hold(MarkdownLink.Render(text))
+		}
+		return r.hold(MarkdownLink.Render(text) + " " + QueuedStyle.Render("("+url+")"))
+	})
+
+	return r.restore(renderEmphasis(s))
+}
+
+// holdCode sets code spans and backslash escapes aside, so their contents
+// are shown as written
+func (r *inlineRenderer) holdCode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|<>~", s[i+1]) >= 0 {
+			b.WriteString(r.hold(string(s[i+1])))
+			i++
+			continue
+		}
+		if c != '`' {
+			b.WriteByte(c)
+			continue
+		}
+
+		// A code span ends at a backtick run of the same length
+		n := 1
+		for i+n < len(s) && s[i+n] == '`' {
+			n++
+		}
+		fence := strings.Repeat("`", n)
+		end := -1
+		for j := i + n; j <= len(s)-n; j++ {
+			if s[j:j+n] == fence && (j+n == len(s) || s[j+n] != '`') && s[j-1] != '`' {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			b.WriteString(fence)
+			i += n - 1
+			continue
+		}
+		b.WriteString(r.hold(MarkdownCode.Render(strings.TrimSpace(s[i+n : end]))))
+		i = end + n - 1
+	}
+	return b.String()
+}
+
+// renderEmphasis renders bold, italic and strikethrough text and decodes
+// HTML entities
+func renderEmphasis(s string) string {
+	s = mdBoldRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return MarkdownBold.Render(m[2 : len(m)-2])
+	})
+	s = mdStrikeRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return MarkdownStrike.Render(m[2 : len(m)-2])
+	})
+	s = mdItalicRegex.ReplaceAllStringFunc(s, func(m string) string {
+		return MarkdownItalic.Render(m[1 : len(m)-1])
+	})
+	s = mdUnderRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdUnderRegex.FindStringSubmatch(m)
+		return sub[1] + MarkdownItalic.Render(sub[2]) + sub[3]
+	})
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// renderPlain renders Markdown without colors, one string per row
+func renderPlain(src string, width int) string {
+	return ansi.Strip(strings.Join(renderMarkdown(src, width), "\n"))
+}
+
+func TestRenderMarkdown_Blocks(t *testing.T) {
+	src := strings.Join([]string{
+		"# Coverage report",
+		"",
+		"Total coverage is **81.2%**.",
+		"",
+		"Setext heading",
+		"--------------",
+		"",
+		"```go",
+		"func main() {}",
+		"```",
+		"",
+		"- [x] unit tests",
+		"- [ ] e2e tests",
+		"  - nested",
+		"1. first",
+		"",
+		"> quoted *text*",
+		"",
+		"***",
+	}, "\n")
+
+	got := renderPlain(src, 40)
+	for _, want := range []string{
+		"Coverage report\n━━━━━━━━━━━━━━━",
+		"Total coverage is 81.2%.",
+		"Setext heading\n──────────────",
+		"▏ func main() {}",
+		"• ☑ unit tests\n• ☐ e2e tests\n  ◦ nested\n1. first",
+		"│ quoted text",
+		strings.Repeat("─", 40),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered Markdown should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "```") || strings.Contains(got, "**") {
+		t.Errorf("Markdown syntax should not be shown, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdown_Inline(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"link", "see [the docs](https://example.com/docs)", "see the docs (https://example.com/docs)"},
+		{"bare link", "[https://example.com](https://example.com)", "https://example.com"},
+		{"autolink", "<https://example.com>", "https://example.com"},
+		{"image", "![coverage badge](https://example.com/badge.svg)", "[image: coverage badge]"},
+		{"code", "run `go test ./... **not bold**`", "run go test ./... **not bold**"},
+		{"emphasis", "**bold**, *italic*, _also_ and ~~gone~~", "bold, italic, also and gone"},
+		{"identifiers", "snake_case_name and 2 * 3 * 4", "snake_case_name and 2 * 3 * 4"},
+		{"escapes", `\*literal\* and \[brackets\]`, "*literal* and [brackets]"},
+		{"html", "a<br>b <b>c</b> &amp; <!-- hidden -->d", "a b c & d"},
+		{"summary", "<details><summary>Details</summary>", "▸ Details"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderPlain(tt.src, 80); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_Table(t *testing.T) {
+	src := strings.Join([]string{
+		"| Package | Coverage | Δ |",
+		"|:--------|---------:|:-:|",
+		"| `app` | 80.1% | +0.2 |",
+		"| github \\| api | 92.0% |",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"┌──────────────┬──────────┬──────┐",
+		"│ Package      │ Coverage │  Δ   │",
+		"├──────────────┼──────────┼──────┤",
+		"│ app          │    80.1% │ +0.2 │",
+		"│ github | api │    92.0% │      │",
+		"└──────────────┴──────────┴──────┘",
+	}, "\n")
+	if got := renderPlain(src, 80); got != want {
+		t.Errorf("table:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	// Narrow tables shrink their widest column
+	for _, row := range strings.Split(renderPlain(src, 28), "\n") {
+		if w := ansi.StringWidth(row); w > 28 {
+			t.Errorf("row %q is %d cells wide, want at most 28", row, w)
+		}
+	}
+	if got := renderPlain(src, 28); !strings.Contains(got, "│ github… │ ") {
+		t.Errorf("cells of shrunk columns should be truncated, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdown_Wrap(t *testing.T) {
+	rows := renderMarkdown("- a list item with **bold words** long enough to wrap", 20)
+	if len(rows) < 2 {
+		t.Fatalf("rows = %q, want the item wrapped", rows)
+	}
+	for i, row := range rows {
+		if w := ansi.StringWidth(row); w > 20 {
+			t.Errorf("row %q is %d cells wide, want at most 20", row, w)
+		}
+		if i > 0 && !strings.HasPrefix(ansi.Strip(row), "  ") {
+			t.Errorf("row %q should be indented under the bullet", row)
+		}
+	}
+}
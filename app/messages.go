@@ -15,22 +15,67 @@ type WorkflowsLoadedMsg struct {
 }
 
 // RunsLoadedMsg is sent when workflow runs have been fetched from GitHub.
+// WorkflowID and Generation identify the request it answers; a zero
+// Generation means it does not answer a request and is always applied.
 type RunsLoadedMsg struct {
-	Runs []github.Run
-	Err  error
+	WorkflowID int64
+	Generation uint64
+	Runs       []github.Run
+	Err        error
 }
 
 // JobsLoadedMsg is sent when jobs have been fetched from GitHub.
+// RunID and Generation identify the request it answers; a zero
+// Generation means it does not answer a request and is always applied.
 type JobsLoadedMsg struct {
-	Jobs []github.Job
-	Err  error
+	RunID      int64
+	Generation uint64
+	Jobs       []github.Job
+	Err        error
 }
 
 // LogsLoadedMsg is sent when job logs have been fetched from GitHub.
 type LogsLoadedMsg struct {
-	JobID int64
-	Logs  string
-	Err   error
+	JobID      int64
+	Logs       string      // Redacted logs
+	Parsed     *ParsedLogs // Logs already parsed, nil if they are to be parsed on receipt
+	Redactions int         // Number of values redacted
+	Err        error
+}
+
+// AnnotationsLoadedMsg is sent when check run annotations of a job have been fetched from GitHub.
+type AnnotationsLoadedMsg struct {
+	JobID       int64
+	Annotations []github.Annotation
+	Err         error
+}
+
+// JobSummaryLoadedMsg is sent when the check run summary of a job has been fetched from GitHub.
+type JobSummaryLoadedMsg struct {
+	JobID   int64
+	Summary github.JobSummary
+	Err     error
+}
+
+// AnalyticsLoadedMsg is sent when run statistics for a workflow have been computed.
+type AnalyticsLoadedMsg struct {
+	WorkflowID int64
+	Stats      WorkflowStats
+	Err        error
+}
+
+// FailureSummaryLoadedMsg is sent when the failed jobs of a run and their
+// logs have been fetched and summarized.
+type FailureSummaryLoadedMsg struct {
+	RunID   int64
+	Summary FailureSummary
+	Err     error
+}
+
+// PermissionsCheckedMsg is sent when the token's permission to change runs has been checked.
+type PermissionsCheckedMsg struct {
+	Permissions github.Permissions
+	Err         error
 }
 
 // === Action Results ===
@@ -61,6 +106,11 @@ type WorkflowTriggeredMsg struct {
 
 // === UI State ===
 
+// EditorClosedMsg is sent when the external editor opened for an annotation exits.
+type EditorClosedMsg struct {
+	Err error
+}
+
 // FlashMsg is sent to display a temporary message to the user.
 type FlashMsg struct {
 	Message  string
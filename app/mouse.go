@@ -12,7 +12,7 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	a.mouseY = msg.Y
 
 	// Ignore actions when popups are shown
-	if a.showHelp || a.showConfirm || a.fullscreenLog || a.filtering {
+	if a.showHelp || a.showConfirm || a.showFailureSummary || a.fullscreenLog || a.filtering || a.gotoLine {
 		return a, nil
 	}
 
@@ -83,12 +83,12 @@ func (a *App) handleDetailPanelClick(_, y, _, _ int) (tea.Model, tea.Cmd) {
 	// Line 2 (y=3): "  Steps: (hint)"
 	// Line 3 (y=4): empty
 	// Line 4 (y=5): "All logs" option (selectedStepIdx = -1)
-	// Line 5+ (y=6+): individual steps (selectedStepIdx = 0, 1, 2, ...)
+	// Line 5+ (y=6+): steps and expanded groups (selectedStepIdx = 0, 1, 2, ...)
 
 	// Content starts at y=1 (after top border)
 	// Step list starts at content line 4 (y=5)
 	stepListStartY := 5 // "All logs" is at y=5
-	stepCount := len(a.parsedLogs.Steps)
+	stepCount := len(a.stepListRows())
 
 	// Check if click is in the step list area
 	if y >= stepListStartY && y < stepListStartY+1+stepCount {
@@ -169,7 +169,12 @@ func (a *App) handleScrollDown() (tea.Model, tea.Cmd) {
 // onWorkflowSelectionChange handles workflow selection change
 func (a *App) onWorkflowSelectionChange() tea.Cmd {
 	if wf, ok := a.workflows.Selected(); ok {
+		// Requests for the previous workflow are no longer wanted
+		a.cancelRequests(requestJobs, requestLogs, requestAnnotations, requestSummary, requestAnalytics)
 		a.loading = true
+		if a.detailTab == AnalyticsTab {
+			return tea.Batch(a.fetchRunsCmd(wf.ID), a.fetchAnalyticsCmd())
+		}
 		return a.fetchRunsCmd(wf.ID)
 	}
 	return nil
@@ -178,6 +183,8 @@ func (a *App) onWorkflowSelectionChange() tea.Cmd {
 // onRunSelectionChange handles run selection change
 func (a *App) onRunSelectionChange() tea.Cmd {
 	if run, ok := a.runs.Selected(); ok {
+		// Requests for the previous run's jobs are no longer wanted
+		a.cancelRequests(requestLogs, requestAnnotations, requestSummary)
 		a.loading = true
 		return a.fetchJobsCmd(run.ID)
 	}
@@ -191,19 +198,39 @@ func (a *App) onJobSelectionChange() tea.Cmd {
 		return nil
 	}
 
+	// Requests for the previous job are no longer wanted
+	a.cancelRequests(requestLogs, requestAnnotations, requestSummary)
+
 	// Reset step selection for new job
 	a.parsedLogs = nil
 	a.selectedStepIdx = -1
 	a.stepListFocused = true
+	a.expandedGroups = map[int]bool{}
+	a.annotationIdx = 0
+	a.annotationsFocused = false
+	a.problemIdx = 0
+	a.problemsFocused = false
+	a.testIdx = 0
+	a.testsFocused = false
+	a.summaryFocused = false
+
+	// Tabs showing data fetched for the job fetch it for the new one
+	var tabCmd tea.Cmd
+	switch a.detailTab {
+	case AnnotationsTab:
+		tabCmd = a.fetchAnnotationsCmd()
+	case SummaryTab:
+		tabCmd = a.fetchSummaryCmd()
+	}
 
 	// GitHub API only provides logs for completed jobs
 	if !job.IsCompleted() {
 		a.logView.SetContent(jobStatusMessage(job))
-		return nil
+		return tabCmd
 	}
 
 	a.logView.SetContent("Loading logs...")
-	return a.fetchLogsCmd(job.ID)
+	return tea.Batch(a.fetchLogsCmd(job.ID), tabCmd)
 }
 
 // jobStatusMessage returns a user-friendly message for incomplete jobs
@@ -221,15 +248,14 @@ func (a *App) updateLogViewContent() {
 		return
 	}
 
-	// Get logs for the selected step (formatted with syntax highlighting)
-	logs := a.parsedLogs.FormatStepLogsWithColor(a.selectedStepIdx)
-	if logs == "" {
-		logs = "No logs available"
+	// Lines are highlighted and wrapped lazily as they scroll into view
+	lines := a.selectedLogLines()
+	if len(lines) == 0 {
+		a.logView.SetContent("No logs available")
+		return
 	}
-
-	// Wrap log lines to fit within viewport width
-	wrappedLogs := wrapLines(logs, a.logPaneWidth()-4)
-	a.logView.SetContent(wrappedLogs)
+	a.logView.SetLines(lines, a.logColorMode.formatter())
+	a.setLogGutter()
 }
 
 // navigateStepUp moves step selection up
@@ -250,7 +276,7 @@ func (a *App) navigateStepDown() {
 	if a.parsedLogs == nil || len(a.parsedLogs.Steps) == 0 {
 		return
 	}
-	maxIdx := len(a.parsedLogs.Steps) - 1
+	maxIdx := len(a.stepListRows()) - 1
 	if a.selectedStepIdx < maxIdx {
 		a.selectedStepIdx++
 		a.updateLogViewContent()
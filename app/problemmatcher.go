@@ -0,0 +1,342 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProblemMatcher recognizes problems in log output. It uses the format of
+// GitHub Actions problem matchers, so matcher files written for the runner
+// can be loaded as they are:
+// https://github.com/actions/toolkit/blob/main/docs/problem-matchers.md
+type ProblemMatcher struct {
+	Owner    string           `json:"owner"`
+	Severity string           `json:"severity,omitempty"` // Used when the pattern captures none
+	Pattern  []ProblemPattern `json:"pattern"`
+}
+
+// ProblemPattern matches one line of a problem. The fields other than
+// Regexp are the indexes of the capture groups holding each property.
+// Problems spanning several lines use a pattern per line; the last pattern
+// may loop to match several problems after the same leading lines.
+type ProblemPattern struct {
+	Regexp   string `json:"regexp"`
+	File     int    `json:"file,omitempty"`
+	FromPath int    `json:"fromPath,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity int    `json:"severity,omitempty"`
+	Code     int    `json:"code,omitempty"`
+	Message  int    `json:"message,omitempty"`
+	Loop     bool   `json:"loop,omitempty"`
+}
+
+// problemMatcherFile is the top level of a problem matcher JSON file
+type problemMatcherFile struct {
+	ProblemMatcher []ProblemMatcher `json:"problemMatcher"`
+}
+
+// Diagnostic is a problem recognized in the logs, such as a compiler error
+// or a failed test
+type Diagnostic struct {
+	Owner    string // Owner of the matcher that recognized the problem
+	Severity string // error, warning or notice
+	File     string
+	Line     int
+	Column   int
+	Code     string
+	Message  string
+	LogLine  int // Line of the logs the problem was recognized on, in ParsedLogs.AllLines
+}
+
+// Diagnostic severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNotice  = "notice"
+)
+
+// normalizeSeverity maps the severities tools print to those of Diagnostic
+func normalizeSeverity(s string) string {
+	switch strings.ToLower(s) {
+	case "warning", "warn":
+		return SeverityWarning
+	case "notice", "info":
+		return SeverityNotice
+	default:
+		return SeverityError
+	}
+}
+
+// builtinProblemMatchers recognize the output of common tools. Matchers are
+// tried in order and the first to recognize a problem on a line wins, so
+// more specific matchers come first.
+var builtinProblemMatchers = []ProblemMatcher{
+	{
+		Owner:   "actions",
+		Pattern: []ProblemPattern{{Regexp: `^##\[(error|warning|notice)\](.*)$`, Severity: 1, Message: 2}},
+	},
+	{
+		Owner: "tsc",
+		Pattern: []ProblemPattern{{
+			Regexp: `^([^\s].*?)\((\d+),(\d+)\):\s+(error|warning|info)\s+(TS\d+)\s*:\s*(.*)$`,
+			File:   1, Line: 2, Column: 3, Severity: 4, Code: 5, Message: 6,
+		}},
+	},
+	{
+		Owner: "tsc-pretty",
+		Pattern: []ProblemPattern{{
+			Regexp: `^([^\s].*?):(\d+):(\d+) - (error|warning|info) (TS\d+): (.*)$`,
+			File:   1, Line: 2, Column: 3, Severity: 4, Code: 5, Message: 6,
+		}},
+	},
+	{
+		Owner:   "go",
+		Pattern: []ProblemPattern{{Regexp: `^\s*(?:\./)?([^\s:]+\.go):(\d+)(?::(\d+))?: (.*)$`, File: 1, Line: 2, Column: 3, Message: 4}},
+	},
+	{
+		Owner:   "go-test",
+		Pattern: []ProblemPattern{{Regexp: `^\s*--- (FAIL: \S+.*)$`, Message: 1}},
+	},
+	{
+		Owner:   "pytest",
+		Pattern: []ProblemPattern{{Regexp: `^(?:FAILED|ERROR) ([^\s:]+)::(\S+(?: - .*)?)$`, File: 1, Message: 2}},
+	},
+	{
+		Owner:   "python",
+		Pattern: []ProblemPattern{{Regexp: `^([^\s:]+\.py):(\d+): (.+)$`, File: 1, Line: 2, Message: 3}},
+	},
+	{
+		Owner: "eslint-stylish",
+		Pattern: []ProblemPattern{
+			{Regexp: `^([^\s].*)$`, File: 1},
+			{Regexp: `^\s+(\d+):(\d+)\s+(error|warning|info)\s+(.*?)\s\s+(\S+)$`, Line: 1, Column: 2, Severity: 3, Message: 4, Code: 5, Loop: true},
+		},
+	},
+}
+
+// MatcherRegistry holds the problem matchers that logs are scanned with.
+// It is not changed while logs are scanned, so one registry can be shared
+// by concurrent log fetches.
+type MatcherRegistry struct {
+	matchers []*compiledMatcher
+}
+
+// compiledMatcher is a ProblemMatcher with its patterns compiled
+type compiledMatcher struct {
+	ProblemMatcher
+	patterns []*regexp.Regexp
+}
+
+// NewMatcherRegistry creates a registry with the built-in matchers.
+func NewMatcherRegistry() *MatcherRegistry {
+	r := &MatcherRegistry{}
+	for _, m := range slices.Backward(builtinProblemMatchers) {
+		if err := r.Register(m); err != nil {
+			panic("invalid built-in problem matcher " + m.Owner + ": " + err.Error())
+		}
+	}
+	return r
+}
+
+// defaultMatchers is used when no registry is configured
+var defaultMatchers = NewMatcherRegistry()
+
+// Register adds a matcher, which is tried before those registered earlier,
+// so user matchers take precedence over the built-in ones. A matcher with
+// the same owner as a registered one replaces it in place, as with the
+// runner's add-matcher command.
+func (r *MatcherRegistry) Register(m ProblemMatcher) error {
+	if m.Owner == "" {
+		return errors.New("problem matcher has no owner")
+	}
+	if len(m.Pattern) == 0 {
+		return fmt.Errorf("problem matcher %s has no patterns", m.Owner)
+	}
+	cm := &compiledMatcher{ProblemMatcher: m}
+	for i, p := range m.Pattern {
+		re, err := regexp.Compile(p.Regexp)
+		if err != nil {
+			return fmt.Errorf("problem matcher %s: %w", m.Owner, err)
+		}
+		for _, group := range []int{p.File, p.FromPath, p.Line, p.Column, p.Severity, p.Code, p.Message} {
+			if group > re.NumSubexp() {
+				return fmt.Errorf("problem matcher %s: pattern %d has no group %d", m.Owner, i+1, group)
+			}
+		}
+		if p.Loop && (i != len(m.Pattern)-1 || i == 0) {
+			return fmt.Errorf("problem matcher %s: only the last of several patterns can loop", m.Owner)
+		}
+		cm.patterns = append(cm.patterns, re)
+	}
+	if m.Pattern[len(m.Pattern)-1].Message == 0 {
+		return fmt.Errorf("problem matcher %s: the last pattern must capture a message", m.Owner)
+	}
+
+	for i, existing := range r.matchers {
+		if existing.Owner == m.Owner {
+			r.matchers[i] = cm
+			return nil
+		}
+	}
+	r.matchers = append([]*compiledMatcher{cm}, r.matchers...)
+	return nil
+}
+
+// LoadFile registers the matchers of a problem matcher JSON file.
+func (r *MatcherRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read problem matchers: %w", err)
+	}
+	matchers, err := ParseProblemMatchers(data)
+	if err != nil {
+		return fmt.Errorf("invalid problem matchers %s: %w", path, err)
+	}
+	for _, m := range matchers {
+		if err := r.Register(m); err != nil {
+			return fmt.Errorf("invalid problem matchers %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ParseProblemMatchers parses a problem matcher JSON file.
+func ParseProblemMatchers(data []byte) ([]ProblemMatcher, error) {
+	var f problemMatcherFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if len(f.ProblemMatcher) == 0 {
+		return nil, errors.New("no problemMatcher entries")
+	}
+	return f.ProblemMatcher, nil
+}
+
+// Owners returns the owners of the registered matchers, in the order they
+// are tried.
+func (r *MatcherRegistry) Owners() []string {
+	owners := make([]string, len(r.matchers))
+	for i, m := range r.matchers {
+		owners[i] = m.Owner
+	}
+	return owners
+}
+
+// problemScanner runs the matchers of a registry over the lines of one log
+type problemScanner struct {
+	matchers    []*compiledMatcher
+	states      []matchState
+	diagnostics []Diagnostic
+}
+
+// matchState tracks a matcher through the lines of a multi-line problem
+type matchState struct {
+	next    int        // Index of the pattern the next line must match, 0 when idle
+	partial Diagnostic // Properties captured by the patterns matched so far
+}
+
+// newScanner starts scanning a log with the registry's matchers
+func (r *MatcherRegistry) newScanner() *problemScanner {
+	return &problemScanner{
+		matchers: r.matchers,
+		states:   make([]matchState, len(r.matchers)),
+	}
+}
+
+// addLine scans the text of line i of the logs, as returned by logText.
+// Every matcher sees every line, so multi-line problems are tracked, but a
+// line yields at most one problem.
+func (s *problemScanner) addLine(i int, text string) {
+	if text == "" {
+		return
+	}
+
+	found := false
+	for m, matcher := range s.matchers {
+		d, ok := s.match(matcher, &s.states[m], text)
+		if ok && !found {
+			d.LogLine = i
+			s.diagnostics = append(s.diagnostics, d)
+			found = true
+		}
+	}
+}
+
+// match advances a matcher over a line, returning the problem completed on it
+func (s *problemScanner) match(m *compiledMatcher, state *matchState, text string) (Diagnostic, bool) {
+	last := len(m.patterns) - 1
+	if state.next > 0 {
+		if groups := m.patterns[state.next].FindStringSubmatch(text); groups != nil {
+			d := state.partial
+			capture(&d, m.Pattern[state.next], groups)
+			if state.next < last {
+				state.partial = d
+				state.next++
+				return Diagnostic{}, false
+			}
+			if !m.Pattern[last].Loop {
+				*state = matchState{}
+			}
+			return m.finish(d), true
+		}
+		// The problem ended, the line may start the next one
+		*state = matchState{}
+	}
+
+	groups := m.patterns[0].FindStringSubmatch(text)
+	if groups == nil {
+		return Diagnostic{}, false
+	}
+	var d Diagnostic
+	capture(&d, m.Pattern[0], groups)
+	if last > 0 {
+		*state = matchState{next: 1, partial: d}
+		return Diagnostic{}, false
+	}
+	return m.finish(d), true
+}
+
+// finish fills in the defaults of a matched problem
+func (m *compiledMatcher) finish(d Diagnostic) Diagnostic {
+	d.Owner = m.Owner
+	if d.Severity == "" {
+		d.Severity = m.Severity
+	}
+	d.Severity = normalizeSeverity(d.Severity)
+	return d
+}
+
+// capture copies the captured properties of a pattern into d
+func capture(d *Diagnostic, p ProblemPattern, groups []string) {
+	group := func(i int) string {
+		if i <= 0 || i >= len(groups) {
+			return ""
+		}
+		return strings.TrimSpace(groups[i])
+	}
+	if v := group(p.File); v != "" {
+		d.File = v
+	}
+	if v, err := strconv.Atoi(group(p.Line)); err == nil {
+		d.Line = v
+	}
+	if v, err := strconv.Atoi(group(p.Column)); err == nil {
+		d.Column = v
+	}
+	if v := group(p.Severity); v != "" {
+		d.Severity = v
+	}
+	if v := group(p.Code); v != "" {
+		d.Code = v
+	}
+	if v := group(p.Message); v != "" {
+		d.Message = v
+	}
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scanLines runs the registry's matchers over lines
+func scanLines(r *MatcherRegistry, lines ...string) []Diagnostic {
+	s := r.newScanner()
+	for i, line := range lines {
+		s.addLine(i, logText(line))
+	}
+	return s.diagnostics
+}
+
+func TestBuiltinProblemMatchers(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Diagnostic
+	}{
+		{
+			name: "workflow command",
+			line: "2024-01-15T10:00:00.0000000Z ##[error]Process completed with exit code 1.",
+			want: Diagnostic{Owner: "actions", Severity: SeverityError, Message: "Process completed with exit code 1."},
+		},
+		{
+			name: "workflow warning",
+			line: "##[warning]Node.js 16 actions are deprecated.",
+			want: Diagnostic{Owner: "actions", Severity: SeverityWarning, Message: "Node.js 16 actions are deprecated."},
+		},
+		{
+			name: "go compiler",
+			line: "./internal/server/handler.go:42:7: undefined: foo",
+			want: Diagnostic{Owner: "go", Severity: SeverityError, File: "internal/server/handler.go", Line: 42, Column: 7, Message: "undefined: foo"},
+		},
+		{
+			name: "go test assertion",
+			line: "    handler_test.go:18: got 2, want 3",
+			want: Diagnostic{Owner: "go", Severity: SeverityError, File: "handler_test.go", Line: 18, Message: "got 2, want 3"},
+		},
+		{
+			name: "go test failure",
+			line: "--- FAIL: TestHandler (0.01s)",
+			want: Diagnostic{Owner: "go-test", Severity: SeverityError, Message: "FAIL: TestHandler (0.01s)"},
+		},
+		{
+			name: "tsc",
+			line: "src/index.ts(10,5): error TS2322: Type 'string' is not assignable to type 'number'.",
+			want: Diagnostic{Owner: "tsc", Severity: SeverityError, File: "src/index.ts", Line: 10, Column: 5, Code: "TS2322", Message: "Type 'string' is not assignable to type 'number'."},
+		},
+		{
+			name: "tsc pretty",
+			line: "src/index.ts:10:5 - error TS2322: Type 'string' is not assignable to type 'number'.",
+			want: Diagnostic{Owner: "tsc-pretty", Severity: SeverityError, File: "src/index.ts", Line: 10, Column: 5, Code: "TS2322", Message: "Type 'string' is not assignable to type 'number'."},
+		},
+		{
+			name: "pytest summary",
+			line: "FAILED tests/test_api.py::test_login - AssertionError: assert 401 == 200",
+			want: Diagnostic{Owner: "pytest", Severity: SeverityError, File: "tests/test_api.py", Message: "test_login - AssertionError: assert 401 == 200"},
+		},
+		{
+			name: "python traceback location",
+			line: "tests/test_api.py:27: AssertionError",
+			want: Diagnostic{Owner: "python", Severity: SeverityError, File: "tests/test_api.py", Line: 27, Message: "AssertionError"},
+		},
+		{
+			name: "colored output",
+			line: "\x1b[31m--- FAIL: TestColor (0.00s)\x1b[0m",
+			want: Diagnostic{Owner: "go-test", Severity: SeverityError, Message: "FAIL: TestColor (0.00s)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanLines(NewMatcherRegistry(), tt.line)
+			if len(got) != 1 {
+				t.Fatalf("got %d diagnostics, want 1: %+v", len(got), got)
+			}
+			if got[0] != tt.want {
+				t.Errorf("diagnostic = %+v, want %+v", got[0], tt.want)
+			}
+		})
+	}
+
+	t.Run("ordinary lines", func(t *testing.T) {
+		got := scanLines(NewMatcherRegistry(),
+			"Run go test ./...",
+			"ok  	github.com/example/pkg	0.012s",
+			"=== RUN   TestHandler",
+			"--- PASS: TestHandler (0.00s)",
+		)
+		if len(got) != 0 {
+			t.Errorf("got diagnostics for ordinary lines: %+v", got)
+		}
+	})
+}
+
+func TestBuiltinProblemMatchers_ESLintStylish(t *testing.T) {
+	got := scanLines(NewMatcherRegistry(),
+		"",
+		"/home/runner/work/app/src/index.js",
+		"  1:10  error    'foo' is defined but never used  no-unused-vars",
+		"  3:1   warning  Unexpected console statement     no-console",
+		"",
+		"✖ 2 problems (1 error, 1 warning)",
+	)
+
+	want := []Diagnostic{
+		{Owner: "eslint-stylish", Severity: SeverityError, File: "/home/runner/work/app/src/index.js", Line: 1, Column: 10, Code: "no-unused-vars", Message: "'foo' is defined but never used", LogLine: 2},
+		{Owner: "eslint-stylish", Severity: SeverityWarning, File: "/home/runner/work/app/src/index.js", Line: 3, Column: 1, Code: "no-console", Message: "Unexpected console statement", LogLine: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMatcherRegistry_UserMatchers(t *testing.T) {
+	data := `{
+  "problemMatcher": [
+    {
+      "owner": "custom-lint",
+      "severity": "warning",
+      "pattern": [
+        {"regexp": "^LINT (\\S+) line (\\d+): (.*)$", "file": 1, "line": 2, "message": 3}
+      ]
+    }
+  ]
+}`
+	path := filepath.Join(t.TempDir(), "matcher.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewMatcherRegistry()
+	if err := r.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if owners := r.Owners(); owners[0] != "custom-lint" {
+		t.Errorf("Owners() = %v, user matchers should be tried first", owners)
+	}
+
+	got := scanLines(r, "LINT main.go line 3: shadowed variable")
+	want := Diagnostic{Owner: "custom-lint", Severity: SeverityWarning, File: "main.go", Line: 3, Message: "shadowed variable"}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("diagnostics = %+v, want %+v", got, want)
+	}
+
+	// A matcher with the owner of a registered one replaces it
+	count := len(r.Owners())
+	err := r.Register(ProblemMatcher{
+		Owner:   "go-test",
+		Pattern: []ProblemPattern{{Regexp: `^FAIL (\S+)$`, Message: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if len(r.Owners()) != count {
+		t.Errorf("got %d matchers after replacing one, want %d", len(r.Owners()), count)
+	}
+	if got := scanLines(r, "--- FAIL: TestX (0.00s)"); len(got) != 0 {
+		t.Errorf("replaced matcher should not match: %+v", got)
+	}
+	if got := scanLines(r, "FAIL github.com/example/pkg"); len(got) != 1 || got[0].Owner != "go-test" {
+		t.Errorf("replacing matcher should match: %+v", got)
+	}
+}
+
+func TestMatcherRegistry_RegisterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher ProblemMatcher
+		wantErr string
+	}{
+		{"no owner", ProblemMatcher{Pattern: []ProblemPattern{{Regexp: "(.*)", Message: 1}}}, "no owner"},
+		{"no patterns", ProblemMatcher{Owner: "x"}, "no patterns"},
+		{"invalid regexp", ProblemMatcher{Owner: "x", Pattern: []ProblemPattern{{Regexp: "(", Message: 1}}}, "missing closing )"},
+		{"missing group", ProblemMatcher{Owner: "x", Pattern: []ProblemPattern{{Regexp: "(.*)", Message: 2}}}, "has no group 2"},
+		{"loop on only pattern", ProblemMatcher{Owner: "x", Pattern: []ProblemPattern{{Regexp: "(.*)", Message: 1, Loop: true}}}, "can loop"},
+		{"no message", ProblemMatcher{Owner: "x", Pattern: []ProblemPattern{{Regexp: "(.*)", File: 1}}}, "must capture a message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewMatcherRegistry().Register(tt.matcher)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Register() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "matcher.json")
+		if err := os.WriteFile(path, []byte(`{"problemMatcher": []}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err := NewMatcherRegistry().LoadFile(path)
+		if err == nil || !strings.Contains(err.Error(), "invalid problem matchers") {
+			t.Errorf("LoadFile() error = %v", err)
+		}
+	})
+}
+
+func TestParseLogs_Diagnostics(t *testing.T) {
+	logs := `2024-01-15T10:00:00.0000000Z ##[group]Run go test ./...
+2024-01-15T10:00:01.0000000Z --- FAIL: TestHandler (0.01s)
+2024-01-15T10:00:01.0000000Z     handler_test.go:18: got 2, want 3
+2024-01-15T10:00:02.0000000Z ##[endgroup]
+2024-01-15T10:00:02.0000000Z ##[error]Process completed with exit code 1.`
+
+	parsed := ParseLogs(logs)
+	var got []string
+	for _, d := range parsed.Diagnostics {
+		got = append(got, d.Owner+"@"+parsed.AllLines[d.LogLine][29:])
+	}
+	want := []string{
+		"go-test@--- FAIL: TestHandler (0.01s)",
+		"go@    handler_test.go:18: got 2, want 3",
+		"actions@##[error]Process completed with exit code 1.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diagnostics =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
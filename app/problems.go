@@ -0,0 +1,121 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+)
+
+// diagnosticIcon returns an icon for a diagnostic severity
+func diagnosticIcon(severity string) string {
+	switch severity {
+	case SeverityError:
+		return FailureStyle.Render("✗")
+	case SeverityWarning:
+		return CancelledStyle.Render("!")
+	default:
+		return LogNoticeStyle.Render("i")
+	}
+}
+
+// diagnostics returns the problems recognized in the selected job's logs
+func (a *App) diagnostics() []Diagnostic {
+	if a.parsedLogs == nil {
+		return nil
+	}
+	return a.parsedLogs.Diagnostics
+}
+
+// navigateProblem moves the problem selection by delta, staying in bounds
+func (a *App) navigateProblem(delta int) {
+	diags := a.diagnostics()
+	if len(diags) == 0 {
+		return
+	}
+	a.problemIdx = max(0, min(a.problemIdx+delta, len(diags)-1))
+}
+
+// jumpToProblem shows the log line of the selected problem in the Logs tab
+func (a *App) jumpToProblem() {
+	diags := a.diagnostics()
+	if a.problemIdx < 0 || a.problemIdx >= len(diags) {
+		return
+	}
+	a.jumpToLogLine(diags[a.problemIdx].LogLine)
+}
+
+// diagnosticLocation formats where a diagnostic is, as file:line:column
+func diagnosticLocation(d Diagnostic) string {
+	if d.File == "" {
+		return ""
+	}
+	location := d.File
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			location += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return location
+}
+
+// buildProblemsContent builds the content for the Problems tab, listing the
+// problems recognized in the selected job's logs
+func (a *App) buildProblemsContent(maxWidth int) []string {
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return []string{"  Select a job"}
+	}
+
+	var content []string
+	content = append(content, "  Problems: "+truncateString(job.Name, maxWidth-12))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	if a.parsedLogs == nil {
+		if !job.IsCompleted() {
+			return append(content, "  Problems are shown when the job completes")
+		}
+		return append(content, "  Loading logs "+a.spinner.View())
+	}
+	diags := a.diagnostics()
+	if len(diags) == 0 {
+		return append(content, "  No problems found")
+	}
+
+	counts := map[string]int{}
+	for _, d := range diags {
+		counts[d.Severity]++
+	}
+	content = append(content, "  "+
+		FailureStyle.Render(strconv.Itoa(counts[SeverityError])+" errors")+"  "+
+		CancelledStyle.Render(strconv.Itoa(counts[SeverityWarning])+" warnings")+"  "+
+		LogNoticeStyle.Render(strconv.Itoa(counts[SeverityNotice])+" notices"))
+
+	if a.problemsFocused {
+		content = append(content, "  (↑/↓ select, Enter jump to log line, Esc back)")
+	} else {
+		content = append(content, "  (Enter to select problems)")
+	}
+	content = append(content, "")
+
+	for i, d := range diags {
+		text := d.Message
+		if location := diagnosticLocation(d); location != "" {
+			text = location + ": " + text
+		}
+		if d.Code != "" {
+			text += " (" + d.Code + ")"
+		}
+		text = diagnosticIcon(d.Severity) + " " + truncateString(text, maxWidth-8)
+
+		switch {
+		case i == a.problemIdx && a.problemsFocused:
+			content = append(content, "  "+CursorStyle.Render(">")+" "+SelectedItemFocused.Render(text))
+		case i == a.problemIdx:
+			content = append(content, "  "+SelectedItemUnfocused.Render("> "+text))
+		default:
+			content = append(content, "    "+NormalItem.Render(text))
+		}
+	}
+
+	return content
+}
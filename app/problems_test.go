@@ -0,0 +1,73 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// problemJobLogs is the log of a job whose tests fail
+const problemJobLogs = `2024-01-15T10:00:00.0000000Z ##[group]Run actions/checkout@v4
+2024-01-15T10:00:00.1000000Z ##[endgroup]
+2024-01-15T10:00:01.0000000Z ##[group]Run go test ./...
+2024-01-15T10:00:01.1000000Z ##[endgroup]
+2024-01-15T10:00:01.2000000Z === RUN   TestHandler
+2024-01-15T10:00:01.3000000Z --- FAIL: TestHandler (0.01s)
+2024-01-15T10:00:01.4000000Z     handler_test.go:18: got 2, want 3
+2024-01-15T10:00:01.5000000Z FAIL
+2024-01-15T10:00:01.6000000Z ##[error]Process completed with exit code 1.`
+
+func TestApp_ProblemsTab(t *testing.T) {
+	app := New()
+	app.focusedPane = JobsPane
+	app.jobs.SetItems([]github.Job{{ID: 10, Name: "test", Status: "completed", Conclusion: "failure"}})
+	app.Update(LogsLoadedMsg{JobID: 10, Logs: problemJobLogs})
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'7'}})
+	if app.detailTab != ProblemsTab {
+		t.Fatalf("detailTab = %v, want ProblemsTab", app.detailTab)
+	}
+
+	diags := app.parsedLogs.Diagnostics
+	if len(diags) != 3 {
+		t.Fatalf("got %d problems, want 3: %+v", len(diags), diags)
+	}
+	content := strings.Join(app.buildProblemsContent(80), "\n")
+	for _, want := range []string{"Problems: test", "3 errors", "FAIL: TestHandler", "handler_test.go:18: got 2, want 3"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Enter focuses the list, then up/down move the selection
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.problemsFocused {
+		t.Fatal("Enter should focus the problems")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.problemIdx != 1 || app.jobs.SelectedIndex() != 0 {
+		t.Errorf("down should select the next problem, got index %d and job %d", app.problemIdx, app.jobs.SelectedIndex())
+	}
+
+	// Enter again jumps to the problem's line in the logs
+	app.logView.SetSize(80, 1)
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.detailTab != LogsTab || app.stepListFocused || app.problemsFocused {
+		t.Fatalf("jumping should focus the log content, got tab %v", app.detailTab)
+	}
+	if row := app.stepListRows()[app.selectedStepIdx]; row.name != "Run go test ./..." {
+		t.Errorf("selected step = %q, want the step holding the problem", row.name)
+	}
+	if rows := app.logView.Rows(); len(rows) != 1 || !strings.Contains(rows[0], "handler_test.go:18") {
+		t.Errorf("log view should start at the problem's line, got %q", rows)
+	}
+
+	// Changing jobs resets the selection
+	app.problemIdx, app.problemsFocused = 1, true
+	app.onJobSelectionChange()
+	if app.problemIdx != 0 || app.problemsFocused {
+		t.Error("problem selection should reset on job change")
+	}
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// Polling constants. The poll interval grows as the API budget shrinks, so
+// background refreshes never use up the budget needed for interactive use.
+const (
+	// PollIntervalFast is the poll interval while at least half the budget is left
+	PollIntervalFast = 5 * time.Second
+	// PollIntervalMedium is the poll interval while at least a quarter is left
+	PollIntervalMedium = 15 * time.Second
+	// PollIntervalSlow is the poll interval while at least a tenth is left
+	PollIntervalSlow = 30 * time.Second
+	// PollIntervalMin is the poll interval once the budget is nearly exhausted
+	PollIntervalMin = time.Minute
+	// RateGaugeWidth is the number of cells in the status bar budget gauge
+	RateGaugeWidth = 5
+)
+
+// rateLimit returns the client's last known rate limit state
+func (a *App) rateLimit() github.RateLimit {
+	if a.client == nil {
+		return github.RateLimit{}
+	}
+	return a.client.RateLimit()
+}
+
+// pollInterval returns how long to wait before the next background refresh,
+// given the remaining API budget. While a secondary rate limit is in effect,
+// or once the budget is nearly exhausted, polling waits for it to lift.
+func pollInterval(rl github.RateLimit, now time.Time) time.Duration {
+	if rl.RetryAt.After(now) {
+		return max(rl.RetryAt.Sub(now), PollIntervalFast)
+	}
+	switch f := rl.Fraction(); {
+	case f >= 0.5:
+		return PollIntervalFast
+	case f >= 0.25:
+		return PollIntervalMedium
+	case f >= 0.1:
+		return PollIntervalSlow
+	}
+	if rl.Remaining == 0 && rl.Reset.After(now) {
+		return max(rl.Reset.Sub(now), PollIntervalMin)
+	}
+	return PollIntervalMin
+}
+
+// pollCmd schedules the next background refresh if auto refresh is on
+func (a *App) pollCmd(now time.Time) tea.Cmd {
+	if !a.autoRefresh {
+		return nil
+	}
+	return tick(pollInterval(a.rateLimit(), now))
+}
+
+// renderRateGauge renders the API budget as a gauge with the remaining
+// requests and a countdown to the reset, e.g. "API ■■■□□ 3012/5000 ↻41m07s".
+// It returns an empty string if the limit is unknown.
+func renderRateGauge(rl github.RateLimit, now time.Time) string {
+	if rl.Limit <= 0 {
+		return ""
+	}
+	f := rl.Fraction()
+	filled := int(f*RateGaugeWidth + 0.5)
+	if rl.Remaining > 0 {
+		filled = max(filled, 1)
+	}
+	style := SuccessStyle
+	switch {
+	case f < 0.1:
+		style = FailureStyle
+	case f < 0.25:
+		style = CancelledStyle
+	}
+	gauge := "API " + style.Render(strings.Repeat("■", filled)) + strings.Repeat("□", RateGaugeWidth-filled) +
+		" " + strconv.Itoa(rl.Remaining) + "/" + strconv.Itoa(rl.Limit)
+
+	if rl.RetryAt.After(now) {
+		return gauge + " " + FailureStyle.Render("paused "+formatDuration(rl.RetryAt.Sub(now)))
+	}
+	if rl.Reset.After(now) {
+		gauge += " ↻" + formatDuration(rl.Reset.Sub(now))
+	}
+	return gauge
+}
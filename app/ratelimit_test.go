@@ -0,0 +1,182 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestPollInterval(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name string
+		rl   github.RateLimit
+		want time.Duration
+	}{
+		{"unknown limit", github.RateLimit{}, PollIntervalFast},
+		{"full budget", github.RateLimit{Limit: 5000, Remaining: 5000}, PollIntervalFast},
+		{"half budget", github.RateLimit{Limit: 5000, Remaining: 2500}, PollIntervalFast},
+		{"third of budget", github.RateLimit{Limit: 5000, Remaining: 1600}, PollIntervalMedium},
+		{"tenth of budget", github.RateLimit{Limit: 5000, Remaining: 500}, PollIntervalSlow},
+		{"nearly exhausted", github.RateLimit{Limit: 5000, Remaining: 100}, PollIntervalMin},
+		{
+			name: "exhausted waits for reset",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(10 * time.Minute)},
+			want: 10 * time.Minute,
+		},
+		{
+			name: "exhausted with reset soon",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(10 * time.Second)},
+			want: PollIntervalMin,
+		},
+		{
+			name: "secondary rate limit",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 4000, RetryAt: now.Add(2 * time.Minute)},
+			want: 2 * time.Minute,
+		},
+		{
+			name: "expired secondary rate limit",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 4000, RetryAt: now.Add(-time.Minute)},
+			want: PollIntervalFast,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pollInterval(tt.rl, now); got != tt.want {
+				t.Errorf("pollInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRateGauge(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name string
+		rl   github.RateLimit
+		want string
+	}{
+		{"unknown limit", github.RateLimit{}, ""},
+		{"full budget", github.RateLimit{Limit: 5000, Remaining: 5000}, "API ■■■■■ 5000/5000"},
+		{"half budget", github.RateLimit{Limit: 5000, Remaining: 2400}, "API ■■□□□ 2400/5000"},
+		{"low budget keeps one cell", github.RateLimit{Limit: 5000, Remaining: 10}, "API ■□□□□ 10/5000"},
+		{"exhausted", github.RateLimit{Limit: 5000, Remaining: 0}, "API □□□□□ 0/5000"},
+		{
+			name: "reset countdown",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 3000, Reset: now.Add(41*time.Minute + 7*time.Second)},
+			want: "API ■■■□□ 3000/5000 ↻41m07s",
+		},
+		{
+			name: "secondary rate limit",
+			rl:   github.RateLimit{Limit: 5000, Remaining: 3000, RetryAt: now.Add(30 * time.Second)},
+			want: "API ■■■□□ 3000/5000 paused 30s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Strip(renderRateGauge(tt.rl, now)); got != tt.want {
+				t.Errorf("renderRateGauge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApp_RenderStatusBar_RateGauge(t *testing.T) {
+	state := &mockClientState{rateLimit: 1200}
+	app := New(WithClient(newMockClient(state)))
+	app.width = 300
+	app.height = 40
+
+	if bar := ansi.Strip(app.renderStatusBar()); !strings.Contains(bar, "1200/5000") {
+		t.Errorf("renderStatusBar() = %q, want rate limit gauge", bar)
+	}
+}
+
+func TestApp_TickMsg(t *testing.T) {
+	state := &mockClientState{
+		workflows: []github.Workflow{{ID: 1, Name: "CI"}},
+	}
+	newApp := func() *App {
+		app := New(WithClient(newMockClient(state)), WithAutoRefresh())
+		app.workflows.SetItems(state.workflows)
+		return app
+	}
+
+	t.Run("refreshes and schedules the next poll", func(t *testing.T) {
+		app := newApp()
+		_, cmd := app.Update(TickMsg{Time: time.Now()})
+		if cmd == nil {
+			t.Fatal("Update(TickMsg) returned nil cmd")
+		}
+		// Both the refresh and the next tick are batched, so running the
+		// command returns the batch without waiting for the tick
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok || len(batch) != 2 {
+			t.Errorf("Update(TickMsg) cmd = %T (%d), want a batch of refresh and tick", batch, len(batch))
+		}
+	})
+
+	t.Run("keeps refreshing after an error", func(t *testing.T) {
+		app := newApp()
+		app.Update(RunsLoadedMsg{Err: errAPI})
+		if app.err == nil {
+			t.Fatal("a failed load should set the error")
+		}
+		_, cmd := app.Update(TickMsg{Time: time.Now()})
+		if cmd == nil {
+			t.Fatal("Update(TickMsg) returned nil cmd")
+		}
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok || len(batch) != 2 {
+			t.Fatalf("Update(TickMsg) cmd = %T (%d), want a batch of refresh and tick", batch, len(batch))
+		}
+
+		// The next successful load clears the error
+		app.Update(RunsLoadedMsg{Runs: []github.Run{{ID: 1, Status: "completed"}}})
+		if app.err != nil {
+			t.Errorf("err = %v, want it cleared by a successful load", app.err)
+		}
+	})
+
+	skip := map[string]func(*App){
+		"skips refresh while loading":      func(a *App) { a.loading = true },
+		"skips refresh while rate limited": func(a *App) { a.client = &github.MockClient{RateLimitFunc: pausedRateLimit} },
+	}
+	for name, setup := range skip {
+		t.Run(name, func(t *testing.T) {
+			app := newApp()
+			setup(app)
+			_, cmd := app.Update(TickMsg{Time: time.Now()})
+			if cmd == nil {
+				t.Fatal("Update(TickMsg) returned nil cmd, want the next tick")
+			}
+			// Only the tick is scheduled; it is not run since it would wait
+			// for the poll interval
+			done := make(chan tea.Msg, 1)
+			go func() { done <- cmd() }()
+			select {
+			case msg := <-done:
+				t.Errorf("Update(TickMsg) cmd returned %T immediately, want only a tick", msg)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestApp_PollCmd(t *testing.T) {
+	if cmd := New(WithClient(newMockClient(nil))).pollCmd(time.Now()); cmd != nil {
+		t.Error("pollCmd() should not poll unless auto refresh is on")
+	}
+	if cmd := New(WithClient(newMockClient(nil)), WithAutoRefresh()).pollCmd(time.Now()); cmd == nil {
+		t.Error("pollCmd() = nil, want a tick with auto refresh on")
+	}
+}
+
+// pausedRateLimit reports a secondary rate limit that ends in a minute
+func pausedRateLimit() github.RateLimit {
+	return github.RateLimit{Limit: 5000, Remaining: 4000, RetryAt: time.Now().Add(time.Minute)}
+}
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Rendering helpers - build panels for lazygit-style layout
@@ -55,6 +58,7 @@ func (a *App) buildRunsPanel(width, height int) []string {
 
 	// Build content
 	var content []string
+	now := time.Now()
 	items := a.runs.Items()
 	if len(items) == 0 {
 		content = append(content, "  Select workflow")
@@ -63,8 +67,14 @@ func (a *App) buildRunsPanel(width, height int) []string {
 			selected := i == a.runs.SelectedIndex()
 			hovered := a.mouseX < leftWidth && a.mouseY == panelStartY+i+BorderOffset
 			icon := StatusIcon(run.Status, run.Conclusion)
-			line := icon + " #" + strconv.Itoa(run.RunNumber) + " " + run.Event + " " + run.Branch
-			line = truncateString(line, width-ItemPaddingSmall)
+			// Durations of running runs are computed on every render,
+			// so they tick along with the spinner's frame updates
+			meta := " " + formatRelativeTime(run.CreatedAt, now)
+			if d := run.Duration(); d > 0 {
+				meta += " " + formatDuration(d)
+			}
+			title := truncateString(run.Title(), width-ItemPaddingMedium-len(strconv.Itoa(run.RunNumber))-len(meta))
+			line := icon + " #" + strconv.Itoa(run.RunNumber) + " " + title + meta
 			content = append(content, a.renderListItem(line, selected, focused, hovered))
 		}
 	}
@@ -92,7 +102,11 @@ func (a *App) buildJobsPanel(width, height int) []string {
 			selected := i == a.jobs.SelectedIndex()
 			hovered := a.mouseX < leftWidth && a.mouseY == panelStartY+i+BorderOffset
 			icon := StatusIcon(job.Status, job.Conclusion)
-			line := icon + " " + truncateString(job.Name, width-ItemPaddingMedium)
+			duration := ""
+			if !job.StartedAt.IsZero() {
+				duration = " " + formatDuration(job.Duration())
+			}
+			line := icon + " " + truncateString(job.Name, width-ItemPaddingMedium-len(duration)) + duration
 			content = append(content, a.renderListItem(line, selected, focused, hovered))
 		}
 	}
@@ -104,27 +118,55 @@ func (a *App) buildJobsPanel(width, height int) []string {
 func (a *App) buildDetailPanel(width, height int) []string {
 	borderStyle := getPanelBorderStyle(false) // Detail panel is always unfocused style
 
-	// Build tab header
-	infoTab := " Info "
-	logsTab := " Logs "
-	if a.detailTab == InfoTab {
-		infoTab = FocusedTitle.Render(" Info ")
-	} else {
-		logsTab = FocusedTitle.Render(" Logs ")
+	// Build tab header, falling back to numbers only for inactive tabs
+	// when the full labels don't fit
+	tabHeader := a.buildTabHeader(false)
+	if lipgloss.Width(tabHeader) > width-BorderWidth {
+		tabHeader = a.buildTabHeader(true)
 	}
-	tabHeader := " [1]" + infoTab + " [2]" + logsTab + " "
 
 	// Build content based on selected tab
 	var content []string
-	if a.detailTab == InfoTab {
+	switch a.detailTab {
+	case InfoTab:
 		content = a.buildInfoContent(width - ContentPadding)
-	} else {
+	case TimelineTab:
+		content = a.buildTimelineContent(width - ContentPadding)
+	case AnalyticsTab:
+		content = a.buildAnalyticsContent(width - ContentPadding)
+	case DiffTab:
+		content = a.buildDiffContent(width - ContentPadding)
+	case AnnotationsTab:
+		content = a.buildAnnotationsContent(width - ContentPadding)
+	case ProblemsTab:
+		content = a.buildProblemsContent(width - ContentPadding)
+	case TestsTab:
+		content = a.buildTestsContent(width - ContentPadding)
+	case SummaryTab:
+		content = a.buildSummaryContent(width - ContentPadding)
+	default:
 		content = a.buildLogsContent(width - ContentPadding)
 	}
 
 	return renderPanelFrame(width, height, tabHeader, content, borderStyle)
 }
 
+// buildTabHeader builds the detail panel title listing the tabs. In compact
+// mode only the active tab is labeled.
+func (a *App) buildTabHeader(compact bool) string {
+	var b strings.Builder
+	for i, t := range detailTabs {
+		b.WriteString(" [" + strconv.Itoa(i+1) + "]")
+		if a.detailTab == t.tab {
+			b.WriteString(FocusedTitle.Render(" " + t.name + " "))
+		} else if !compact {
+			b.WriteString(" " + t.name + " ")
+		}
+	}
+	b.WriteString(" ")
+	return b.String()
+}
+
 // buildInfoContent builds the content for the Info tab
 func (a *App) buildInfoContent(maxWidth int) []string {
 	var content []string
@@ -145,16 +187,38 @@ func (a *App) buildInfoContent(maxWidth int) []string {
 		if run, ok := a.runs.Selected(); ok {
 			content = append(content, "  Run Information")
 			content = append(content, "  "+strings.Repeat("─", 30))
-			content = append(content, "  Run:    #"+strconv.Itoa(run.RunNumber))
+			runLabel := "#" + strconv.Itoa(run.RunNumber)
+			if run.RunAttempt > 1 {
+				runLabel += " (attempt " + strconv.Itoa(run.RunAttempt) + ")"
+			}
+			content = append(content, "  Run:    "+runLabel)
+			if run.DisplayTitle != "" {
+				content = append(content, "  Title:  "+truncateString(run.DisplayTitle, maxWidth-10))
+			}
 			content = append(content, "  Status: "+StatusIcon(run.Status, run.Conclusion)+" "+run.Status)
 			if run.Conclusion != "" {
 				content = append(content, "  Result: "+run.Conclusion)
 			}
 			content = append(content, "  Branch: "+run.Branch)
+			if run.HeadSHA != "" {
+				content = append(content, "  Commit: "+shortSHA(run.HeadSHA))
+			}
+			for _, pr := range run.PullRequests {
+				content = append(content, "  PR:     #"+strconv.Itoa(pr.Number)+" "+pr.HeadBranch+" → "+pr.BaseBranch)
+			}
 			content = append(content, "  Event:  "+run.Event)
 			content = append(content, "  Actor:  "+run.Actor)
 			if !run.CreatedAt.IsZero() {
-				content = append(content, "  Created: "+run.CreatedAt.Format("2006-01-02 15:04:05"))
+				content = append(content, "  Created: "+run.CreatedAt.Format("2006-01-02 15:04:05")+" ("+formatRelativeTime(run.CreatedAt, time.Now())+")")
+			}
+			if !run.RunStartedAt.IsZero() {
+				content = append(content, "  Started: "+run.RunStartedAt.Format("2006-01-02 15:04:05"))
+			}
+			if d := run.Duration(); d > 0 {
+				content = append(content, "  Duration: "+formatDuration(d))
+			}
+			if queued := run.QueueDuration(); queued > 0 {
+				content = append(content, "  Queued:   "+formatDuration(queued))
 			}
 			if run.URL != "" {
 				content = append(content, "")
@@ -173,12 +237,22 @@ func (a *App) buildInfoContent(maxWidth int) []string {
 			if job.Conclusion != "" {
 				content = append(content, "  Result: "+job.Conclusion)
 			}
+			if !job.StartedAt.IsZero() {
+				content = append(content, "  Duration: "+formatDuration(job.Duration()))
+			}
+			if queued := job.QueueDuration(); queued > 0 {
+				content = append(content, "  Queued:   "+formatDuration(queued))
+			}
 			if len(job.Steps) > 0 {
 				content = append(content, "")
 				content = append(content, "  Steps:")
 				for _, step := range job.Steps {
 					icon := StatusIcon(step.Status, step.Conclusion)
-					content = append(content, "    "+icon+" "+truncateString(step.Name, maxWidth-10))
+					duration := ""
+					if !step.StartedAt.IsZero() {
+						duration = " " + formatDuration(step.Duration())
+					}
+					content = append(content, "    "+icon+" "+truncateString(step.Name, maxWidth-10-len(duration))+duration)
 				}
 			}
 		} else {
@@ -195,7 +269,17 @@ func (a *App) buildLogsContent(maxWidth int) []string {
 
 	job, jobOk := a.jobs.Selected()
 	if jobOk {
-		content = append(content, "  Logs: "+job.Name)
+		header := "  Logs: " + job.Name
+		if a.parsedLogs != nil && a.logRedactions > 0 {
+			header += QueuedStyle.Render(" · " + strconv.Itoa(a.logRedactions) + " redacted")
+		}
+		if a.logColorMode != LogColorsColorized {
+			header += QueuedStyle.Render(" · " + a.logColorMode.String())
+		}
+		if position := a.logPosition(); position != "" {
+			header += QueuedStyle.Render(" · " + position)
+		}
+		content = append(content, header)
 		content = append(content, "  "+strings.Repeat("─", 30))
 	}
 
@@ -203,7 +287,7 @@ func (a *App) buildLogsContent(maxWidth int) []string {
 	if a.parsedLogs != nil && len(a.parsedLogs.Steps) > 0 {
 		// Navigation hint
 		if a.stepListFocused {
-			content = append(content, "  Steps: (↑/↓ select, Enter focus logs)")
+			content = append(content, "  Steps: (↑/↓ select, Space expand, Enter focus logs)")
 		} else {
 			content = append(content, "  Steps: (Esc back to steps)")
 		}
@@ -222,18 +306,29 @@ func (a *App) buildLogsContent(maxWidth int) []string {
 			content = append(content, "    "+NormalItem.Render(allLogsText))
 		}
 
-		// Step list with status icons
-		for i, step := range a.parsedLogs.Steps {
+		// Step list with status icons, and the groups of expanded rows
+		for i, row := range a.stepListRows() {
 			stepSelected := a.selectedStepIdx == i
 
-			// Get step status from job.Steps if available
-			icon := " "
-			if jobOk && i < len(job.Steps) {
-				icon = StatusIcon(job.Steps[i].Status, job.Steps[i].Conclusion)
+			marker := " "
+			if row.hasGroups() {
+				marker = "▸"
+				if a.expandedGroups[row.startLine] {
+					marker = "▾"
+				}
 			}
+			indent := strings.Repeat("  ", row.depth)
 
-			stepName := truncateString(step.Name, maxWidth-10)
-			stepText := icon + " " + stepName
+			// Get step status from job.Steps if available
+			stepText := indent + marker + " "
+			if row.depth == 0 {
+				icon := " "
+				if step, ok := jobStepByNumber(job, row.number); jobOk && ok {
+					icon = StatusIcon(step.Status, step.Conclusion)
+				}
+				stepText += icon + " "
+			}
+			stepText += truncateString(row.name, maxWidth-12-len(indent))
 
 			if stepSelected {
 				if a.stepListFocused {
@@ -251,10 +346,8 @@ func (a *App) buildLogsContent(maxWidth int) []string {
 	}
 
 	// Log content
-	logContent := a.logView.View()
-	logLines := strings.Split(logContent, "\n")
-	for _, l := range logLines {
-		content = append(content, "  "+truncateString(l, maxWidth-4))
+	for _, row := range a.logView.Rows() {
+		content = append(content, "  "+row)
 	}
 
 	if len(content) == 0 {
@@ -267,11 +360,12 @@ func (a *App) buildLogsContent(maxWidth int) []string {
 // padRight pads a string to the specified display width
 func padRight(s string, width int) string {
 	currentWidth := lipgloss.Width(s)
-	if currentWidth >= width {
-		// Truncate if too long
-		return truncateToWidth(s, width)
+	if currentWidth > width {
+		// Truncate if too long, padding again if a wide character didn't fit
+		s = truncateToWidth(s, width)
+		currentWidth = lipgloss.Width(s)
 	}
-	return s + strings.Repeat(" ", width-currentWidth)
+	return s + strings.Repeat(" ", max(width-currentWidth, 0))
 }
 
 // getPanelBorderStyle returns the border style based on focus state
@@ -319,32 +413,30 @@ func renderPanelFrame(width, height int, title string, content []string, borderS
 // buildBorderHeader builds a header line with proper styling for borders
 // This ensures the border color is applied correctly even after styled title text
 func buildBorderHeader(title string, innerWidth int, borderStyle lipgloss.Style) string {
-	titleWidth := lipgloss.Width(title)
-	if titleWidth >= innerWidth {
-		return borderStyle.Render("┏") + title + borderStyle.Render("┓")
+	if lipgloss.Width(title) > innerWidth {
+		title = ansi.Truncate(title, max(innerWidth, 0), "")
 	}
+	titleWidth := lipgloss.Width(title)
 	leftPad := (innerWidth - titleWidth) / 2
 	rightPad := innerWidth - titleWidth - leftPad
 	return borderStyle.Render("┏"+strings.Repeat("━", leftPad)) + title + borderStyle.Render(strings.Repeat("━", rightPad)+"┓")
 }
 
-// truncateToWidth truncates a string to fit within the specified display width
+// truncateToWidth truncates a string to fit within the specified display
+// width, adding "..." if truncated. Escape sequences are kept intact and
+// don't count towards the width.
 func truncateToWidth(s string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return ""
 	}
-	currentWidth := 0
-	for i, r := range s {
-		charWidth := lipgloss.Width(string(r))
-		if currentWidth+charWidth > maxWidth {
-			if maxWidth >= 3 && currentWidth >= 0 {
-				return s[:i] + "..."
-			}
-			return s[:i]
-		}
-		currentWidth += charWidth
+	if ansi.StringWidth(s) <= maxWidth {
+		return s
+	}
+	tail := "..."
+	if maxWidth < len(tail) {
+		tail = ""
 	}
-	return s
+	return ansi.Truncate(s, maxWidth, tail)
 }
 
 // renderStatusBar renders the status bar at the bottom
@@ -356,50 +448,81 @@ func (a *App) renderStatusBar() string {
 	var actionHints string
 	switch a.focusedPane {
 	case WorkflowsPane:
-		actionHints = "[t]rigger [/]filter"
+		actionHints = a.writeHints("[t]rigger") + " [/]filter"
 	case RunsPane:
-		actionHints = "[c]ancel [r]erun [R]erun-failed [y]ank"
+		actionHints = a.writeHints("[c]ancel [r]erun [R]erun-failed") + " [y]ank [F]ailures"
 	case JobsPane:
 		if a.detailTab == LogsTab && a.parsedLogs != nil && len(a.parsedLogs.Steps) > 0 {
 			if a.stepListFocused {
 				actionHints = "[↑/↓]step [Enter]logs [L]fullscreen"
 			} else {
-				actionHints = "[↑/↓]scroll [Esc]steps [L]fullscreen"
+				actionHints = "[↑/↓]scroll [g/G]top/end [:]line [#]numbers [Y]permalink [Esc]steps"
 			}
+		} else if a.detailTab == DiffTab {
+			if a.diffFocused {
+				actionHints = "[↑/↓]scroll [Esc]jobs [v]iew [m]asks"
+			} else {
+				actionHints = "[d]mark [Enter]scroll [v]iew [m]asks"
+			}
+		} else if a.detailTab == AnnotationsTab && a.annotationsFocused {
+			actionHints = "[↑/↓]select [Enter]edit [Esc]jobs"
+		} else if a.detailTab == ProblemsTab && a.problemsFocused {
+			actionHints = "[↑/↓]select [Enter]jump [Esc]jobs"
+		} else if a.detailTab == TestsTab && a.testsFocused {
+			actionHints = "[↑/↓]select [Enter]jump [Esc]jobs"
+		} else if a.detailTab == SummaryTab && a.summaryFocused {
+			actionHints = "[↑/↓]scroll [Esc]jobs"
 		} else {
-			actionHints = "[L]fullscreen [y]ank"
+			actionHints = "[L]fullscreen [d]iff-mark [y]ank"
 		}
 	}
 
 	// Tab hints
-	tabHints := "[1]info [2]logs"
+	tabHints := "[1-9]tabs"
 
 	// Common hints
 	commonHints := "[?]help [q]uit"
 
 	hints := navHints + " " + actionHints + " " + tabHints + " " + commonHints
+	if gauge := renderRateGauge(a.rateLimit(), time.Now()); gauge != "" {
+		hints = gauge + " " + hints
+	}
 
 	if a.filtering {
-		return StatusBar.Width(a.width).Render("Filter: " + a.filterInput.View())
+		return a.renderStatusLine(StatusBar, "Filter: "+a.filterInput.View())
+	}
+	if a.gotoLine {
+		return a.renderStatusLine(StatusBar, "Go to line: "+a.gotoInput.View())
 	}
 
 	if a.flashMsg != "" {
-		return StatusBar.Width(a.width).Render(a.flashMsg)
+		return a.renderStatusLine(StatusBar, a.flashMsg)
 	}
 
 	if a.err != nil {
-		return StatusBar.
-			Foreground(lipgloss.Color("#FF0000")).
-			Width(a.width).
-			Render("Error: " + a.err.Error() + " [Esc]retry")
+		return a.renderStatusLine(StatusBar.Foreground(lipgloss.Color("#FF0000")),
+			"Error: "+a.err.Error()+" [Esc]retry")
 	}
 
-	return StatusBar.Width(a.width).Render(hints)
+	return a.renderStatusLine(StatusBar, hints)
+}
+
+// renderStatusLine renders text in the status bar, truncating it rather than
+// letting it wrap onto a second line
+func (a *App) renderStatusLine(style lipgloss.Style, text string) string {
+	innerWidth := a.width - style.GetHorizontalFrameSize()
+	return style.Width(a.width).Render(truncateToWidth(text, max(innerWidth, 0)))
 }
 
 // renderFullscreenLog renders the fullscreen log view
 func (a *App) renderFullscreenLog() string {
 	title := FocusedTitle.Render("Logs (fullscreen)")
+	if a.gotoLine {
+		// The status bar with the prompt is not shown in full screen
+		title += " Go to line: " + a.gotoInput.View()
+	} else if position := a.logPosition(); position != "" {
+		title += QueuedStyle.Render(" " + position)
+	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		title,
@@ -432,17 +555,59 @@ c           Cancel run
 r           Rerun workflow
 R           Rerun failed jobs only
 y           Copy URL to clipboard
+F           Failure summary of run
 
 Detail View
 ──────────────────────────────────
 1           Info tab
 2           Logs tab
+3           Timeline tab
+4           Analytics tab
+s           Sort analytics jobs
+5           Diff tab
+
+Log Diff
+──────────────────────────────────
+d           Mark job as diff base
+Enter       Focus diff (↓/↑ scroll)
+v           Unified/side-by-side
+m           Cycle duration/hash masks
+
+Annotations (tab 6)
+──────────────────────────────────
+Enter       Select, then open in $EDITOR
+Esc         Back to jobs list
+
+Problems (tab 7)
+──────────────────────────────────
+Enter       Select, then jump to log line
+Esc         Back to jobs list
+
+Tests (tab 8)
+──────────────────────────────────
+Enter       Select failed test, then jump to log
+Esc         Back to jobs list
+
+Summary (tab 9)
+──────────────────────────────────
+Enter       Scroll the job summary
+Esc         Back to jobs list
 
 Step Navigation (Logs tab)
 ──────────────────────────────────
 ↓/↑         Select step
+Space       Expand/collapse groups
 Enter       Focus log content
 Esc         Back to step list
+C           Colorized/raw/stripped
+
+Log Lines (Logs tab)
+──────────────────────────────────
+PgUp/PgDn   Page up/down
+g/G         First/last line
+:           Go to line (L or step:L)
+#           Toggle line numbers
+Y           Copy permalink to line
 
 View
 ──────────────────────────────────
@@ -494,35 +659,35 @@ func truncateString(s string, maxLen int) string {
 	return truncateToWidth(s, maxLen)
 }
 
-// wrapLines wraps long lines to fit within maxWidth (display width)
-func wrapLines(content string, maxWidth int) string {
-	if maxWidth <= 0 {
-		maxWidth = DefaultWrapWidth
+// formatDuration formats a duration compactly (e.g. "45s", "3m12s", "1h05m")
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return strconv.Itoa(int(d.Seconds())) + "s"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
 	}
-	lines := strings.Split(content, "\n")
-	var result []string
-	for _, line := range lines {
-		if lipgloss.Width(line) <= maxWidth {
-			result = append(result, line)
-		} else {
-			// Split long lines by display width
-			currentLine := ""
-			currentWidth := 0
-			for _, r := range line {
-				charWidth := lipgloss.Width(string(r))
-				if currentWidth+charWidth > maxWidth {
-					result = append(result, currentLine)
-					currentLine = string(r)
-					currentWidth = charWidth
-				} else {
-					currentLine += string(r)
-					currentWidth += charWidth
-				}
-			}
-			if currentLine != "" {
-				result = append(result, currentLine)
-			}
-		}
+}
+
+// formatRelativeTime formats t relative to now (e.g. "now", "4m ago", "3d ago")
+func formatRelativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now.Sub(t)
+	switch {
+	case d < 10*time.Second:
+		return "now"
+	case d < time.Minute:
+		return strconv.Itoa(int(d.Seconds())) + "s ago"
+	case d < time.Hour:
+		return strconv.Itoa(int(d.Minutes())) + "m ago"
+	case d < 24*time.Hour:
+		return strconv.Itoa(int(d.Hours())) + "h ago"
+	default:
+		return strconv.Itoa(int(d.Hours()/24)) + "d ago"
 	}
-	return strings.Join(result, "\n")
 }
@@ -2,7 +2,12 @@ package app
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nnnkkk7/lazyactions/github"
 )
 
 func TestApp_RenderPanes(t *testing.T) {
@@ -58,3 +63,31 @@ func TestApp_RenderStatusBar_States(t *testing.T) {
 		t.Error("renderStatusBar with error returned empty string")
 	}
 }
+
+func TestApp_View_FitsTerminal(t *testing.T) {
+	for _, width := range []int{60, 80, 100, 120, 160} {
+		for _, dt := range detailTabs {
+			t.Run(fmt.Sprintf("%d/%s", width, dt.name), func(t *testing.T) {
+				app := New()
+				app.width = width
+				app.height = 40
+				app.focusedPane = JobsPane
+				app.detailTab = dt.tab
+				app.workflows.SetItems([]github.Workflow{{ID: 1, Name: "CI", Path: ".github/workflows/ci.yml"}})
+				app.runs.SetItems([]github.Run{{ID: 2, Name: "CI", Branch: "main", Status: "completed", Conclusion: "failure"}})
+				app.jobs.SetItems([]github.Job{{ID: 3, Name: "build", Status: "completed", Conclusion: "failure"}})
+				app.err = errors.New(strings.Repeat("something went wrong ", 20))
+
+				lines := strings.Split(app.View(), "\n")
+				if len(lines) > app.height {
+					t.Errorf("View() has %d lines, want at most %d", len(lines), app.height)
+				}
+				for i, line := range lines {
+					if w := ansi.StringWidth(line); w > app.width {
+						t.Errorf("line %d is %d columns wide, want at most %d", i, w, app.width)
+					}
+				}
+			})
+		}
+	}
+}
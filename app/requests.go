@@ -0,0 +1,102 @@
+package app
+
+import "context"
+
+// requestKind identifies a slot for an in-flight request. At most one request
+// of each kind is in flight; starting a new one cancels the previous one.
+type requestKind int
+
+const (
+	requestWorkflows requestKind = iota
+	requestRuns
+	requestJobs
+	requestLogs
+	requestAnnotations
+	requestSummary
+	requestAnalytics
+	requestPermissions
+	requestFailureSummary
+)
+
+// requestManager hands out cancellable contexts for fetches, so requests
+// superseded by a selection change stop instead of running to completion,
+// including their retries. It is only used from the Update loop.
+type requestManager struct {
+	ctx      context.Context
+	stop     context.CancelFunc
+	inFlight map[requestKind]context.CancelFunc
+}
+
+// newRequestManager creates a request manager with no requests in flight,
+// whose contexts derive from parent
+func newRequestManager(parent context.Context) *requestManager {
+	ctx, stop := context.WithCancel(parent)
+	return &requestManager{
+		ctx:      ctx,
+		stop:     stop,
+		inFlight: make(map[requestKind]context.CancelFunc),
+	}
+}
+
+// start cancels the in-flight request of a kind, if any, and returns the
+// context for a new one
+func (m *requestManager) start(kind requestKind) context.Context {
+	m.cancel(kind)
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.inFlight[kind] = cancel
+	return ctx
+}
+
+// cancel cancels the in-flight requests of the given kinds
+func (m *requestManager) cancel(kinds ...requestKind) {
+	for _, kind := range kinds {
+		if cancel, ok := m.inFlight[kind]; ok {
+			cancel()
+			delete(m.inFlight, kind)
+		}
+	}
+}
+
+// cancelAll cancels every in-flight request, and any started afterwards
+func (m *requestManager) cancelAll() {
+	m.stop()
+	clear(m.inFlight)
+}
+
+// cancelRequests cancels the in-flight requests of the given kinds and
+// clears their loading state, since cancelled requests send no message
+func (a *App) cancelRequests(kinds ...requestKind) {
+	a.requests.cancel(kinds...)
+	for _, kind := range kinds {
+		switch kind {
+		case requestAnnotations:
+			a.annotationsLoading = false
+		case requestSummary:
+			a.summaryLoading = false
+		case requestAnalytics:
+			a.analyticsLoading = false
+		case requestFailureSummary:
+			a.failureSummaryLoading = false
+		}
+	}
+}
+
+// isCurrentRunsResponse reports whether a runs response answers the latest
+// runs request, for the selected workflow
+func (a *App) isCurrentRunsResponse(msg RunsLoadedMsg) bool {
+	if msg.Generation == 0 {
+		return true
+	}
+	wf, ok := a.workflows.Selected()
+	return ok && wf.ID == msg.WorkflowID && msg.Generation == a.runsGeneration
+}
+
+// isCurrentJobsResponse reports whether a jobs response answers the latest
+// jobs request, for the selected run
+func (a *App) isCurrentJobsResponse(msg JobsLoadedMsg) bool {
+	if msg.Generation == 0 {
+		return true
+	}
+	run, ok := a.runs.Selected()
+	return ok && run.ID == msg.RunID && msg.Generation == a.jobsGeneration
+}
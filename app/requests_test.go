@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestRequestManager(t *testing.T) {
+	t.Run("start cancels the previous request of the same kind", func(t *testing.T) {
+		m := newRequestManager(context.Background())
+		first := m.start(requestJobs)
+		logs := m.start(requestLogs)
+		second := m.start(requestJobs)
+
+		if first.Err() == nil {
+			t.Error("previous jobs request was not cancelled")
+		}
+		if second.Err() != nil {
+			t.Error("new jobs request was cancelled")
+		}
+		if logs.Err() != nil {
+			t.Error("logs request was cancelled by a jobs request")
+		}
+	})
+
+	t.Run("cancel cancels only the given kinds", func(t *testing.T) {
+		m := newRequestManager(context.Background())
+		runs := m.start(requestRuns)
+		logs := m.start(requestLogs)
+		annotations := m.start(requestAnnotations)
+
+		m.cancel(requestLogs, requestAnnotations, requestAnalytics)
+		if logs.Err() == nil || annotations.Err() == nil {
+			t.Error("cancel did not cancel the given kinds")
+		}
+		if runs.Err() != nil {
+			t.Error("cancel cancelled a kind it was not given")
+		}
+	})
+
+	t.Run("cancelAll cancels current and later requests", func(t *testing.T) {
+		m := newRequestManager(context.Background())
+		runs := m.start(requestRuns)
+		m.cancelAll()
+		if runs.Err() == nil {
+			t.Error("cancelAll did not cancel in-flight request")
+		}
+		if m.start(requestJobs).Err() == nil {
+			t.Error("request started after cancelAll was not cancelled")
+		}
+	})
+}
+
+func TestFetch_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mock := newMockClient(&mockClientState{})
+	repo := github.Repository{Owner: "owner", Name: "repo"}
+	cmds := map[string]tea.Cmd{
+		"workflows":   fetchWorkflows(ctx, mock, repo),
+		"runs":        fetchRuns(ctx, mock, repo, 1, 1),
+		"jobs":        fetchJobs(ctx, mock, repo, 1, 1),
+		"logs":        fetchLogs(ctx, mock, nil, nil, nil, repo, 1),
+		"annotations": fetchAnnotations(ctx, mock, repo, 1),
+		"analytics":   fetchAnalytics(ctx, mock, repo, 1),
+	}
+	for name, cmd := range cmds {
+		t.Run(name, func(t *testing.T) {
+			if msg := cmd(); msg != nil {
+				t.Errorf("cancelled fetch returned %T, want no message", msg)
+			}
+		})
+	}
+}
+
+func TestApp_SelectionChangeCancelsRequests(t *testing.T) {
+	state := &mockClientState{
+		workflows: []github.Workflow{{ID: 1, Name: "CI"}},
+		runs:      []github.Run{{ID: 10}},
+		jobs:      []github.Job{{ID: 100, Status: "completed"}},
+	}
+	app := New(WithClient(newMockClient(state)))
+	app.workflows.SetItems(state.workflows)
+	app.runs.SetItems(state.runs)
+	app.jobs.SetItems(state.jobs)
+
+	t.Run("job change cancels logs and annotations", func(t *testing.T) {
+		logs := app.requests.start(requestLogs)
+		app.annotationsLoading = true
+		app.requests.start(requestAnnotations)
+		jobs := app.requests.start(requestJobs)
+
+		app.onJobSelectionChange()
+		if logs.Err() == nil {
+			t.Error("logs request for the previous job was not cancelled")
+		}
+		if jobs.Err() != nil {
+			t.Error("jobs request was cancelled by a job change")
+		}
+		if app.annotationsLoading {
+			t.Error("annotationsLoading should be cleared when annotations are cancelled")
+		}
+	})
+
+	t.Run("run change cancels the previous run's requests", func(t *testing.T) {
+		jobs := app.requests.start(requestJobs)
+		logs := app.requests.start(requestLogs)
+
+		app.onRunSelectionChange()
+		if jobs.Err() == nil || logs.Err() == nil {
+			t.Error("requests for the previous run were not cancelled")
+		}
+	})
+
+	t.Run("workflow change cancels the previous workflow's requests", func(t *testing.T) {
+		runs := app.requests.start(requestRuns)
+		analytics := app.requests.start(requestAnalytics)
+		app.analyticsLoading = true
+
+		app.onWorkflowSelectionChange()
+		if runs.Err() == nil || analytics.Err() == nil {
+			t.Error("requests for the previous workflow were not cancelled")
+		}
+		if app.analyticsLoading {
+			t.Error("analyticsLoading should be cleared when analytics are cancelled")
+		}
+	})
+}
+
+func TestApp_QuitCancelsRequests(t *testing.T) {
+	app := New()
+	runs := app.requests.start(requestRuns)
+	logs := app.requests.start(requestLogs)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if runs.Err() == nil || logs.Err() == nil {
+		t.Error("quit did not cancel in-flight requests")
+	}
+}
+
+func TestApp_IsCurrentResponse(t *testing.T) {
+	app := New()
+	app.workflows.SetItems([]github.Workflow{{ID: 1}})
+	app.runs.SetItems([]github.Run{{ID: 10}})
+	app.runsGeneration = 2
+	app.jobsGeneration = 5
+
+	runsTests := []struct {
+		name string
+		msg  RunsLoadedMsg
+		want bool
+	}{
+		{"latest request", RunsLoadedMsg{WorkflowID: 1, Generation: 2}, true},
+		{"superseded request", RunsLoadedMsg{WorkflowID: 1, Generation: 1}, false},
+		{"previous workflow", RunsLoadedMsg{WorkflowID: 2, Generation: 2}, false},
+		{"not a response", RunsLoadedMsg{}, true},
+	}
+	for _, tt := range runsTests {
+		t.Run("runs "+tt.name, func(t *testing.T) {
+			if got := app.isCurrentRunsResponse(tt.msg); got != tt.want {
+				t.Errorf("isCurrentRunsResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	jobsTests := []struct {
+		name string
+		msg  JobsLoadedMsg
+		want bool
+	}{
+		{"latest request", JobsLoadedMsg{RunID: 10, Generation: 5}, true},
+		{"superseded request", JobsLoadedMsg{RunID: 10, Generation: 4}, false},
+		{"previous run", JobsLoadedMsg{RunID: 11, Generation: 5}, false},
+		{"not a response", JobsLoadedMsg{}, true},
+	}
+	for _, tt := range jobsTests {
+		t.Run("jobs "+tt.name, func(t *testing.T) {
+			if got := app.isCurrentJobsResponse(tt.msg); got != tt.want {
+				t.Errorf("isCurrentJobsResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package app
+
+import "github.com/nnnkkk7/lazyactions/github"
+
+// stepListRow is an entry of the step list in the Logs tab: a step, or a
+// group nested in an expanded step or group
+type stepListRow struct {
+	name      string
+	depth     int // 0 for steps
+	number    int // Number of the job step, 0 for groups and unaligned steps
+	startLine int // First line in ParsedLogs.AllLines
+	endLine   int // Last line in ParsedLogs.AllLines
+	groups    []LogGroup
+}
+
+// hasGroups reports whether the row can be expanded
+func (r stepListRow) hasGroups() bool {
+	return len(r.groups) > 0
+}
+
+// stepListRows returns the rows of the step list: every step, followed by
+// its groups if it is expanded, and so on for nested groups
+func (a *App) stepListRows() []stepListRow {
+	if a.parsedLogs == nil {
+		return nil
+	}
+	rows := make([]stepListRow, 0, len(a.parsedLogs.Steps))
+	var addGroups func(groups []LogGroup, depth int)
+	addGroups = func(groups []LogGroup, depth int) {
+		for _, g := range groups {
+			rows = append(rows, stepListRow{
+				name:      g.Name,
+				depth:     depth,
+				startLine: g.StartLine,
+				endLine:   g.EndLine,
+				groups:    g.Groups,
+			})
+			if a.expandedGroups[g.StartLine] {
+				addGroups(g.Groups, depth+1)
+			}
+		}
+	}
+	for _, step := range a.parsedLogs.Steps {
+		rows = append(rows, stepListRow{
+			name:      step.Name,
+			number:    step.Number,
+			startLine: step.StartLine,
+			endLine:   step.EndLine,
+			groups:    step.Groups,
+		})
+		if a.expandedGroups[step.StartLine] {
+			addGroups(step.Groups, 1)
+		}
+	}
+	return rows
+}
+
+// selectedLogLines returns the log lines of the selected step list row, or
+// all lines when "All logs" is selected
+func (a *App) selectedLogLines() []string {
+	if a.parsedLogs == nil {
+		return nil
+	}
+	if a.selectedStepIdx == -1 {
+		return a.parsedLogs.AllLines
+	}
+	rows := a.stepListRows()
+	if a.selectedStepIdx < 0 || a.selectedStepIdx >= len(rows) {
+		return nil
+	}
+	row := rows[a.selectedStepIdx]
+	return a.parsedLogs.AllLines[row.startLine : row.endLine+1]
+}
+
+// selectedLogStart returns the index in ParsedLogs.AllLines of the first of
+// selectedLogLines
+func (a *App) selectedLogStart() int {
+	rows := a.stepListRows()
+	if a.selectedStepIdx < 0 || a.selectedStepIdx >= len(rows) {
+		return 0
+	}
+	return rows[a.selectedStepIdx].startLine
+}
+
+// toggleStepGroup expands or collapses the groups of the selected row. The
+// selection stays on the row, since only rows after it change.
+func (a *App) toggleStepGroup() {
+	rows := a.stepListRows()
+	if a.selectedStepIdx < 0 || a.selectedStepIdx >= len(rows) || !rows[a.selectedStepIdx].hasGroups() {
+		return
+	}
+	start := rows[a.selectedStepIdx].startLine
+	if a.expandedGroups[start] {
+		delete(a.expandedGroups, start)
+	} else {
+		a.expandedGroups[start] = true
+	}
+}
+
+// jumpToLogLine shows a line of ParsedLogs.AllLines in the Logs tab. The
+// innermost step or group in the step list holding the line is selected,
+// so the line is shown with the rest of its step.
+func (a *App) jumpToLogLine(line int) {
+	a.selectedStepIdx = -1
+	start := 0
+	for i, row := range a.stepListRows() {
+		// Nested rows follow the row they are nested in
+		if row.startLine <= line && line <= row.endLine {
+			a.selectedStepIdx = i
+			start = row.startLine
+		}
+	}
+
+	a.detailTab = LogsTab
+	a.stepListFocused = false
+	a.updateLogViewContent()
+	a.logView.ScrollTo(line - start)
+}
+
+// jobStepByNumber returns the step of a job with the given number
+func jobStepByNumber(job github.Job, number int) (github.Step, bool) {
+	if number <= 0 {
+		return github.Step{}, false
+	}
+	for _, s := range job.Steps {
+		if s.Number == number {
+			return s, true
+		}
+	}
+	return github.Step{}, false
+}
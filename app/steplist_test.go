@@ -0,0 +1,101 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// newStepListApp returns an app showing the realistic job logs in the Logs tab
+func newStepListApp(t *testing.T) *App {
+	t.Helper()
+	app := New()
+	app.focusedPane = JobsPane
+	app.detailTab = LogsTab
+	app.jobs.SetItems([]github.Job{{ID: 10, Name: "test", Status: "completed", Conclusion: "failure", Steps: realisticJobSteps()}})
+	app.Update(LogsLoadedMsg{JobID: 10, Logs: realisticJobLogs})
+	return app
+}
+
+func TestApp_StepListExpandCollapse(t *testing.T) {
+	app := newStepListApp(t)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	if rows := app.stepListRows(); len(rows) != 6 {
+		t.Fatalf("got %d rows, want the 6 steps with logs", len(rows))
+	}
+
+	// Expanding a step without groups does nothing
+	app.selectedStepIdx = 3
+	app.handleKeyPress(space)
+	if rows := app.stepListRows(); len(rows) != 6 {
+		t.Errorf("got %d rows after toggling a step without groups", len(rows))
+	}
+
+	// Expand the composite step, then its inner step
+	app.selectedStepIdx = 2
+	app.handleKeyPress(space)
+	rows := app.stepListRows()
+	if len(rows) != 7 || rows[3].name != "Run echo installing" || rows[3].depth != 1 {
+		t.Fatalf("expanded rows = %+v", rows)
+	}
+	app.navigateStepDown()
+	app.handleKeyPress(space)
+	rows = app.stepListRows()
+	if len(rows) != 8 || rows[4].name != "Nested tool output" || rows[4].depth != 2 {
+		t.Fatalf("nested rows = %+v", rows)
+	}
+
+	// The selected group's lines are shown
+	app.navigateStepDown()
+	if lines := app.selectedLogLines(); len(lines) != 3 || !strings.HasSuffix(lines[1], "deep") {
+		t.Errorf("group lines = %q", lines)
+	}
+
+	content := strings.Join(app.buildLogsContent(80), "\n")
+	for _, want := range []string{"▾ " + StatusIcon("", "success") + " Run ./.github/actions/setup", "  ▾ Run echo installing", "Checkout"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Collapsing the composite step hides its groups
+	app.selectedStepIdx = 2
+	app.handleKeyPress(space)
+	if rows := app.stepListRows(); len(rows) != 6 {
+		t.Errorf("got %d rows after collapsing", len(rows))
+	}
+}
+
+func TestApp_StepListIconsFollowJobSteps(t *testing.T) {
+	app := newStepListApp(t)
+
+	content := app.buildLogsContent(80)
+	var testLine string
+	for _, line := range content {
+		if strings.Contains(line, "Run go test") {
+			testLine = line
+			break
+		}
+	}
+	// Step 5 failed; the skipped step 4 has no row and doesn't shift the icons
+	if !strings.Contains(testLine, StatusIcon("", "failure")) {
+		t.Errorf("go test row should show the failure icon, got %q", testLine)
+	}
+}
+
+func TestApp_StepGroupsResetOnJobChange(t *testing.T) {
+	app := newStepListApp(t)
+	app.selectedStepIdx = 0
+	app.toggleStepGroup()
+	if len(app.expandedGroups) != 1 {
+		t.Fatalf("expandedGroups = %v", app.expandedGroups)
+	}
+
+	app.onJobSelectionChange()
+	if len(app.expandedGroups) != 0 {
+		t.Errorf("expanded groups should be reset for a new job, got %v", app.expandedGroups)
+	}
+}
@@ -121,6 +121,23 @@ var (
 	LogSuccessKeyword = lipgloss.NewStyle().Foreground(ColorLightGreen)
 )
 
+// Log line number styles
+var (
+	LogGutterStyle        = lipgloss.NewStyle().Foreground(ColorMediumGray)
+	LogGutterCurrentStyle = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+)
+
+// Markdown styles, for job summaries
+var (
+	MarkdownHeading = lipgloss.NewStyle().Foreground(ColorGreen).Bold(true)
+	MarkdownBold    = lipgloss.NewStyle().Bold(true)
+	MarkdownItalic  = lipgloss.NewStyle().Italic(true)
+	MarkdownStrike  = lipgloss.NewStyle().Strikethrough(true)
+	MarkdownCode    = lipgloss.NewStyle().Foreground(ColorLightOrange)
+	MarkdownLink    = lipgloss.NewStyle().Foreground(ColorCyan).Underline(true)
+	MarkdownRule    = lipgloss.NewStyle().Foreground(ColorMediumGray)
+)
+
 // StatusIcon returns icon for status
 func StatusIcon(status, conclusion string) string {
 	switch {
@@ -139,6 +156,24 @@ func StatusIcon(status, conclusion string) string {
 	}
 }
 
+// StatusStyle returns the color style matching StatusIcon
+func StatusStyle(status, conclusion string) lipgloss.Style {
+	switch {
+	case status == "in_progress":
+		return RunningStyle
+	case status == "queued":
+		return QueuedStyle
+	case conclusion == "success":
+		return SuccessStyle
+	case conclusion == "failure":
+		return FailureStyle
+	case conclusion == "cancelled":
+		return CancelledStyle
+	default:
+		return NormalItem
+	}
+}
+
 // RenderItem renders list item with selection state
 func RenderItem(text string, selected bool) string {
 	if selected {
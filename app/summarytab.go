@@ -0,0 +1,77 @@
+package app
+
+import (
+	"strings"
+)
+
+// summaryViewKey identifies the inputs of the rendered summary, so it is only
+// rendered again when they change
+type summaryViewKey struct {
+	jobID int64
+	width int
+}
+
+// hasJobSummary reports whether the selected job's summary is loaded and has
+// content to show
+func (a *App) hasJobSummary() bool {
+	job, ok := a.jobs.Selected()
+	return ok && job.ID == a.summaryJobID && strings.TrimSpace(a.summary.Markdown()) != ""
+}
+
+// syncSummaryView renders the selected job's summary into summaryView when
+// the Summary tab is shown and the job or width changed. It runs after every
+// Update, so rendering the tab never changes state.
+func (a *App) syncSummaryView() {
+	if a.detailTab != SummaryTab || !a.hasJobSummary() {
+		return
+	}
+	width := a.detailContentWidth()
+	key := summaryViewKey{jobID: a.summaryJobID, width: width}
+	if key == a.summaryKey {
+		return
+	}
+	a.summaryView.SetContent(strings.Join(renderMarkdown(a.summary.Markdown(), width-2), "\n"))
+	a.summaryView.GotoTop()
+	a.summaryKey = key
+}
+
+// buildSummaryContent builds the content for the Summary tab, showing the
+// Markdown summary of the selected job as rendered by syncSummaryView
+func (a *App) buildSummaryContent(maxWidth int) []string {
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return []string{"  Select a job"}
+	}
+
+	var content []string
+	content = append(content, "  Summary: "+truncateString(job.Name, maxWidth-11))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	if a.summaryJobID != job.ID {
+		if a.summaryLoading {
+			return append(content, "  Loading summary "+a.spinner.View())
+		}
+		return append(content, "  No summary loaded")
+	}
+	if !a.hasJobSummary() {
+		if !job.IsCompleted() {
+			return append(content, "  No summary yet")
+		}
+		return append(content, "  This job has no summary")
+	}
+
+	if a.summaryFocused {
+		content = append(content, "  (↑/↓ scroll, Esc back)")
+	} else {
+		content = append(content, "  (Enter to scroll)")
+	}
+	if a.summary.Title != "" {
+		content = append(content, "  "+MarkdownBold.Render(truncateString(a.summary.Title, maxWidth-2)))
+	}
+	content = append(content, "")
+
+	for _, l := range strings.Split(a.summaryView.View(), "\n") {
+		content = append(content, "  "+l)
+	}
+	return content
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestFetchJobSummary(t *testing.T) {
+	mock := newMockClient(&mockClientState{summary: github.JobSummary{Summary: "## Coverage"}})
+
+	msg := fetchJobSummary(context.Background(), mock, github.Repository{}, 42)()
+
+	result, ok := msg.(JobSummaryLoadedMsg)
+	if !ok {
+		t.Fatalf("expected JobSummaryLoadedMsg, got %T", msg)
+	}
+	if result.JobID != 42 || result.Summary.Summary != "## Coverage" || result.Err != nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if calls := mock.GetJobSummaryCalls(); len(calls) != 1 || calls[0].JobID != 42 {
+		t.Errorf("GetJobSummary calls = %+v, want one call for job 42", calls)
+	}
+}
+
+func TestApp_SummaryTab(t *testing.T) {
+	app := New(WithClient(newMockClient(nil)))
+	app.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	app.focusedPane = JobsPane
+	app.jobs.SetItems([]github.Job{
+		{ID: 10, Name: "coverage", Status: "completed", Conclusion: "success"},
+		{ID: 11, Name: "bench", Status: "completed", Conclusion: "success"},
+	})
+
+	cmd := app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+	if app.detailTab != SummaryTab {
+		t.Fatalf("detailTab = %v, want SummaryTab", app.detailTab)
+	}
+	if cmd == nil || !app.summaryLoading {
+		t.Fatal("switching to the summary tab should fetch the summary")
+	}
+
+	// Summaries of another job are ignored
+	app.Update(JobSummaryLoadedMsg{JobID: 99, Summary: github.JobSummary{Summary: "other"}})
+	if app.summaryJobID == 99 {
+		t.Error("the summary of another job should be ignored")
+	}
+	if !app.summaryLoading {
+		t.Error("the summary of another job should not end loading")
+	}
+
+	app.Update(JobSummaryLoadedMsg{JobID: 10, Summary: github.JobSummary{
+		Summary: "## Coverage\n\n| Package | Coverage |\n|---|--:|\n| app | 80.1% |",
+	}})
+	content := strings.Join(app.buildSummaryContent(80), "\n")
+	for _, want := range []string{"Summary: coverage", "Coverage", "│ app     │    80.1% │"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Enter focuses the summary, so up/down scroll it instead of the jobs
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.summaryFocused {
+		t.Fatal("Enter should focus the summary")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.jobs.SelectedIndex() != 0 {
+		t.Error("down should scroll the focused summary, not select the next job")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.summaryFocused {
+		t.Error("Esc should return focus to the jobs list")
+	}
+
+	// Selecting another job fetches its summary
+	cmd = app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.jobs.SelectedIndex() != 1 || !app.summaryLoading {
+		t.Error("selecting another job should fetch its summary")
+	}
+	if !hasMsg(runCmd(cmd), func(msg tea.Msg) bool {
+		loaded, ok := msg.(JobSummaryLoadedMsg)
+		return ok && loaded.JobID == 11
+	}) {
+		t.Error("selecting another job should request the summary of job 11")
+	}
+	app.Update(JobSummaryLoadedMsg{JobID: 11})
+	content = strings.Join(app.buildSummaryContent(80), "\n")
+	if !strings.Contains(content, "This job has no summary") {
+		t.Errorf("content should report no summary, got:\n%s", content)
+	}
+}
+
+func TestApp_SummaryTab_JobsLoaded(t *testing.T) {
+	mock := newMockClient(nil)
+	app := New(WithClient(mock))
+	app.focusedPane = JobsPane
+	app.detailTab = SummaryTab
+	app.jobs.SetItems([]github.Job{{ID: 10, Name: "coverage", Status: "completed", Conclusion: "success"}})
+	app.Update(JobSummaryLoadedMsg{JobID: 10})
+	app.summaryFocused = true
+
+	// The jobs of another run bring a new selected job
+	_, cmd := app.Update(JobsLoadedMsg{Jobs: []github.Job{{ID: 20, Name: "bench", Status: "in_progress"}}})
+	if !app.summaryLoading || app.summaryFocused {
+		t.Errorf("loading = %v, focused = %v; want the summary reloading and unfocused", app.summaryLoading, app.summaryFocused)
+	}
+	runCmd(cmd)
+	if calls := mock.GetJobSummaryCalls(); len(calls) != 1 || calls[0].JobID != 20 {
+		t.Errorf("GetJobSummary calls = %+v, want one call for job 20", calls)
+	}
+}
@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"errors"
+	"io"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nnnkkk7/lazyactions/github"
 )
 
@@ -11,12 +14,16 @@ import (
 var errAPI = errors.New("API error")
 
 type mockClientState struct {
-	workflows []github.Workflow
-	runs      []github.Run
-	jobs      []github.Job
-	logs      string
-	err       error
-	rateLimit int
+	workflows   []github.Workflow
+	runs        []github.Run
+	jobs        []github.Job
+	logs        string
+	annotations []github.Annotation
+	summary     github.JobSummary
+	headSHA     string
+	err         error
+	rateLimit   int
+	permissions github.Permissions
 }
 
 func newMockClient(state *mockClientState) *github.MockClient {
@@ -31,11 +38,34 @@ func newMockClient(state *mockClientState) *github.MockClient {
 		ListRunsFunc: func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
 			return state.runs, state.err
 		},
+		GetRunFunc: func(ctx context.Context, repo github.Repository, runID int64) (*github.Run, error) {
+			for _, run := range state.runs {
+				if run.ID == runID {
+					return &run, state.err
+				}
+			}
+			return nil, state.err
+		},
+		ResolveRefFunc: func(ctx context.Context, repo github.Repository, ref string) (string, error) {
+			return state.headSHA, state.err
+		},
 		ListJobsFunc: func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
 			return state.jobs, state.err
 		},
-		GetJobLogsFunc: func(ctx context.Context, repo github.Repository, jobID int64) (string, error) {
-			return state.logs, state.err
+		ListJobsAllAttemptsFunc: func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
+			return state.jobs, state.err
+		},
+		GetJobLogsFunc: func(ctx context.Context, repo github.Repository, jobID int64) (io.ReadCloser, error) {
+			if state.err != nil {
+				return nil, state.err
+			}
+			return io.NopCloser(strings.NewReader(state.logs)), nil
+		},
+		GetJobAnnotationsFunc: func(ctx context.Context, repo github.Repository, jobID int64) ([]github.Annotation, error) {
+			return state.annotations, state.err
+		},
+		GetJobSummaryFunc: func(ctx context.Context, repo github.Repository, jobID int64) (github.JobSummary, error) {
+			return state.summary, state.err
 		},
 		CancelRunFunc: func(ctx context.Context, repo github.Repository, runID int64) error {
 			return state.err
@@ -55,5 +85,43 @@ func newMockClient(state *mockClientState) *github.MockClient {
 			}
 			return 5000
 		},
+		RateLimitFunc: func() github.RateLimit {
+			remaining := 5000
+			if state.rateLimit > 0 {
+				remaining = state.rateLimit
+			}
+			return github.RateLimit{Limit: github.DefaultRateLimit, Remaining: remaining}
+		},
+		CheckPermissionsFunc: func(ctx context.Context, repo github.Repository) (github.Permissions, error) {
+			return state.permissions, state.err
+		},
+	}
+}
+
+// runCmd runs cmd and any commands it batches, returning their messages.
+// It must only be used with commands that don't wait, such as fetches.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, runCmd(c)...)
+	}
+	return msgs
+}
+
+// hasMsg reports whether any of msgs matches
+func hasMsg(msgs []tea.Msg, match func(tea.Msg) bool) bool {
+	for _, msg := range msgs {
+		if match(msg) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,375 @@
+package app
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Test result statuses
+const (
+	TestPassed  = "pass"
+	TestFailed  = "fail"
+	TestSkipped = "skip"
+)
+
+// TestOutputLines is the number of output lines kept for a failed test
+const TestOutputLines = 10
+
+// TestResult is the result of one test found in the logs
+type TestResult struct {
+	Name     string
+	Package  string // Go package, empty for other runners
+	Status   string // TestPassed, TestFailed or TestSkipped
+	Duration time.Duration
+	Output   []string // First lines the test printed, kept for failed tests
+	LogLine  int      // Line of the logs the test starts on, in ParsedLogs.AllLines
+}
+
+// TestReport summarizes the tests run by a job, as found in its logs. Go
+// test results are read from `go test -v` and `go test -json` output;
+// pytest, Jest, Maven Surefire and gotestsum summaries provide counts.
+type TestReport struct {
+	Tests    []TestResult // In the order the tests started
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration // Total time reported by the test runners
+}
+
+// Total returns the number of tests run
+func (r TestReport) Total() int {
+	return r.Passed + r.Failed + r.Skipped
+}
+
+// Failures returns the failed tests, in the order they started
+func (r TestReport) Failures() []TestResult {
+	var failures []TestResult
+	for _, t := range r.Tests {
+		if t.Status == TestFailed {
+			failures = append(failures, t)
+		}
+	}
+	return failures
+}
+
+var (
+	// goTestEventRegex matches the lines go test -v prints when a test
+	// starts, pauses or continues, or when its output resumes
+	goTestEventRegex = regexp.MustCompile(`^=== (RUN|PAUSE|CONT|NAME)\s+(\S+)`)
+	// goTestResultRegex matches the result line of a test or subtest
+	goTestResultRegex = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([\d.]+)s\)`)
+	// goPackageResultRegex matches the line ending the tests of a package
+	goPackageResultRegex = regexp.MustCompile(`^(ok|FAIL)\s+(\S+)\s+(?:([\d.]+)s|\(cached\))`)
+	// pytestResultRegex matches pytest -v results and the short summary
+	pytestResultRegex = regexp.MustCompile(`^(?:(\S+::\S+) (PASSED|FAILED|SKIPPED|ERROR|XFAIL|XPASS)\b|(FAILED|ERROR) (\S+::\S+)(?: - (.*))?$)`)
+	// pytestSummaryRegex matches the final line of a pytest run
+	pytestSummaryRegex = regexp.MustCompile(`^=+ (.*\d+ (?:passed|failed|skipped|errors?).*) in ([\d.]+)s\b.*=+$`)
+	// jestSummaryRegex matches the test counts of a Jest run
+	jestSummaryRegex = regexp.MustCompile(`^Tests:\s+(.*\d+ total)$`)
+	// surefireSummaryRegex matches the totals of a Maven Surefire run. The
+	// per-class lines, which end with the time elapsed, are not matched.
+	surefireSummaryRegex = regexp.MustCompile(`^(?:\[\w+\] )?Tests run: (\d+), Failures: (\d+), Errors: (\d+), Skipped: (\d+)$`)
+	// gotestsumSummaryRegex matches the final line of a gotestsum run
+	gotestsumSummaryRegex = regexp.MustCompile(`^DONE (\d+ tests?.*) in ([\d.]+)s$`)
+	// testCountRegex matches the counts in runner summaries
+	testCountRegex = regexp.MustCompile(`(\d+) (passed|xpassed|failed|failures?|errors?|skipped|xfailed|todo|tests?|total)\b`)
+)
+
+// goTestEvent is a line of go test -json output
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// testReportParser builds a TestReport one line at a time
+type testReportParser struct {
+	tests    []*TestResult
+	byName   map[string]*TestResult // Tests of the current go test -v package, or by package and name for -json
+	current  *TestResult            // Test the output lines belong to
+	pkgStart int                    // Index of the first test of the current go test -v package
+
+	summary    TestReport // Counts of the runner summaries
+	hasSummary bool
+	duration   time.Duration
+}
+
+func newTestReportParser() *testReportParser {
+	return &testReportParser{byName: map[string]*TestResult{}}
+}
+
+// addLine reads the text of line i of the logs, as returned by logText
+func (p *testReportParser) addLine(i int, text string) {
+	switch {
+	case strings.HasPrefix(text, `{"`):
+		p.addJSONEvent(i, text)
+	case strings.HasPrefix(text, "=== "):
+		p.addGoTestEvent(i, text)
+	case strings.HasPrefix(strings.TrimLeft(text, " "), "--- "):
+		p.addGoTestResult(i, text)
+	case strings.HasPrefix(text, "ok ") || strings.HasPrefix(text, "FAIL"):
+		if !p.addGoPackageResult(text) {
+			p.addOutput(text)
+		}
+	case strings.Contains(text, "::"):
+		if !p.addPytestResult(i, text) {
+			p.addOutput(text)
+		}
+	case strings.HasPrefix(text, "="), strings.HasPrefix(text, "Tests"),
+		strings.HasPrefix(text, "["), strings.HasPrefix(text, "DONE "):
+		if !p.addSummary(text) {
+			p.addOutput(text)
+		}
+	default:
+		p.addOutput(text)
+	}
+}
+
+// test returns the test with the given key, adding it if it is new
+func (p *testReportParser) test(key, name, pkg string, i int) *TestResult {
+	if t, ok := p.byName[key]; ok {
+		return t
+	}
+	t := &TestResult{Name: name, Package: pkg, LogLine: i}
+	p.byName[key] = t
+	p.tests = append(p.tests, t)
+	return t
+}
+
+// addOutput adds a line printed by the test that is running, or by the test
+// whose result was just printed if the line is indented below it
+func (p *testReportParser) addOutput(text string) {
+	t := p.current
+	if t == nil {
+		return
+	}
+	if t.Status != "" && !strings.HasPrefix(text, "    ") {
+		p.current = nil
+		return
+	}
+	if len(t.Output) < TestOutputLines {
+		t.Output = append(t.Output, strings.TrimRight(text, " "))
+	}
+}
+
+func (p *testReportParser) addGoTestEvent(i int, text string) {
+	match := goTestEventRegex.FindStringSubmatch(text)
+	if match == nil {
+		// Short pytest summaries start the same way
+		if !p.addSummary(text) {
+			p.addOutput(text)
+		}
+		return
+	}
+	t := p.test(match[2], match[2], "", i)
+	if match[1] == "PAUSE" {
+		p.current = nil
+	} else {
+		p.current = t
+	}
+}
+
+func (p *testReportParser) addGoTestResult(i int, text string) {
+	match := goTestResultRegex.FindStringSubmatch(text)
+	if match == nil {
+		p.addOutput(text)
+		return
+	}
+	t := p.test(match[2], match[2], "", i)
+	t.Status = goTestStatus(match[1])
+	t.Duration = parseSeconds(match[3])
+	p.current = t
+}
+
+// addGoPackageResult ends the tests of a go test -v package, reporting
+// whether the line is a package result
+func (p *testReportParser) addGoPackageResult(text string) bool {
+	match := goPackageResultRegex.FindStringSubmatch(text)
+	if match == nil {
+		return false
+	}
+	for _, t := range p.tests[p.pkgStart:] {
+		if t.Package == "" {
+			t.Package = match[2]
+		}
+		// Tests cut short by a panic or timeout never print a result
+		if t.Status == "" && match[1] == "FAIL" {
+			t.Status = TestFailed
+		}
+	}
+	p.duration += parseSeconds(match[3])
+	p.byName = map[string]*TestResult{}
+	p.pkgStart = len(p.tests)
+	p.current = nil
+	return true
+}
+
+// addJSONEvent reads a go test -json event
+func (p *testReportParser) addJSONEvent(i int, text string) {
+	var e goTestEvent
+	if json.Unmarshal([]byte(text), &e) != nil || e.Action == "" {
+		return
+	}
+	if e.Test == "" {
+		// The package finished
+		if e.Action == "pass" || e.Action == "fail" {
+			p.duration += time.Duration(e.Elapsed * float64(time.Second))
+		}
+		if e.Action == "fail" {
+			for _, t := range p.tests {
+				if t.Package == e.Package && t.Status == "" {
+					t.Status = TestFailed
+				}
+			}
+		}
+		return
+	}
+
+	t := p.test(e.Package+"\x00"+e.Test, e.Test, e.Package, i)
+	switch e.Action {
+	case "output":
+		out := strings.TrimRight(e.Output, "\n")
+		trimmed := strings.TrimSpace(out)
+		if strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			return
+		}
+		if len(t.Output) < TestOutputLines {
+			t.Output = append(t.Output, strings.TrimRight(out, " "))
+		}
+	case "pass", "fail", "skip":
+		t.Status = e.Action
+		t.Duration = time.Duration(e.Elapsed * float64(time.Second))
+	}
+}
+
+// addPytestResult reads a pytest result, reporting whether the line is one
+func (p *testReportParser) addPytestResult(i int, text string) bool {
+	match := pytestResultRegex.FindStringSubmatch(text)
+	if match == nil {
+		return false
+	}
+	name, status := match[1], match[2]
+	if name == "" {
+		name, status = match[4], match[3]
+	}
+	t := p.test(name, name, "", i)
+	switch status {
+	case "PASSED", "XPASS":
+		t.Status = TestPassed
+	case "SKIPPED", "XFAIL":
+		t.Status = TestSkipped
+	default:
+		t.Status = TestFailed
+	}
+	if match[5] != "" && len(t.Output) < TestOutputLines {
+		t.Output = append(t.Output, match[5])
+	}
+	p.current = nil
+	return true
+}
+
+// addSummary adds the counts of a runner summary, reporting whether the
+// line is one
+func (p *testReportParser) addSummary(text string) bool {
+	var counts string
+	var elapsed string
+	if match := pytestSummaryRegex.FindStringSubmatch(text); match != nil {
+		counts, elapsed = match[1], match[2]
+	} else if match := jestSummaryRegex.FindStringSubmatch(text); match != nil {
+		counts = match[1]
+	} else if match := gotestsumSummaryRegex.FindStringSubmatch(text); match != nil {
+		counts, elapsed = match[1], match[2]
+	} else if match := surefireSummaryRegex.FindStringSubmatch(text); match != nil {
+		run, _ := strconv.Atoi(match[1])
+		failures, _ := strconv.Atoi(match[2])
+		errs, _ := strconv.Atoi(match[3])
+		skipped, _ := strconv.Atoi(match[4])
+		p.summary.Failed += failures + errs
+		p.summary.Skipped += skipped
+		p.summary.Passed += run - failures - errs - skipped
+		p.hasSummary = true
+		return true
+	} else {
+		return false
+	}
+
+	passed, failed, skipped, total := 0, 0, 0, -1
+	for _, m := range testCountRegex.FindAllStringSubmatch(counts, -1) {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "passed", "xpassed":
+			passed += n
+		case "failed", "failure", "failures", "error", "errors":
+			failed += n
+		case "skipped", "xfailed", "todo":
+			skipped += n
+		default:
+			total = n
+		}
+	}
+	// Some runners only print the total and the tests that did not pass
+	if total >= 0 && passed == 0 {
+		passed = max(0, total-failed-skipped)
+	}
+	p.summary.Passed += passed
+	p.summary.Failed += failed
+	p.summary.Skipped += skipped
+	p.duration += parseSeconds(elapsed)
+	p.hasSummary = true
+	return true
+}
+
+// finish returns the report. Counts come from the runner summaries if the
+// logs have any, since those cover tests whose results were not printed,
+// and from the test results otherwise.
+func (p *testReportParser) finish() TestReport {
+	report := TestReport{Duration: p.duration}
+	for _, t := range p.tests {
+		if t.Status == "" {
+			continue
+		}
+		result := *t
+		if result.Status != TestFailed {
+			result.Output = nil
+		}
+		report.Tests = append(report.Tests, result)
+		switch result.Status {
+		case TestPassed:
+			report.Passed++
+		case TestFailed:
+			report.Failed++
+		case TestSkipped:
+			report.Skipped++
+		}
+	}
+	if p.hasSummary {
+		report.Passed, report.Failed, report.Skipped = p.summary.Passed, p.summary.Failed, p.summary.Skipped
+	}
+	return report
+}
+
+// goTestStatus maps a go test -v result to a test status
+func goTestStatus(result string) string {
+	switch result {
+	case "PASS":
+		return TestPassed
+	case "SKIP":
+		return TestSkipped
+	default:
+		return TestFailed
+	}
+}
+
+// parseSeconds parses a duration printed in seconds, such as "0.012"
+func parseSeconds(s string) time.Duration {
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs * float64(time.Second))
+}
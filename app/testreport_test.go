@@ -0,0 +1,200 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseTestReport runs the test report parser over lines
+func parseTestReport(lines ...string) TestReport {
+	p := newTestReportParser()
+	for i, line := range lines {
+		p.addLine(i, logText(line))
+	}
+	return p.finish()
+}
+
+// testNames returns the names and statuses of the tests in a report
+func testNames(r TestReport) string {
+	var names []string
+	for _, t := range r.Tests {
+		names = append(names, t.Name+"="+t.Status)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTestReport_GoTestVerbose(t *testing.T) {
+	r := parseTestReport(
+		"2024-01-15T10:00:00.0000000Z === RUN   TestAdd",
+		"2024-01-15T10:00:00.0000000Z --- PASS: TestAdd (0.00s)",
+		"=== RUN   TestHandler",
+		"=== RUN   TestHandler/empty",
+		"    handler_test.go:18: got 2, want 3",
+		"=== RUN   TestHandler/full",
+		"--- FAIL: TestHandler (0.01s)",
+		"    --- FAIL: TestHandler/empty (0.00s)",
+		"    --- PASS: TestHandler/full (0.00s)",
+		"=== RUN   TestSlow",
+		"    slow_test.go:9: skipping in short mode",
+		"--- SKIP: TestSlow (0.00s)",
+		"FAIL",
+		"FAIL\tgithub.com/example/app/handler\t0.123s",
+		"=== RUN   TestOther",
+		"--- PASS: TestOther (1.50s)",
+		"PASS",
+		"ok  \tgithub.com/example/app/other\t1.600s",
+	)
+
+	if want := "TestAdd=pass,TestHandler=fail,TestHandler/empty=fail,TestHandler/full=pass,TestSlow=skip,TestOther=pass"; testNames(r) != want {
+		t.Errorf("tests = %s, want %s", testNames(r), want)
+	}
+	if r.Passed != 3 || r.Failed != 2 || r.Skipped != 1 {
+		t.Errorf("counts = %d/%d/%d, want 3 passed, 2 failed, 1 skipped", r.Passed, r.Failed, r.Skipped)
+	}
+	if r.Duration != 1723*time.Millisecond {
+		t.Errorf("Duration = %v, want the packages' total", r.Duration)
+	}
+
+	failures := r.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("got %d failures, want 2", len(failures))
+	}
+	empty := failures[1]
+	if empty.Package != "github.com/example/app/handler" || empty.LogLine != 3 || empty.Duration != 0 {
+		t.Errorf("failure = %+v", empty)
+	}
+	if len(empty.Output) != 1 || !strings.Contains(empty.Output[0], "got 2, want 3") {
+		t.Errorf("Output = %q, want the test's log", empty.Output)
+	}
+	if failures[0].Duration != 10*time.Millisecond {
+		t.Errorf("Duration = %v, want 10ms", failures[0].Duration)
+	}
+	for _, test := range r.Tests {
+		if test.Status != TestFailed && test.Output != nil {
+			t.Errorf("output of %s should only be kept for failed tests", test.Name)
+		}
+	}
+}
+
+func TestTestReport_GoTestNonVerbose(t *testing.T) {
+	r := parseTestReport(
+		"--- FAIL: TestParse (0.00s)",
+		"    parse_test.go:12: unexpected token",
+		"    parse_test.go:13: second error",
+		"FAIL",
+		"exit status 1",
+	)
+	failures := r.Failures()
+	if len(failures) != 1 || len(failures[0].Output) != 2 {
+		t.Fatalf("failures = %+v", failures)
+	}
+	if failures[0].Output[1] != "    parse_test.go:13: second error" {
+		t.Errorf("Output = %q", failures[0].Output)
+	}
+}
+
+func TestTestReport_GoTestTimeout(t *testing.T) {
+	r := parseTestReport(
+		"=== RUN   TestHang",
+		"panic: test timed out after 10m0s",
+		"FAIL\tgithub.com/example/app\t600.010s",
+	)
+	if testNames(r) != "TestHang=fail" {
+		t.Errorf("tests = %s, a test without a result in a failed package should fail", testNames(r))
+	}
+}
+
+func TestTestReport_GoTestJSON(t *testing.T) {
+	r := parseTestReport(
+		`{"Action":"run","Package":"example.com/pkg","Test":"TestA"}`,
+		`{"Action":"output","Package":"example.com/pkg","Test":"TestA","Output":"=== RUN   TestA\n"}`,
+		`{"Action":"output","Package":"example.com/pkg","Test":"TestA","Output":"    a_test.go:5: boom\n"}`,
+		`{"Action":"output","Package":"example.com/pkg","Test":"TestA","Output":"--- FAIL: TestA (0.02s)\n"}`,
+		`{"Action":"fail","Package":"example.com/pkg","Test":"TestA","Elapsed":0.02}`,
+		`{"Action":"run","Package":"example.com/pkg","Test":"TestB"}`,
+		`{"Action":"pass","Package":"example.com/pkg","Test":"TestB","Elapsed":0}`,
+		`{"Action":"fail","Package":"example.com/pkg","Elapsed":0.5}`,
+	)
+	if testNames(r) != "TestA=fail,TestB=pass" {
+		t.Errorf("tests = %s", testNames(r))
+	}
+	a := r.Tests[0]
+	if a.Package != "example.com/pkg" || a.Duration != 20*time.Millisecond || len(a.Output) != 1 || a.Output[0] != "    a_test.go:5: boom" {
+		t.Errorf("TestA = %+v", a)
+	}
+	if r.Duration != 500*time.Millisecond {
+		t.Errorf("Duration = %v, want 500ms", r.Duration)
+	}
+}
+
+func TestTestReport_RunnerSummaries(t *testing.T) {
+	tests := []struct {
+		name                    string
+		lines                   []string
+		passed, failed, skipped int
+		duration                time.Duration
+		failures                string
+	}{
+		{
+			name: "pytest",
+			lines: []string{
+				"tests/test_api.py::test_health PASSED                    [ 33%]",
+				"tests/test_api.py::test_login FAILED                     [ 66%]",
+				"=========================== short test summary info ============================",
+				"FAILED tests/test_api.py::test_login - AssertionError: assert 401 == 200",
+				"============== 1 failed, 10 passed, 2 skipped, 1 warning in 1.25s ==============",
+			},
+			passed: 10, failed: 1, skipped: 2, duration: 1250 * time.Millisecond,
+			failures: "tests/test_api.py::test_login",
+		},
+		{
+			name:   "short pytest summary",
+			lines:  []string{"=== 1 failed, 1 error in 0.50s ==="},
+			failed: 2, duration: 500 * time.Millisecond,
+		},
+		{
+			name:   "jest",
+			lines:  []string{"Tests:       1 failed, 1 skipped, 5 passed, 7 total", "Time:        2.3 s"},
+			passed: 5, failed: 1, skipped: 1,
+		},
+		{
+			name: "maven surefire",
+			lines: []string{
+				"[INFO] Tests run: 4, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.05 s - in com.example.AppTest",
+				"[ERROR] Tests run: 9, Failures: 1, Errors: 1, Skipped: 2",
+			},
+			passed: 5, failed: 2, skipped: 2,
+		},
+		{
+			name:   "gotestsum",
+			lines:  []string{"DONE 10 tests, 1 skipped, 2 failures in 3.20s"},
+			passed: 7, failed: 2, skipped: 1, duration: 3200 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseTestReport(tt.lines...)
+			if r.Passed != tt.passed || r.Failed != tt.failed || r.Skipped != tt.skipped {
+				t.Errorf("counts = %d/%d/%d, want %d/%d/%d", r.Passed, r.Failed, r.Skipped, tt.passed, tt.failed, tt.skipped)
+			}
+			if r.Duration != tt.duration {
+				t.Errorf("Duration = %v, want %v", r.Duration, tt.duration)
+			}
+			var failures []string
+			for _, f := range r.Failures() {
+				failures = append(failures, f.Name)
+			}
+			if strings.Join(failures, ",") != tt.failures {
+				t.Errorf("failures = %v, want %q", failures, tt.failures)
+			}
+		})
+	}
+}
+
+func TestTestReport_NoTests(t *testing.T) {
+	r := ParseLogs(realisticJobLogs).Tests
+	if r.Total() != 0 || len(r.Tests) != 0 {
+		t.Errorf("report = %+v, want no tests", r)
+	}
+}
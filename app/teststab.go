@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TestExcerptLines is the number of output lines shown under a failed test
+const TestExcerptLines = 3
+
+// testFailures returns the failed tests in the selected job's logs
+func (a *App) testFailures() []TestResult {
+	if a.parsedLogs == nil {
+		return nil
+	}
+	return a.parsedLogs.Tests.Failures()
+}
+
+// navigateTest moves the failed test selection by delta, staying in bounds
+func (a *App) navigateTest(delta int) {
+	failures := a.testFailures()
+	if len(failures) == 0 {
+		return
+	}
+	a.testIdx = max(0, min(a.testIdx+delta, len(failures)-1))
+}
+
+// jumpToTest shows where the selected failed test starts in the Logs tab
+func (a *App) jumpToTest() {
+	failures := a.testFailures()
+	if a.testIdx < 0 || a.testIdx >= len(failures) {
+		return
+	}
+	a.jumpToLogLine(failures[a.testIdx].LogLine)
+}
+
+// formatTestDuration formats a test duration, keeping the fractions of a
+// second that most tests take
+func formatTestDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return formatDuration(d)
+}
+
+// buildTestsContent builds the content for the Tests tab, summarizing the
+// tests in the selected job's logs and listing the failed ones
+func (a *App) buildTestsContent(maxWidth int) []string {
+	job, ok := a.jobs.Selected()
+	if !ok {
+		return []string{"  Select a job"}
+	}
+
+	var content []string
+	content = append(content, "  Tests: "+truncateString(job.Name, maxWidth-9))
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	if a.parsedLogs == nil {
+		if !job.IsCompleted() {
+			return append(content, "  Tests are shown when the job completes")
+		}
+		return append(content, "  Loading logs "+a.spinner.View())
+	}
+	report := a.parsedLogs.Tests
+	if report.Total() == 0 && len(report.Tests) == 0 {
+		return append(content, "  No test results found in the logs")
+	}
+
+	summary := "  " +
+		SuccessStyle.Render(strconv.Itoa(report.Passed)+" passed") + "  " +
+		FailureStyle.Render(strconv.Itoa(report.Failed)+" failed") + "  " +
+		QueuedStyle.Render(strconv.Itoa(report.Skipped)+" skipped")
+	if report.Duration > 0 {
+		summary += "  · " + formatTestDuration(report.Duration)
+	}
+	content = append(content, summary)
+
+	failures := report.Failures()
+	if len(failures) == 0 {
+		if report.Failed > 0 {
+			return append(content, "", "  Failed tests are not named in the logs")
+		}
+		return append(content, "", "  All tests passed")
+	}
+
+	if a.testsFocused {
+		content = append(content, "  (↑/↓ select, Enter jump to log line, Esc back)")
+	} else {
+		content = append(content, "  (Enter to select failed tests)")
+	}
+	content = append(content, "")
+
+	for i, t := range failures {
+		text := t.Name
+		if t.Package != "" {
+			text += " (" + t.Package + ")"
+		}
+		if t.Duration > 0 {
+			text += " " + formatTestDuration(t.Duration)
+		}
+		text = FailureStyle.Render("✗") + " " + truncateString(text, maxWidth-8)
+
+		switch {
+		case i == a.testIdx && a.testsFocused:
+			content = append(content, "  "+CursorStyle.Render(">")+" "+SelectedItemFocused.Render(text))
+		case i == a.testIdx:
+			content = append(content, "  "+SelectedItemUnfocused.Render("> "+text))
+		default:
+			content = append(content, "    "+NormalItem.Render(text))
+		}
+
+		for _, line := range t.Output[:min(len(t.Output), TestExcerptLines)] {
+			content = append(content, "      "+QueuedStyle.Render(truncateString(strings.TrimSpace(line), maxWidth-8)))
+		}
+	}
+
+	return content
+}
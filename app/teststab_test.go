@@ -0,0 +1,63 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestApp_TestsTab(t *testing.T) {
+	app := New()
+	app.focusedPane = JobsPane
+	app.jobs.SetItems([]github.Job{{ID: 10, Name: "test", Status: "completed", Conclusion: "failure"}})
+	app.Update(LogsLoadedMsg{JobID: 10, Logs: problemJobLogs})
+
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'8'}})
+	if app.detailTab != TestsTab {
+		t.Fatalf("detailTab = %v, want TestsTab", app.detailTab)
+	}
+
+	content := strings.Join(app.buildTestsContent(80), "\n")
+	for _, want := range []string{"Tests: test", "0 passed", "1 failed", "TestHandler 0.01s", "handler_test.go:18: got 2, want 3"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Enter focuses the failed tests, then jumps to the selected one
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.testsFocused {
+		t.Fatal("Enter should focus the failed tests")
+	}
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	if app.testIdx != 0 || app.jobs.SelectedIndex() != 0 {
+		t.Errorf("test selection should stay in bounds, got index %d and job %d", app.testIdx, app.jobs.SelectedIndex())
+	}
+	app.logView.SetSize(80, 1)
+	app.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.detailTab != LogsTab || app.stepListFocused || app.testsFocused {
+		t.Fatalf("jumping should focus the log content, got tab %v", app.detailTab)
+	}
+	if rows := app.logView.Rows(); len(rows) != 1 || !strings.Contains(rows[0], "=== RUN   TestHandler") {
+		t.Errorf("log view should start where the test starts, got %q", rows)
+	}
+
+	// Changing jobs resets the selection
+	app.testsFocused = true
+	app.onJobSelectionChange()
+	if app.testIdx != 0 || app.testsFocused {
+		t.Error("test selection should reset on job change")
+	}
+}
+
+func TestApp_TestsTabWithoutTests(t *testing.T) {
+	app := newStepListApp(t)
+	app.detailTab = TestsTab
+
+	content := strings.Join(app.buildTestsContent(80), "\n")
+	if !strings.Contains(content, "No test results found") {
+		t.Errorf("content should report no tests, got:\n%s", content)
+	}
+}
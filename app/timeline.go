@@ -0,0 +1,194 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// Timeline layout constants
+const (
+	// TimelineLabelWidth is the width of the name column in the timeline
+	TimelineLabelWidth = 20
+	// TimelineDurationWidth is the width reserved for the duration after each bar
+	TimelineDurationWidth = 8
+	// MinTimelineBarWidth is the minimum width of the bar area
+	MinTimelineBarWidth = 10
+)
+
+// timelineSpan is a labelled time interval drawn as a bar on the timeline.
+// The queue segment (QueuedAt to StartedAt) and the run segment
+// (StartedAt to CompletedAt, or now) are drawn differently.
+type timelineSpan struct {
+	Label       string
+	QueuedAt    time.Time // Zero if the span has no queue segment
+	StartedAt   time.Time // Zero if the span has not started
+	CompletedAt time.Time // Zero if the span is still running
+	Status      string
+	Conclusion  string
+}
+
+// end returns when the span ended, or now if it is still running
+func (s timelineSpan) end(now time.Time) time.Time {
+	if !s.CompletedAt.IsZero() {
+		return s.CompletedAt
+	}
+	if s.StartedAt.IsZero() {
+		return time.Time{}
+	}
+	return now
+}
+
+// jobSpans converts jobs to timeline spans
+func jobSpans(jobs []github.Job) []timelineSpan {
+	spans := make([]timelineSpan, 0, len(jobs))
+	for _, job := range jobs {
+		spans = append(spans, timelineSpan{
+			Label:       job.Name,
+			QueuedAt:    job.CreatedAt,
+			StartedAt:   job.StartedAt,
+			CompletedAt: job.CompletedAt,
+			Status:      job.Status,
+			Conclusion:  job.Conclusion,
+		})
+	}
+	return spans
+}
+
+// stepSpans converts the steps of a job to timeline spans
+func stepSpans(job github.Job) []timelineSpan {
+	spans := make([]timelineSpan, 0, len(job.Steps))
+	for _, step := range job.Steps {
+		spans = append(spans, timelineSpan{
+			Label:       step.Name,
+			StartedAt:   step.StartedAt,
+			CompletedAt: step.CompletedAt,
+			Status:      step.Status,
+			Conclusion:  step.Conclusion,
+		})
+	}
+	return spans
+}
+
+// timelineBounds returns the earliest start and latest end of the spans.
+// Both are zero if no span has started or been queued.
+func timelineBounds(spans []timelineSpan, now time.Time) (start, end time.Time) {
+	for _, s := range spans {
+		for _, t := range []time.Time{s.QueuedAt, s.StartedAt} {
+			if !t.IsZero() && (start.IsZero() || t.Before(start)) {
+				start = t
+			}
+		}
+		if e := s.end(now); !e.IsZero() && e.After(end) {
+			end = e
+		}
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+// renderTimelineBar draws a span as a bar of the given width on the axis
+// from axisStart to axisEnd. Queue time is drawn with "░", run time with "█".
+func renderTimelineBar(s timelineSpan, axisStart, axisEnd, now time.Time, width int) string {
+	total := axisEnd.Sub(axisStart)
+	col := func(t time.Time) int {
+		if total <= 0 {
+			return 0
+		}
+		c := int(float64(t.Sub(axisStart)) / float64(total) * float64(width))
+		return max(0, min(c, width))
+	}
+
+	if s.StartedAt.IsZero() {
+		if s.QueuedAt.IsZero() {
+			return strings.Repeat(" ", width)
+		}
+		// Still queued: draw the wait up to now
+		q := col(s.QueuedAt)
+		e := max(col(now), q)
+		return strings.Repeat(" ", q) + QueuedStyle.Render(strings.Repeat("░", e-q)) + strings.Repeat(" ", width-e)
+	}
+
+	startCol := col(s.StartedAt)
+	queueCol := startCol
+	if !s.QueuedAt.IsZero() {
+		queueCol = col(s.QueuedAt)
+	}
+	endCol := col(s.end(now))
+	// Always draw at least one cell so very short spans stay visible
+	if endCol <= startCol {
+		endCol = min(startCol+1, width)
+		startCol = endCol - 1
+		queueCol = min(queueCol, startCol)
+	}
+
+	return strings.Repeat(" ", queueCol) +
+		QueuedStyle.Render(strings.Repeat("░", startCol-queueCol)) +
+		StatusStyle(s.Status, s.Conclusion).Render(strings.Repeat("█", endCol-startCol)) +
+		strings.Repeat(" ", width-endCol)
+}
+
+// renderTimelineRow renders a labelled bar followed by the span's run time
+func renderTimelineRow(s timelineSpan, axisStart, axisEnd, now time.Time, barWidth int) string {
+	label := padRight(truncateString(s.Label, TimelineLabelWidth-2), TimelineLabelWidth)
+	duration := ""
+	if !s.StartedAt.IsZero() {
+		duration = " " + formatDuration(s.end(now).Sub(s.StartedAt))
+	}
+	return "  " + label + renderTimelineBar(s, axisStart, axisEnd, now, barWidth) + duration
+}
+
+// buildTimelineContent builds the content for the Timeline tab.
+// It draws the jobs of the selected run, and the steps of the selected job,
+// as bars on a shared time axis.
+func (a *App) buildTimelineContent(maxWidth int) []string {
+	jobs := a.jobs.Items()
+	if len(jobs) == 0 {
+		return []string{"  Select a run"}
+	}
+
+	now := time.Now()
+	jobRows := jobSpans(jobs)
+	var stepRows []timelineSpan
+	job, jobOk := a.jobs.Selected()
+	if jobOk {
+		stepRows = stepSpans(job)
+	}
+
+	axisStart, axisEnd := timelineBounds(append(append([]timelineSpan{}, jobRows...), stepRows...), now)
+	if axisStart.IsZero() {
+		return []string{"  No timing data yet"}
+	}
+
+	barWidth := max(maxWidth-2-TimelineLabelWidth-TimelineDurationWidth, MinTimelineBarWidth)
+
+	var content []string
+	content = append(content, "  Timeline")
+	content = append(content, "  "+strings.Repeat("─", 30))
+
+	// Axis labels: elapsed time at both ends of the bar area
+	endLabel := formatDuration(axisEnd.Sub(axisStart))
+	axis := "0s" + strings.Repeat(" ", max(barWidth-2-len(endLabel), 1)) + endLabel
+	content = append(content, "  "+strings.Repeat(" ", TimelineLabelWidth)+QueuedStyle.Render(axis))
+
+	content = append(content, "  Jobs:")
+	for _, s := range jobRows {
+		content = append(content, renderTimelineRow(s, axisStart, axisEnd, now, barWidth))
+	}
+
+	if jobOk && len(stepRows) > 0 {
+		content = append(content, "")
+		content = append(content, "  Steps: "+truncateString(job.Name, maxWidth-9))
+		for _, s := range stepRows {
+			content = append(content, renderTimelineRow(s, axisStart, axisEnd, now, barWidth))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, "  "+QueuedStyle.Render("░ queued")+"  "+NormalItem.Render("█ running"))
+
+	return content
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{1400 * time.Millisecond, "1s"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 5*time.Second, "3m05s"},
+		{time.Hour + 5*time.Minute + 30*time.Second, "1h05m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatDuration(tt.d); got != tt.want {
+				t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Time{}, ""},
+		{now.Add(-3 * time.Second), "now"},
+		{now.Add(-45 * time.Second), "45s ago"},
+		{now.Add(-4 * time.Minute), "4m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatRelativeTime(tt.t, now); got != tt.want {
+				t.Errorf("formatRelativeTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimelineBounds(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	spans := []timelineSpan{
+		{QueuedAt: base.Add(10 * time.Second), StartedAt: base.Add(20 * time.Second), CompletedAt: base.Add(time.Minute)},
+		{QueuedAt: base, StartedAt: base.Add(5 * time.Second), CompletedAt: base.Add(2 * time.Minute)},
+		{Label: "not started"},
+	}
+
+	start, end := timelineBounds(spans, base.Add(time.Hour))
+
+	if !start.Equal(base) {
+		t.Errorf("start = %v, want %v", start, base)
+	}
+	if !end.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("end = %v, want %v", end, base.Add(2*time.Minute))
+	}
+}
+
+func TestRenderTimelineBar(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	axisEnd := base.Add(100 * time.Second)
+
+	t.Run("queue and run segments", func(t *testing.T) {
+		s := timelineSpan{
+			QueuedAt:    base,
+			StartedAt:   base.Add(20 * time.Second),
+			CompletedAt: base.Add(50 * time.Second),
+			Status:      "completed",
+			Conclusion:  "success",
+		}
+		bar := renderTimelineBar(s, base, axisEnd, axisEnd, 10)
+
+		if w := lipgloss.Width(bar); w != 10 {
+			t.Errorf("bar width = %d, want 10", w)
+		}
+		if n := strings.Count(bar, "░"); n != 2 {
+			t.Errorf("queue cells = %d, want 2", n)
+		}
+		if n := strings.Count(bar, "█"); n != 3 {
+			t.Errorf("run cells = %d, want 3", n)
+		}
+	})
+
+	t.Run("short span is still visible", func(t *testing.T) {
+		s := timelineSpan{StartedAt: axisEnd, CompletedAt: axisEnd, Status: "completed", Conclusion: "success"}
+		bar := renderTimelineBar(s, base, axisEnd, axisEnd, 10)
+
+		if n := strings.Count(bar, "█"); n != 1 {
+			t.Errorf("run cells = %d, want 1", n)
+		}
+		if w := lipgloss.Width(bar); w != 10 {
+			t.Errorf("bar width = %d, want 10", w)
+		}
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		bar := renderTimelineBar(timelineSpan{}, base, axisEnd, axisEnd, 10)
+		if bar != strings.Repeat(" ", 10) {
+			t.Errorf("bar = %q, want blank", bar)
+		}
+	})
+}
+
+func TestApp_BuildTimelineContent(t *testing.T) {
+	base := time.Now().Add(-10 * time.Minute)
+	app := New()
+	app.jobs.SetItems([]github.Job{
+		{
+			Name: "build", Status: "completed", Conclusion: "success",
+			CreatedAt: base, StartedAt: base.Add(30 * time.Second), CompletedAt: base.Add(3 * time.Minute),
+			Steps: []github.Step{
+				{Name: "Checkout", Status: "completed", Conclusion: "success", StartedAt: base.Add(30 * time.Second), CompletedAt: base.Add(time.Minute)},
+			},
+		},
+		{Name: "deploy", Status: "queued", CreatedAt: base.Add(3 * time.Minute)},
+	})
+
+	content := strings.Join(app.buildTimelineContent(80), "\n")
+
+	for _, want := range []string{"build", "deploy", "Checkout", "2m30s", "░ queued"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("timeline should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestApp_BuildTimelineContent_NoJobs(t *testing.T) {
+	app := New()
+	content := app.buildTimelineContent(80)
+	if len(content) != 1 || !strings.Contains(content[0], "Select a run") {
+		t.Errorf("content = %v, want select a run hint", content)
+	}
+}
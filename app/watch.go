@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// Watch exit codes
+const (
+	// WatchExitSuccess is returned when every watched run succeeded
+	WatchExitSuccess = 0
+	// WatchExitFailure is returned when a watched run failed or watching itself failed
+	WatchExitFailure = 1
+	// WatchExitCancelled is returned when a watched run was cancelled
+	WatchExitCancelled = 2
+)
+
+// Watch timing constants
+const (
+	// DefaultWatchInterval is the default polling interval for watch mode
+	DefaultWatchInterval = 5 * time.Second
+	// DefaultWatchWaitTimeout is how long watch mode waits for a run to appear
+	DefaultWatchWaitTimeout = 2 * time.Minute
+	// DefaultWatchGrace is how long watch mode keeps polling a branch or
+	// commit after its runs completed, since workflows triggered by the same
+	// push can start a little later
+	DefaultWatchGrace = 15 * time.Second
+)
+
+// WatchOptions selects the runs followed by Watch.
+// Exactly one of RunID, Branch or Commit should be set.
+type WatchOptions struct {
+	RunID       int64         // Watch a single run
+	Branch      string        // Watch the runs for the commit at the head of a branch
+	Commit      string        // Watch every run for a commit SHA
+	Interval    time.Duration // Polling interval (DefaultWatchInterval if zero)
+	WaitTimeout time.Duration // How long to wait for runs to appear (DefaultWatchWaitTimeout if zero)
+	Grace       time.Duration // How long to wait for more runs of a branch or commit (DefaultWatchGrace if zero)
+}
+
+// target describes the watched runs for user-facing messages
+func (o WatchOptions) target() string {
+	switch {
+	case o.RunID != 0:
+		return "run " + strconv.FormatInt(o.RunID, 10)
+	case o.Branch != "" && o.Commit != "":
+		return "branch " + o.Branch + " (" + shortSHA(o.Commit) + ")"
+	case o.Branch != "":
+		return "branch " + o.Branch
+	default:
+		return "commit " + shortSHA(o.Commit)
+	}
+}
+
+// Watch polls the selected runs until they complete and mirrors their job and
+// step status to out as a compact tree. When out is a terminal the tree is
+// redrawn in place, otherwise a new tree is printed on every change.
+// A branch is resolved to its head commit when watching starts, so runs of
+// later pushes are not mixed in. The runs of a commit are reported once they
+// completed and no new run started during the grace period.
+// It returns the exit code matching the final conclusion of the runs.
+func Watch(ctx context.Context, client github.Client, repo github.Repository, opts WatchOptions, out io.Writer) (int, error) {
+	if opts.RunID == 0 && opts.Branch == "" && opts.Commit == "" {
+		return WatchExitFailure, errors.New("watch requires a run ID, branch or commit")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	if opts.WaitTimeout <= 0 {
+		opts.WaitTimeout = DefaultWatchWaitTimeout
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultWatchGrace
+	}
+	if opts.RunID == 0 && opts.Branch != "" {
+		err := github.RetryWithBackoff(ctx, 3, func() error {
+			var e error
+			opts.Commit, e = client.ResolveRef(ctx, repo, opts.Branch)
+			return e
+		})
+		if err != nil {
+			return WatchExitFailure, fmt.Errorf("failed to resolve branch %s: %w", opts.Branch, err)
+		}
+	}
+
+	renderer := newWatchRenderer(out)
+	started := time.Now()
+	var completedAt time.Time // When the runs were first seen completed
+	completedRuns := 0        // How many runs there were then
+	jobs := make(map[int64][]github.Job)
+	final := make(map[int64]bool) // Runs whose jobs were fetched after they completed
+
+	for {
+		runs, err := fetchWatchedRuns(ctx, client, repo, opts)
+		if err != nil {
+			return WatchExitFailure, err
+		}
+
+		if len(runs) == 0 {
+			if time.Since(started) > opts.WaitTimeout {
+				return WatchExitFailure, fmt.Errorf("no workflow runs found for %s", opts.target())
+			}
+			renderer.Render("Waiting for runs of " + opts.target() + "...")
+		} else {
+			for _, run := range runs {
+				// Jobs of a completed run don't change
+				if final[run.ID] {
+					continue
+				}
+				var runJobs []github.Job
+				err := github.RetryWithBackoff(ctx, 3, func() error {
+					var e error
+					runJobs, e = client.ListJobs(ctx, repo, run.ID)
+					return e
+				})
+				if err != nil {
+					return WatchExitFailure, err
+				}
+				jobs[run.ID] = runJobs
+				final[run.ID] = run.Status == "completed"
+			}
+
+			renderer.Render(renderWatchTree(runs, jobs))
+			switch {
+			case !allRunsCompleted(runs):
+				completedAt = time.Time{}
+			case opts.RunID != 0:
+				return watchExitCode(runs), nil
+			case completedAt.IsZero() || len(runs) != completedRuns:
+				// Give runs triggered by the same push a chance to appear
+				completedAt, completedRuns = time.Now(), len(runs)
+			case time.Since(completedAt) >= opts.Grace:
+				return watchExitCode(runs), nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return WatchExitFailure, ctx.Err()
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// fetchWatchedRuns fetches the runs selected by opts.
+// Retries on transient errors (rate limits, server errors).
+func fetchWatchedRuns(ctx context.Context, client github.Client, repo github.Repository, opts WatchOptions) ([]github.Run, error) {
+	var runs []github.Run
+	err := github.RetryWithBackoff(ctx, 3, func() error {
+		switch {
+		case opts.RunID != 0:
+			run, e := client.GetRun(ctx, repo, opts.RunID)
+			if e != nil {
+				return e
+			}
+			runs = []github.Run{*run}
+		default:
+			all, e := client.ListRuns(ctx, repo, &github.ListRunsOpts{Branch: opts.Branch, HeadSHA: opts.Commit, PerPage: 100})
+			if e != nil {
+				return e
+			}
+			runs = all
+		}
+		return nil
+	})
+	return runs, err
+}
+
+// allRunsCompleted returns true if none of the runs is queued or in progress
+func allRunsCompleted(runs []github.Run) bool {
+	for _, run := range runs {
+		if run.Status != "completed" {
+			return false
+		}
+	}
+	return true
+}
+
+// watchExitCode maps the conclusions of completed runs to an exit code.
+// A failure takes precedence over a cancellation.
+func watchExitCode(runs []github.Run) int {
+	code := WatchExitSuccess
+	for _, run := range runs {
+		switch run.Conclusion {
+		case "success", "skipped", "neutral":
+		case "cancelled":
+			if code == WatchExitSuccess {
+				code = WatchExitCancelled
+			}
+		default:
+			code = WatchExitFailure
+		}
+	}
+	return code
+}
+
+// renderWatchTree renders runs with their jobs, and the steps of jobs that
+// are still running or did not succeed, as an indented status tree
+func renderWatchTree(runs []github.Run, jobs map[int64][]github.Job) string {
+	var lines []string
+	for _, run := range runs {
+		header := StatusIcon(run.Status, run.Conclusion) + " " + run.Name + " #" + strconv.Itoa(run.RunNumber) +
+			" " + run.Event + " " + run.Branch + " (" + watchStatusText(run.Status, run.Conclusion) + ")"
+		lines = append(lines, header)
+
+		runJobs := jobs[run.ID]
+		if len(runJobs) == 0 && run.Status != "completed" {
+			lines = append(lines, "  "+QueuedStyle.Render("waiting for jobs..."))
+		}
+		for _, job := range runJobs {
+			lines = append(lines, "  "+StatusIcon(job.Status, job.Conclusion)+" "+job.Name)
+			if job.IsCompleted() && (job.Conclusion == "success" || job.Conclusion == "skipped") {
+				continue
+			}
+			for _, step := range job.Steps {
+				if step.Status == "queued" && job.IsCompleted() {
+					continue
+				}
+				lines = append(lines, "      "+StatusIcon(step.Status, step.Conclusion)+" "+step.Name)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// watchStatusText returns the conclusion of a completed item, or its status otherwise
+func watchStatusText(status, conclusion string) string {
+	if status == "completed" && conclusion != "" {
+		return conclusion
+	}
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+// shortSHA shortens a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// watchRenderer writes watch frames to an output, skipping unchanged frames
+type watchRenderer struct {
+	out   io.Writer
+	live  bool   // Redraw the previous frame in place
+	last  string // Last rendered frame
+	lines int    // Number of lines in the last rendered frame
+}
+
+// newWatchRenderer creates a renderer that redraws in place when out is a terminal
+func newWatchRenderer(out io.Writer) *watchRenderer {
+	return &watchRenderer{
+		out:  out,
+		live: isTerminal(out),
+	}
+}
+
+// Render writes frame unless it is identical to the previous one
+func (r *watchRenderer) Render(frame string) {
+	if frame == r.last {
+		return
+	}
+	if r.live && r.lines > 0 {
+		// Move the cursor to the start of the previous frame and clear below it
+		_, _ = fmt.Fprintf(r.out, "\x1b[%dA\x1b[J", r.lines)
+	}
+	_, _ = fmt.Fprintln(r.out, frame)
+	r.last = frame
+	r.lines = strings.Count(frame, "\n") + 1
+}
+
+// isTerminal returns true if w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
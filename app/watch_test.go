@@ -0,0 +1,220 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+func TestWatch_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		conclusion string
+		want       int
+	}{
+		{"success", "success", WatchExitSuccess},
+		{"failure", "failure", WatchExitFailure},
+		{"cancelled", "cancelled", WatchExitCancelled},
+		{"timed out", "timed_out", WatchExitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockClient(&mockClientState{
+				runs: []github.Run{{ID: 1, Name: "CI", RunNumber: 7, Status: "completed", Conclusion: tt.conclusion}},
+				jobs: []github.Job{{ID: 10, Name: "build", Status: "completed", Conclusion: tt.conclusion}},
+			})
+			var out bytes.Buffer
+
+			code, err := Watch(context.Background(), mock, github.Repository{Owner: "o", Name: "r"},
+				WatchOptions{RunID: 1, Interval: time.Millisecond}, &out)
+			if err != nil {
+				t.Fatalf("Watch() unexpected error: %v", err)
+			}
+			if code != tt.want {
+				t.Errorf("Watch() = %d, want %d", code, tt.want)
+			}
+			if !strings.Contains(out.String(), "build") {
+				t.Errorf("output should contain job name, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestWatch_PollsUntilCompleted(t *testing.T) {
+	calls := 0
+	mock := newMockClient(&mockClientState{
+		jobs: []github.Job{{ID: 10, Name: "build", Status: "in_progress"}},
+	})
+	mock.GetRunFunc = func(ctx context.Context, repo github.Repository, runID int64) (*github.Run, error) {
+		calls++
+		if calls < 3 {
+			return &github.Run{ID: runID, Name: "CI", Status: "in_progress"}, nil
+		}
+		return &github.Run{ID: runID, Name: "CI", Status: "completed", Conclusion: "success"}, nil
+	}
+	var out bytes.Buffer
+
+	code, err := Watch(context.Background(), mock, github.Repository{}, WatchOptions{RunID: 5, Interval: time.Millisecond}, &out)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	if code != WatchExitSuccess {
+		t.Errorf("Watch() = %d, want %d", code, WatchExitSuccess)
+	}
+	if calls != 3 {
+		t.Errorf("GetRun called %d times, want 3", calls)
+	}
+	// Unchanged frames are not printed again
+	if n := strings.Count(out.String(), "in progress"); n != 1 {
+		t.Errorf("in progress frame printed %d times, want 1", n)
+	}
+}
+
+func TestWatch_Commit(t *testing.T) {
+	mock := newMockClient(&mockClientState{
+		runs: []github.Run{
+			{ID: 1, Name: "CI", Status: "completed", Conclusion: "success"},
+			{ID: 2, Name: "Lint", Status: "completed", Conclusion: "failure"},
+		},
+	})
+	var out bytes.Buffer
+
+	code, err := Watch(context.Background(), mock, github.Repository{}, WatchOptions{Commit: "abc123", Interval: time.Millisecond, Grace: time.Millisecond}, &out)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	if code != WatchExitFailure {
+		t.Errorf("Watch() = %d, want %d", code, WatchExitFailure)
+	}
+	calls := mock.ListRunsCalls()
+	if len(calls) == 0 || calls[0].Opts.HeadSHA != "abc123" {
+		t.Errorf("ListRuns should filter by head SHA, got %+v", calls)
+	}
+	if len(mock.ListJobsCalls()) != 2 {
+		t.Errorf("ListJobs called %d times, want 2", len(mock.ListJobsCalls()))
+	}
+}
+
+func TestWatch_BranchFollowsHeadCommit(t *testing.T) {
+	mock := newMockClient(&mockClientState{
+		headSHA: "def456",
+		runs:    []github.Run{{ID: 1, Name: "CI", Status: "completed", Conclusion: "success"}},
+	})
+	var out bytes.Buffer
+
+	code, err := Watch(context.Background(), mock, github.Repository{},
+		WatchOptions{Branch: "main", Interval: time.Millisecond, Grace: time.Millisecond}, &out)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	if code != WatchExitSuccess {
+		t.Errorf("Watch() = %d, want %d", code, WatchExitSuccess)
+	}
+	if calls := mock.ResolveRefCalls(); len(calls) != 1 || calls[0].Ref != "main" {
+		t.Errorf("ResolveRef calls = %+v, want one for main", calls)
+	}
+	for _, call := range mock.ListRunsCalls() {
+		if call.Opts.Branch != "main" || call.Opts.HeadSHA != "def456" {
+			t.Errorf("ListRuns should filter by branch and head commit, got %+v", call.Opts)
+		}
+	}
+}
+
+func TestWatch_WaitsForLateRuns(t *testing.T) {
+	ci := github.Run{ID: 1, Name: "CI", Status: "completed", Conclusion: "success"}
+	deploy := github.Run{ID: 2, Name: "Deploy", Status: "in_progress"}
+	polls := 0
+	mock := newMockClient(&mockClientState{})
+	mock.ListRunsFunc = func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
+		polls++
+		switch {
+		case polls == 1:
+			return []github.Run{ci}, nil
+		case polls < 4:
+			return []github.Run{ci, deploy}, nil
+		default:
+			failed := deploy
+			failed.Status, failed.Conclusion = "completed", "failure"
+			return []github.Run{ci, failed}, nil
+		}
+	}
+	var out bytes.Buffer
+
+	code, err := Watch(context.Background(), mock, github.Repository{},
+		WatchOptions{Commit: "abc123", Interval: time.Millisecond, Grace: 50 * time.Millisecond}, &out)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	if code != WatchExitFailure {
+		t.Errorf("Watch() = %d, want %d for the run that started late", code, WatchExitFailure)
+	}
+	if !strings.Contains(out.String(), "Deploy") {
+		t.Errorf("output should contain the late run, got %q", out.String())
+	}
+}
+
+func TestWatch_NoRunsTimesOut(t *testing.T) {
+	mock := newMockClient(&mockClientState{})
+	var out bytes.Buffer
+
+	code, err := Watch(context.Background(), mock, github.Repository{},
+		WatchOptions{Branch: "main", Interval: time.Millisecond, WaitTimeout: 5 * time.Millisecond}, &out)
+	if err == nil {
+		t.Fatal("Watch() expected error when no runs appear")
+	}
+	if code != WatchExitFailure {
+		t.Errorf("Watch() = %d, want %d", code, WatchExitFailure)
+	}
+	if !strings.Contains(out.String(), "Waiting for runs of branch main") {
+		t.Errorf("output should show waiting message, got %q", out.String())
+	}
+}
+
+func TestWatch_RequiresTarget(t *testing.T) {
+	code, err := Watch(context.Background(), newMockClient(nil), github.Repository{}, WatchOptions{}, &bytes.Buffer{})
+	if err == nil {
+		t.Error("Watch() expected error without a target")
+	}
+	if code != WatchExitFailure {
+		t.Errorf("Watch() = %d, want %d", code, WatchExitFailure)
+	}
+}
+
+func TestRenderWatchTree(t *testing.T) {
+	runs := []github.Run{{ID: 1, Name: "CI", RunNumber: 3, Event: "push", Branch: "main", Status: "in_progress"}}
+	jobs := map[int64][]github.Job{
+		1: {
+			{Name: "build", Status: "completed", Conclusion: "success", Steps: []github.Step{{Name: "Compile", Status: "completed", Conclusion: "success"}}},
+			{Name: "test", Status: "in_progress", Steps: []github.Step{{Name: "Run tests", Status: "in_progress"}}},
+		},
+	}
+
+	tree := renderWatchTree(runs, jobs)
+
+	if !strings.Contains(tree, "CI #3 push main (in progress)") {
+		t.Errorf("tree should contain run header, got %q", tree)
+	}
+	if strings.Contains(tree, "Compile") {
+		t.Error("steps of successful jobs should be collapsed")
+	}
+	if !strings.Contains(tree, "Run tests") {
+		t.Error("steps of running jobs should be shown")
+	}
+}
+
+func TestWatchRenderer_LiveRedraw(t *testing.T) {
+	var out bytes.Buffer
+	r := &watchRenderer{out: &out, live: true}
+
+	r.Render("a\nb")
+	r.Render("c")
+
+	if !strings.Contains(out.String(), "\x1b[2A\x1b[J") {
+		t.Errorf("live renderer should clear the previous two lines, got %q", out.String())
+	}
+}
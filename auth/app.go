@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHub App constants
+const (
+	// DefaultAPIURL is the REST API of github.com
+	DefaultAPIURL = "https://api.github.com"
+	// AppJWTLifetime is how long a minted app JWT is valid. GitHub accepts at most 10 minutes.
+	AppJWTLifetime = 9 * time.Minute
+	// AppJWTClockSkew backdates the JWT issue time to allow for clock drift
+	AppJWTClockSkew = time.Minute
+	// AppTokenRefreshMargin is how long before expiry an installation token is replaced
+	AppTokenRefreshMargin = 5 * time.Minute
+)
+
+// AppCredentials identify a GitHub App installation.
+type AppCredentials struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKey     *rsa.PrivateKey
+}
+
+// LoadAppCredentials reads the app's private key from a PEM file, as
+// downloaded from the app settings.
+func LoadAppCredentials(appID, installationID int64, keyFile string) (AppCredentials, error) {
+	if appID <= 0 || installationID <= 0 {
+		return AppCredentials{}, errors.New("GitHub App requires an app ID and an installation ID")
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return AppCredentials{}, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	key, err := parsePrivateKey(data)
+	if err != nil {
+		return AppCredentials{}, fmt.Errorf("invalid GitHub App private key %s: %w", keyFile, err)
+	}
+	return AppCredentials{AppID: appID, InstallationID: installationID, PrivateKey: key}, nil
+}
+
+// parsePrivateKey parses an RSA private key in PKCS #1 or PKCS #8 PEM form
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA key")
+	}
+	return key, nil
+}
+
+// AppTokenSource provides installation tokens for a GitHub App. A token is
+// minted on first use and replaced shortly before it expires, so a client
+// using the source keeps working for longer than the one-hour token lifetime.
+// It is safe for concurrent use.
+type AppTokenSource struct {
+	creds      AppCredentials
+	apiURL     string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	token     SecureToken
+	expiresAt time.Time
+}
+
+// NewAppTokenSource creates a token source for the installation, exchanging
+// tokens with the REST API at apiURL (DefaultAPIURL if empty).
+func NewAppTokenSource(creds AppCredentials, apiURL string) *AppTokenSource {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &AppTokenSource{
+		creds:      creds,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// Token returns a valid installation token, refreshing it if it is missing
+// or about to expire.
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Value() != "" && s.now().Before(s.expiresAt.Add(-AppTokenRefreshMargin)) {
+		return s.token.Value(), nil
+	}
+	token, expiresAt, err := s.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiresAt = token, expiresAt
+	return token.Value(), nil
+}
+
+// ExpiresAt returns when the current installation token expires, or the
+// zero time if no token has been minted yet.
+func (s *AppTokenSource) ExpiresAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expiresAt
+}
+
+// installationToken is the response of the access token endpoint
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// exchange mints an app JWT and exchanges it for an installation token
+func (s *AppTokenSource) exchange(ctx context.Context) (SecureToken, time.Time, error) {
+	jwt, err := appJWT(s.creds, s.now())
+	if err != nil {
+		return SecureToken{}, time.Time{}, err
+	}
+
+	url := s.apiURL + "/app/installations/" + strconv.FormatInt(s.creds.InstallationID, 10) + "/access_tokens"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return SecureToken{}, time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return SecureToken{}, time.Time{}, fmt.Errorf("failed to get installation token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return SecureToken{}, time.Time{}, fmt.Errorf("failed to get installation token: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return SecureToken{}, time.Time{}, fmt.Errorf("failed to get installation token: %s %s", resp.Status, apiErr.Message)
+	}
+
+	var it installationToken
+	if err := json.Unmarshal(body, &it); err != nil {
+		return SecureToken{}, time.Time{}, fmt.Errorf("invalid installation token response: %w", err)
+	}
+	token, err := NewSecureToken(it.Token)
+	if err != nil {
+		return SecureToken{}, time.Time{}, fmt.Errorf("installation token: %w", err)
+	}
+	return token, it.ExpiresAt, nil
+}
+
+// appJWT mints the RS256 JSON Web Token that authenticates as the app
+func appJWT(creds AppCredentials, now time.Time) (string, error) {
+	if creds.PrivateKey == nil {
+		return "", errors.New("GitHub App private key is missing")
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iat": now.Add(-AppJWTClockSkew).Unix(),
+		"exp": now.Add(AppJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(creds.AppID, 10),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, creds.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
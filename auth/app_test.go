@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testAppKey is shared by the tests because generating RSA keys is slow
+var testAppKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// verifyAppJWT checks the signature of an app JWT and returns its claims
+func verifyAppJWT(t *testing.T, jwt string) map[string]any {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&testAppKey.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("JWT signature invalid: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("unmarshal claims: %v", err)
+	}
+	return claims
+}
+
+func TestAppJWT(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	jwt, err := appJWT(AppCredentials{AppID: 123, InstallationID: 456, PrivateKey: testAppKey}, now)
+	if err != nil {
+		t.Fatalf("appJWT() error = %v", err)
+	}
+	claims := verifyAppJWT(t, jwt)
+	if claims["iss"] != "123" {
+		t.Errorf("iss = %v, want 123", claims["iss"])
+	}
+	if got := int64(claims["iat"].(float64)); got != now.Add(-AppJWTClockSkew).Unix() {
+		t.Errorf("iat = %d, want backdated issue time", got)
+	}
+	if got := int64(claims["exp"].(float64)); got != now.Add(AppJWTLifetime).Unix() {
+		t.Errorf("exp = %d, want %d", got, now.Add(AppJWTLifetime).Unix())
+	}
+
+	if _, err := appJWT(AppCredentials{AppID: 123}, now); err == nil {
+		t.Error("appJWT() without key should fail")
+	}
+}
+
+func TestLoadAppCredentials(t *testing.T) {
+	dir := t.TempDir()
+	pkcs1 := filepath.Join(dir, "pkcs1.pem")
+	if err := os.WriteFile(pkcs1, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testAppKey)}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(testAppKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8 := filepath.Join(dir, "pkcs8.pem")
+	if err := os.WriteFile(pkcs8, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{pkcs1, pkcs8} {
+		creds, err := LoadAppCredentials(123, 456, path)
+		if err != nil {
+			t.Errorf("LoadAppCredentials(%s) error = %v", filepath.Base(path), err)
+			continue
+		}
+		if !creds.PrivateKey.Equal(testAppKey) {
+			t.Errorf("LoadAppCredentials(%s) loaded a different key", filepath.Base(path))
+		}
+	}
+
+	if _, err := LoadAppCredentials(123, 456, garbage); err == nil {
+		t.Error("LoadAppCredentials() with invalid PEM should fail")
+	}
+	if _, err := LoadAppCredentials(123, 456, filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("LoadAppCredentials() with missing file should fail")
+	}
+	if _, err := LoadAppCredentials(0, 456, pkcs1); err == nil {
+		t.Error("LoadAppCredentials() without app ID should fail")
+	}
+}
+
+// installationServer serves installation tokens that expire an hour after
+// the given clock, counting the exchanges
+func installationServer(t *testing.T, now func() time.Time, exchanges *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/456/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		verifyAppJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		n := exchanges.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(installationToken{
+			Token:     "ghs_" + strings.Repeat(string(rune('a'+n)), 36),
+			ExpiresAt: now().Add(time.Hour),
+		})
+	}))
+}
+
+func TestAppTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	var exchanges atomic.Int32
+	server := installationServer(t, clock, &exchanges)
+	defer server.Close()
+
+	src := NewAppTokenSource(AppCredentials{AppID: 123, InstallationID: 456, PrivateKey: testAppKey}, server.URL)
+	src.now = clock
+
+	first, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if !strings.HasPrefix(first, "ghs_") {
+		t.Errorf("Token() = %q, want an installation token", first)
+	}
+	if !src.ExpiresAt().Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt() = %v, want %v", src.ExpiresAt(), now.Add(time.Hour))
+	}
+
+	// Still well within the lifetime: the token is reused
+	now = now.Add(50 * time.Minute)
+	if got, _ := src.Token(context.Background()); got != first || exchanges.Load() != 1 {
+		t.Errorf("Token() = %q after %d exchanges, want cached token", got, exchanges.Load())
+	}
+
+	// Within the refresh margin: a new token is minted
+	now = now.Add(6 * time.Minute)
+	second, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if second == first || exchanges.Load() != 2 {
+		t.Errorf("Token() = %q after %d exchanges, want refreshed token", second, exchanges.Load())
+	}
+}
+
+func TestAppTokenSource_ExchangeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"A JSON web token could not be decoded"}`))
+	}))
+	defer server.Close()
+
+	src := NewAppTokenSource(AppCredentials{AppID: 123, InstallationID: 456, PrivateKey: testAppKey}, server.URL)
+	_, err := src.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "could not be decoded") {
+		t.Errorf("Token() error = %v, want API message", err)
+	}
+}
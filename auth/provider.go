@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultHost is the GitHub host tokens are resolved for by default.
+const DefaultHost = "github.com"
+
+// TokenCommandTimeout bounds how long a token command may run
+const TokenCommandTimeout = 30 * time.Second
+
+// ErrNoToken is returned by a Provider that has no token for a host.
+var ErrNoToken = errors.New("no token")
+
+// Provider is a source of GitHub tokens.
+type Provider interface {
+	// Name identifies the provider in diagnostics, e.g. "GH_TOKEN".
+	Name() string
+	// Token returns the token for host, or ErrNoToken if there is none.
+	Token(host string) (string, error)
+}
+
+// Options configures the providers of DefaultChain.
+type Options struct {
+	TokenFile    string // Path of a file holding the token (--token-file)
+	TokenCommand string // Shell command printing the token (token_command in config)
+}
+
+// Chain is an ordered list of token providers. The first provider with a
+// token for the host supplies it.
+type Chain []Provider
+
+// DefaultChain returns the providers in order of precedence:
+//  1. --token-file
+//  2. GH_TOKEN (github.com) or GH_ENTERPRISE_TOKEN (other hosts)
+//  3. token_command from the config file
+//  4. gh CLI (gh auth token), which also reads the system keyring
+//  5. gh's hosts.yml, for when the gh binary is not installed
+//  6. GITHUB_TOKEN (github.com) or GITHUB_ENTERPRISE_TOKEN (other hosts)
+func DefaultChain(opts Options) Chain {
+	return Chain{
+		tokenFileProvider{path: opts.TokenFile},
+		envProvider{name: "GH_TOKEN"},
+		envProvider{name: "GH_ENTERPRISE_TOKEN", enterprise: true},
+		commandProvider{command: opts.TokenCommand},
+		ghCLIProvider{},
+		hostsFileProvider{path: ghHostsPath()},
+		envProvider{name: "GITHUB_TOKEN"},
+		envProvider{name: "GITHUB_ENTERPRISE_TOKEN", enterprise: true},
+	}
+}
+
+// Resolve returns the token for host from the first provider that has one,
+// and the name of that provider. A provider that fails, rather than having
+// no token, stops the chain, so a broken configuration is reported instead
+// of silently falling back to another account.
+func (c Chain) Resolve(host string) (SecureToken, string, error) {
+	for _, p := range c {
+		value, err := p.Token(host)
+		if errors.Is(err, ErrNoToken) || (err == nil && strings.TrimSpace(value) == "") {
+			continue
+		}
+		if err != nil {
+			return SecureToken{}, "", fmt.Errorf("%s: %w", p.Name(), err)
+		}
+		token, err := NewSecureToken(value)
+		if err != nil {
+			return SecureToken{}, "", fmt.Errorf("token from %s: %w", p.Name(), err)
+		}
+		return token, p.Name(), nil
+	}
+	return SecureToken{}, "", fmt.Errorf("no authentication token found for %s: run 'gh auth login' or set GH_TOKEN", host)
+}
+
+// ProviderStatus is the outcome of one provider in Diagnose.
+type ProviderStatus struct {
+	Name  string
+	Found bool  // The provider has a token for the host
+	Err   error // Why the provider failed or its token is invalid, if it did
+}
+
+// Diagnose asks every provider for a token for host, for troubleshooting.
+// Tokens are validated but not returned.
+func (c Chain) Diagnose(host string) []ProviderStatus {
+	statuses := make([]ProviderStatus, 0, len(c))
+	for _, p := range c {
+		status := ProviderStatus{Name: p.Name()}
+		value, err := p.Token(host)
+		switch {
+		case errors.Is(err, ErrNoToken) || (err == nil && strings.TrimSpace(value) == ""):
+		case err != nil:
+			status.Err = err
+		default:
+			status.Found = true
+			if _, err := NewSecureToken(value); err != nil {
+				status.Err = err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// isEnterpriseHost reports whether host is a GitHub Enterprise Server,
+// as opposed to github.com or a GHE.com data residency tenant
+func isEnterpriseHost(host string) bool {
+	host = strings.ToLower(host)
+	return host != DefaultHost && !strings.HasSuffix(host, ".ghe.com")
+}
+
+// envProvider reads the token from an environment variable. Like the gh
+// CLI, enterprise variables only apply to GitHub Enterprise Server hosts
+// and the others only to github.com.
+type envProvider struct {
+	name       string
+	enterprise bool
+}
+
+func (p envProvider) Name() string { return p.name }
+
+func (p envProvider) Token(host string) (string, error) {
+	if isEnterpriseHost(host) != p.enterprise {
+		return "", ErrNoToken
+	}
+	value := os.Getenv(p.name)
+	if value == "" {
+		return "", ErrNoToken
+	}
+	return value, nil
+}
+
+// tokenFileProvider reads the token from a file, for any host
+type tokenFileProvider struct {
+	path string
+}
+
+func (p tokenFileProvider) Name() string { return "--token-file" }
+
+func (p tokenFileProvider) Token(string) (string, error) {
+	if p.path == "" {
+		return "", ErrNoToken
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// commandProvider runs a shell command that prints the token, such as a
+// password manager CLI. The host is passed in LAZYACTIONS_HOST.
+type commandProvider struct {
+	command string
+}
+
+func (p commandProvider) Name() string { return "token_command" }
+
+func (p commandProvider) Token(host string) (string, error) {
+	if p.command == "" {
+		return "", ErrNoToken
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), TokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.command)
+	}
+	cmd.Env = append(os.Environ(), "LAZYACTIONS_HOST="+host)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("command failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ghCLIProvider asks the gh CLI for its token
+type ghCLIProvider struct{}
+
+func (ghCLIProvider) Name() string { return "gh auth token" }
+
+func (ghCLIProvider) Token(host string) (string, error) {
+	token, err := ghCLIFunc(host)
+	if err != nil {
+		return "", ErrNoToken
+	}
+	return token, nil
+}
+
+// hostsFileProvider reads the token gh stores in its hosts.yml when the
+// system keyring is not used
+type hostsFileProvider struct {
+	path string
+}
+
+func (p hostsFileProvider) Name() string { return "gh hosts.yml" }
+
+// ghHostEntry is a host in gh's hosts.yml
+type ghHostEntry struct {
+	OAuthToken string `yaml:"oauth_token"`
+	User       string `yaml:"user"`
+	Users      map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	} `yaml:"users"`
+}
+
+func (p hostsFileProvider) Token(host string) (string, error) {
+	if p.path == "" {
+		return "", ErrNoToken
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", ErrNoToken
+	}
+	var hosts map[string]ghHostEntry
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", p.path, err)
+	}
+	entry, ok := hosts[host]
+	if !ok {
+		return "", ErrNoToken
+	}
+	if entry.OAuthToken != "" {
+		return entry.OAuthToken, nil
+	}
+	// Newer gh versions store tokens per user, keyed by the active user
+	if u, ok := entry.Users[entry.User]; ok && u.OAuthToken != "" {
+		return u.OAuthToken, nil
+	}
+	return "", ErrNoToken
+}
+
+// ghHostsPath returns the path of gh's hosts.yml, following gh's lookup of
+// its configuration directory
+func ghHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI", "hosts.yml")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
@@ -1,10 +1,12 @@
 // Package auth provides GitHub authentication functionality.
-// It supports token retrieval from gh CLI and environment variables.
+// It supports token retrieval from environment variables, the gh CLI and its
+// hosts.yml, a token file and a user-configured command, and GitHub App
+// installation tokens that are refreshed before they expire.
 package auth
 
 import (
 	"errors"
-	"os"
+	"log/slog"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -18,8 +20,8 @@ var ghCLIFunc = getFromGhCLI
 var legacyTokenPattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
 
 // SecureToken wraps a GitHub token and prevents accidental exposure in logs.
-// It implements fmt.Stringer and fmt.GoStringer to return "[REDACTED]"
-// instead of the actual token value.
+// It implements fmt.Stringer, fmt.GoStringer and slog.LogValuer to return
+// "[REDACTED]" instead of the actual token value.
 type SecureToken struct {
 	value string
 }
@@ -36,6 +38,12 @@ func (t SecureToken) GoString() string {
 	return "[REDACTED]"
 }
 
+// LogValue returns "[REDACTED]" to prevent token leakage in structured logs.
+// This implements the slog.LogValuer interface.
+func (t SecureToken) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+
 // Value returns the actual token value.
 // Use this method only when the token needs to be passed to the GitHub API.
 func (t SecureToken) Value() string {
@@ -61,29 +69,19 @@ func NewSecureToken(token string) (SecureToken, error) {
 	return SecureToken{value: token}, nil
 }
 
-// GetToken retrieves a GitHub token using the following priority:
-//  1. gh CLI (gh auth token) - recommended, no configuration needed
-//  2. GITHUB_TOKEN environment variable
+// GetToken retrieves a GitHub token for github.com from the providers of
+// DefaultChain without a token file or command.
 //
 // Returns an error if no valid token is found.
 func GetToken() (SecureToken, error) {
-	// 1. Try gh CLI first (highest priority)
-	if token, err := ghCLIFunc(); err == nil && token != "" {
-		return NewSecureToken(token)
-	}
-
-	// 2. Fall back to environment variable
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		return NewSecureToken(token)
-	}
-
-	return SecureToken{}, errors.New("no authentication token found: run 'gh auth login' or set GITHUB_TOKEN")
+	token, _, err := DefaultChain(Options{}).Resolve(DefaultHost)
+	return token, err
 }
 
-// getFromGhCLI retrieves the GitHub token from the gh CLI.
-// It runs "gh auth token" and returns the output.
-func getFromGhCLI() (string, error) {
-	cmd := exec.Command("gh", "auth", "token")
+// getFromGhCLI retrieves the GitHub token for host from the gh CLI.
+// It runs "gh auth token --hostname <host>" and returns the output.
+func getFromGhCLI(host string) (string, error) {
+	cmd := exec.Command("gh", "auth", "token", "--hostname", host)
 	out, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/nnnkkk7/lazyactions/auth"
+	"github.com/nnnkkk7/lazyactions/config"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// authFlags select where the authentication token comes from
+type authFlags struct {
+	tokenFile string
+}
+
+// addAuthFlags registers the authentication flags on fs
+func addAuthFlags(fs *flag.FlagSet) *authFlags {
+	f := &authFlags{}
+	fs.StringVar(&f.tokenFile, "token-file", "", "read the GitHub token from a file")
+	return f
+}
+
+// credentials is how the GitHub client authenticates
+type credentials struct {
+	token  auth.SecureToken   // Static token, if tokens is nil
+	tokens github.TokenSource // Refreshing token source of a GitHub App
+	source string             // Where the token comes from
+}
+
+// clientOptions returns the client options that apply the credentials
+func (c credentials) clientOptions() []github.ClientOption {
+	if c.tokens == nil {
+		return nil
+	}
+	return []github.ClientOption{github.WithTokenSource(c.tokens)}
+}
+
+// authenticate resolves the credentials from the configuration and the
+// flags. A configured GitHub App takes precedence over the token chain.
+func authenticate(ctx context.Context, f *authFlags, cfg *config.Config) (credentials, error) {
+	if cfg.GitHubApp != nil {
+		src, err := appTokenSource(cfg.GitHubApp)
+		if err != nil {
+			return credentials{}, err
+		}
+		// Mint the first token now so bad credentials fail before the TUI starts
+		if _, err := src.Token(ctx); err != nil {
+			return credentials{}, err
+		}
+		return credentials{tokens: src, source: appSourceName(cfg.GitHubApp)}, nil
+	}
+
+	token, source, err := authChain(f, cfg).Resolve(auth.DefaultHost)
+	if err != nil {
+		return credentials{}, err
+	}
+	return credentials{token: token, source: source}, nil
+}
+
+// appTokenSource creates the installation token source of a configured app
+func appTokenSource(app *config.GitHubApp) (*auth.AppTokenSource, error) {
+	creds, err := auth.LoadAppCredentials(app.AppID, app.InstallationID, app.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewAppTokenSource(creds, auth.DefaultAPIURL), nil
+}
+
+// appSourceName describes a GitHub App as a token source
+func appSourceName(app *config.GitHubApp) string {
+	return "GitHub App " + strconv.FormatInt(app.AppID, 10) +
+		" (installation " + strconv.FormatInt(app.InstallationID, 10) + ")"
+}
+
+// authChain builds the ordered token provider chain from the flags and the
+// token_command of the configuration file.
+func authChain(f *authFlags, cfg *config.Config) auth.Chain {
+	return auth.DefaultChain(auth.Options{
+		TokenFile:    f.tokenFile,
+		TokenCommand: cfg.TokenCommand,
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// debugFlags enable the debug log. The TUI owns the terminal, so the log is
+// written to a file.
+type debugFlags struct {
+	enabled bool
+	path    string
+}
+
+// addDebugFlags registers the debug log flags on fs
+func addDebugFlags(fs *flag.FlagSet) *debugFlags {
+	f := &debugFlags{}
+	fs.BoolVar(&f.enabled, "debug", false, "write a structured debug log of API requests and UI events")
+	fs.StringVar(&f.path, "debug-file", "", "debug log file, implies --debug (default $XDG_CACHE_HOME/lazyactions/debug.log)")
+	return f
+}
+
+// openDebugLog opens the debug log file. It returns a logger that discards everything if debugging is disabled,
+// and a function that closes the file.
+func openDebugLog(f *debugFlags) (*slog.Logger, func(), error) {
+	if !f.enabled && f.path == "" {
+		return slog.New(slog.DiscardHandler), func() {}, nil
+	}
+
+	path := f.path
+	if path == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to locate debug log: %w", err)
+		}
+		path = filepath.Join(dir, "lazyactions", "debug.log")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create debug log: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open debug log: %w", err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return logger, func() { _ = file.Close() }, nil
+}
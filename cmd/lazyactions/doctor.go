@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/auth"
+	"github.com/nnnkkk7/lazyactions/config"
+)
+
+const doctorUsage = `Usage: lazyactions doctor
+
+Show which authentication sources have a token, in the order they are tried.
+
+Options:
+`
+
+// runDoctor implements the "doctor" subcommand.
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	host := fs.String("hostname", auth.DefaultHost, "GitHub host to check")
+	af := addAuthFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), doctorUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		return err
+	}
+	if cfg.GitHubApp != nil {
+		return printAppDiagnosis(os.Stdout, cfg.GitHubApp)
+	}
+	return printDiagnosis(os.Stdout, *host, authChain(af, cfg))
+}
+
+// printAppDiagnosis writes whether the configured GitHub App can get an
+// installation token. The app takes precedence over all other sources.
+func printAppDiagnosis(w io.Writer, app *config.GitHubApp) error {
+	if path, err := config.DefaultPath(); err == nil {
+		fmt.Fprintf(w, "Config: %s\n", path)
+	}
+	fmt.Fprintf(w, "Using %s, other token sources are ignored\n\n", appSourceName(app))
+
+	src, err := appTokenSource(app)
+	if err != nil {
+		fmt.Fprintf(w, "  ✗ %v\n", err)
+		return err
+	}
+	if _, err := src.Token(context.Background()); err != nil {
+		fmt.Fprintf(w, "  ✗ %v\n", err)
+		return err
+	}
+	fmt.Fprintf(w, "  ✓ installation token valid until %s, refreshed automatically\n",
+		src.ExpiresAt().Local().Format(time.DateTime))
+	return nil
+}
+
+// printDiagnosis writes the status of every provider in chain and the one
+// that would be used. It returns an error if no usable token was found.
+func printDiagnosis(w io.Writer, host string, chain auth.Chain) error {
+	fmt.Fprintf(w, "Host:   %s\n", host)
+	if path, err := config.DefaultPath(); err == nil {
+		fmt.Fprintf(w, "Config: %s\n", path)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Authentication sources, in order:")
+	for _, status := range chain.Diagnose(host) {
+		switch {
+		case status.Err != nil:
+			fmt.Fprintf(w, "  ✗ %-24s %v\n", status.Name, status.Err)
+		case status.Found:
+			fmt.Fprintf(w, "  ✓ %-24s token found\n", status.Name)
+		default:
+			fmt.Fprintf(w, "  - %-24s no token\n", status.Name)
+		}
+	}
+	fmt.Fprintln(w)
+
+	_, source, err := chain.Resolve(host)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Using token from %s\n", source)
+	return nil
+}
@@ -2,37 +2,133 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/nnnkkk7/lazyactions/app"
-	"github.com/nnnkkk7/lazyactions/auth"
+	"github.com/nnnkkk7/lazyactions/config"
 	"github.com/nnnkkk7/lazyactions/github"
+	"github.com/nnnkkk7/lazyactions/logcache"
 	"github.com/nnnkkk7/lazyactions/repo"
 )
 
 func main() {
-	if err := run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		code, err := runWatch(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(code)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		if err := runDoctor(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(args []string) error {
+	fs := flag.NewFlagSet("lazyactions", flag.ContinueOnError)
+	debug := addDebugFlags(fs)
+	af := addAuthFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	logger, closeLog, err := openDebugLog(debug)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		return err
+	}
+	redactor, err := github.NewRedactor(github.RedactorOptions{
+		Patterns: cfg.Redaction.Patterns,
+		Literals: cfg.Redaction.Values,
+		Entropy:  cfg.Redaction.Entropy,
+	})
+	if err != nil {
+		return err
+	}
+	matchers := app.NewMatcherRegistry()
+	for _, path := range cfg.ProblemMatchers {
+		if err := matchers.LoadFile(path); err != nil {
+			return err
+		}
+	}
+
+	client, repository, err := setup(logger, af, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Annotated files are opened relative to the local checkout
+	root, _ := repo.Root()
+	opts := []app.Option{app.WithLocalRoot(root), app.WithLogger(logger), app.WithRedactor(redactor), app.WithProblemMatchers(matchers)}
+
+	if cfg.AutoRefresh {
+		opts = append(opts, app.WithAutoRefresh())
+	}
+	diffOpts := app.DefaultLogDiffOptions()
+	if v := cfg.LogDiff.MaskDurations; v != nil {
+		diffOpts.MaskDurations = *v
+	}
+	if v := cfg.LogDiff.MaskHashes; v != nil {
+		diffOpts.MaskHashes = *v
+	}
+	opts = append(opts, app.WithLogDiffOptions(diffOpts))
+
+	// Logs of completed jobs are cached on disk
+	if dir, err := logcache.DefaultDir(); err == nil {
+		opts = append(opts, app.WithLogCache(logcache.New(dir, logcache.DefaultMaxBytes)))
+	}
+
+	// Run TUI
+	return app.Run(client, repository, opts...)
+}
+
+// setup detects the repository and creates an authenticated GitHub client.
+func setup(logger *slog.Logger, af *authFlags, cfg *config.Config) (github.Client, github.Repository, error) {
 	// Detect repository from current directory
 	repoInfo, err := repo.Detect()
 	if err != nil {
-		return fmt.Errorf("failed to detect repository: %w", err)
+		return nil, github.Repository{}, fmt.Errorf("failed to detect repository: %w", err)
 	}
 
-	// Get authentication token (gh CLI -> GITHUB_TOKEN)
-	token, err := auth.GetToken()
+	// Authenticate as a GitHub App or with the first provider that has a token
+	creds, err := authenticate(context.Background(), af, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get authentication: %w", err)
+		return nil, github.Repository{}, fmt.Errorf("failed to get authentication: %w", err)
 	}
 
-	// Create GitHub client
-	client := github.NewClient(token.Value(), repoInfo.Owner, repoInfo.Name)
+	// Create GitHub client, persisting conditional request validators
+	// so polling after a restart is also served by 304 responses
+	opts := append(creds.clientOptions(), github.WithLogger(logger))
+	if dir, err := github.DefaultHTTPCacheDir(); err == nil && !cfg.HTTPCache.MemoryOnly {
+		maxBytes := int64(cfg.HTTPCache.MaxSizeMB) << 20
+		maxAge := time.Duration(cfg.HTTPCache.MaxAgeDays) * 24 * time.Hour
+		opts = append(opts, github.WithHTTPCache(github.NewDiskCache(dir, maxBytes, maxAge)))
+	}
+	client := github.NewClient(creds.token.Value(), repoInfo.Owner, repoInfo.Name, opts...)
+	logger.Info("client ready", "owner", repoInfo.Owner, "repo", repoInfo.Name, "token", creds.token, "token_source", creds.source)
 
 	// Create repository struct
 	repository := github.Repository{
@@ -40,6 +136,5 @@ func run() error {
 		Name:  repoInfo.Name,
 	}
 
-	// Run TUI
-	return app.Run(client, repository)
+	return client, repository, nil
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/nnnkkk7/lazyactions/app"
+	"github.com/nnnkkk7/lazyactions/config"
+	"github.com/nnnkkk7/lazyactions/github"
+	"github.com/nnnkkk7/lazyactions/repo"
+)
+
+const watchUsage = `Usage: lazyactions watch [run-id | --branch <name|current> | --commit <rev>]
+
+Follow workflow runs until they finish, printing their job and step status.
+Exits 0 on success, 1 on failure and 2 when a run was cancelled.
+
+Options:
+`
+
+// runWatch implements the "watch" subcommand and returns the process exit code.
+func runWatch(args []string) (int, error) {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	branch := fs.String("branch", "", `watch all runs for the head commit of a branch ("current" for the checked out branch)`)
+	commit := fs.String("commit", "", `watch all runs for a commit (e.g. "HEAD")`)
+	interval := fs.Duration("interval", app.DefaultWatchInterval, "polling interval")
+	debug := addDebugFlags(fs)
+	af := addAuthFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), watchUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, nil
+		}
+		return app.WatchExitFailure, err
+	}
+
+	opts, err := parseWatchTarget(fs.Args(), *branch, *commit)
+	if err != nil {
+		fs.Usage()
+		return app.WatchExitFailure, err
+	}
+	opts.Interval = *interval
+
+	logger, closeLog, err := openDebugLog(debug)
+	if err != nil {
+		return app.WatchExitFailure, err
+	}
+	defer closeLog()
+
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		return app.WatchExitFailure, err
+	}
+	client, repository, err := setup(logger, af, cfg)
+	if err != nil {
+		return app.WatchExitFailure, err
+	}
+
+	ctx, stop := signal.NotifyContext(github.ContextWithLogger(context.Background(), logger), os.Interrupt)
+	defer stop()
+
+	return app.Watch(ctx, client, repository, opts, os.Stdout)
+}
+
+// parseWatchTarget builds WatchOptions from the positional run ID or the
+// --branch/--commit flags, resolving "current" and git revisions locally.
+func parseWatchTarget(positional []string, branch, commit string) (app.WatchOptions, error) {
+	var opts app.WatchOptions
+
+	targets := len(positional)
+	if branch != "" {
+		targets++
+	}
+	if commit != "" {
+		targets++
+	}
+	if targets != 1 {
+		return opts, errors.New("specify exactly one of a run ID, --branch or --commit")
+	}
+
+	switch {
+	case len(positional) == 1:
+		id, err := strconv.ParseInt(positional[0], 10, 64)
+		if err != nil || id <= 0 {
+			return opts, fmt.Errorf("invalid run ID: %s", positional[0])
+		}
+		opts.RunID = id
+	case branch == "current":
+		current, err := repo.CurrentBranch()
+		if err != nil {
+			return opts, fmt.Errorf("failed to detect current branch: %w", err)
+		}
+		opts.Branch = current
+	case branch != "":
+		opts.Branch = branch
+	default:
+		sha, err := repo.ResolveCommit(commit)
+		if err != nil {
+			return opts, err
+		}
+		opts.Commit = sha
+	}
+	return opts, nil
+}
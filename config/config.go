@@ -0,0 +1,110 @@
+// Package config loads the lazyactions configuration file.
+//
+// The file is optional and lives at $XDG_CONFIG_HOME/lazyactions/config.yml
+// (see os.UserConfigDir for other platforms). A missing file is the same as
+// an empty one.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration.
+type Config struct {
+	// TokenCommand is a shell command that prints a GitHub token, e.g. to
+	// read it from a password manager. The host the token is for is passed
+	// in the LAZYACTIONS_HOST environment variable.
+	TokenCommand string `yaml:"token_command"`
+
+	// GitHubApp authenticates as a GitHub App installation instead of a
+	// user. It takes precedence over all other token sources when set.
+	GitHubApp *GitHubApp `yaml:"github_app"`
+
+	// Redaction adds rules for removing secrets from logs to the built-in ones.
+	Redaction Redaction `yaml:"redaction"`
+
+	// ProblemMatchers are paths of GitHub problem matcher JSON files, whose
+	// matchers recognize diagnostics in logs alongside the built-in ones.
+	ProblemMatchers []string `yaml:"problem_matchers"`
+
+	// AutoRefresh refreshes the runs of the selected workflow in the
+	// background, as often as the API budget allows.
+	AutoRefresh bool `yaml:"auto_refresh"`
+
+	// LogDiff sets the masks the Diff tab starts with.
+	LogDiff LogDiff `yaml:"log_diff"`
+
+	// HTTPCache configures the cache of API responses that makes polling
+	// cheap with conditional requests.
+	HTTPCache HTTPCache `yaml:"http_cache"`
+}
+
+// LogDiff sets the values masked before logs are compared. Masks are on
+// unless turned off.
+type LogDiff struct {
+	MaskDurations *bool `yaml:"mask_durations"` // Replace durations
+	MaskHashes    *bool `yaml:"mask_hashes"`    // Replace commit SHAs and digests
+}
+
+// HTTPCache configures the cache of API responses.
+type HTTPCache struct {
+	MemoryOnly bool `yaml:"memory_only"`  // Don't persist responses on disk
+	MaxSizeMB  int  `yaml:"max_size_mb"`  // Size cap of the disk cache
+	MaxAgeDays int  `yaml:"max_age_days"` // Drop responses unused for this long
+}
+
+// Redaction holds user rules for removing secrets from logs.
+type Redaction struct {
+	Patterns []string `yaml:"patterns"` // Regular expressions
+	Values   []string `yaml:"values"`   // Literal values
+	Entropy  bool     `yaml:"entropy"`  // Also redact long random-looking strings
+}
+
+// GitHubApp holds the credentials of a GitHub App installation.
+type GitHubApp struct {
+	AppID          int64  `yaml:"app_id"`
+	InstallationID int64  `yaml:"installation_id"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+}
+
+// DefaultPath returns the path of the configuration file.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazyactions", "config.yml"), nil
+}
+
+// Load reads the configuration file at path. It returns an empty
+// configuration if the file does not exist.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadDefault reads the configuration file at DefaultPath. It returns an
+// empty configuration if the user configuration directory is unknown.
+func LoadDefault() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return &Config{}, nil
+	}
+	return Load(path)
+}
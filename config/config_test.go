@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("missing file is empty config", func(t *testing.T) {
+		cfg, err := Load(filepath.Join(t.TempDir(), "config.yml"))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.TokenCommand != "" {
+			t.Errorf("TokenCommand = %q, want empty", cfg.TokenCommand)
+		}
+	})
+
+	t.Run("reads token command", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("token_command: pass show github/token\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.TokenCommand != "pass show github/token" {
+			t.Errorf("TokenCommand = %q, want %q", cfg.TokenCommand, "pass show github/token")
+		}
+	})
+
+	t.Run("reads GitHub App", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		data := "github_app:\n  app_id: 123\n  installation_id: 456\n  private_key_file: /keys/app.pem\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		want := GitHubApp{AppID: 123, InstallationID: 456, PrivateKeyFile: "/keys/app.pem"}
+		if cfg.GitHubApp == nil || *cfg.GitHubApp != want {
+			t.Errorf("GitHubApp = %+v, want %+v", cfg.GitHubApp, want)
+		}
+	})
+
+	t.Run("reads redaction rules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		data := "redaction:\n  patterns: ['corp-[0-9]+']\n  values: [hunter2]\n  entropy: true\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		r := cfg.Redaction
+		if len(r.Patterns) != 1 || r.Patterns[0] != "corp-[0-9]+" || len(r.Values) != 1 || r.Values[0] != "hunter2" || !r.Entropy {
+			t.Errorf("Redaction = %+v", r)
+		}
+	})
+
+	t.Run("reads problem matchers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		data := "problem_matchers:\n  - .github/matchers/lint.json\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(cfg.ProblemMatchers) != 1 || cfg.ProblemMatchers[0] != ".github/matchers/lint.json" {
+			t.Errorf("ProblemMatchers = %v", cfg.ProblemMatchers)
+		}
+	})
+
+	t.Run("reads auto refresh", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("auto_refresh: true\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if !cfg.AutoRefresh {
+			t.Error("AutoRefresh = false, want true")
+		}
+	})
+
+	t.Run("reads log diff masks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("log_diff:\n  mask_hashes: false\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.LogDiff.MaskDurations != nil {
+			t.Errorf("MaskDurations = %v, want unset", *cfg.LogDiff.MaskDurations)
+		}
+		if cfg.LogDiff.MaskHashes == nil || *cfg.LogDiff.MaskHashes {
+			t.Errorf("MaskHashes = %v, want false", cfg.LogDiff.MaskHashes)
+		}
+	})
+
+	t.Run("reads HTTP cache settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		data := "http_cache:\n  memory_only: true\n  max_size_mb: 64\n  max_age_days: 1\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		want := HTTPCache{MemoryOnly: true, MaxSizeMB: 64, MaxAgeDays: 1}
+		if cfg.HTTPCache != want {
+			t.Errorf("HTTPCache = %+v, want %+v", cfg.HTTPCache, want)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("token_command: [unclosed\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), "invalid config") {
+			t.Errorf("Load() error = %v, want invalid config error", err)
+		}
+	})
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error = %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join("lazyactions", "config.yml")) {
+		t.Errorf("DefaultPath() = %q, want lazyactions/config.yml", path)
+	}
+}
@@ -2,52 +2,162 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v68/github"
 )
 
 // realClient implements the Client interface using go-github
 type realClient struct {
-	client    *github.Client
-	owner     string
-	repoName  string
-	rateLimit int
+	client   *github.Client
+	owner    string
+	repoName string
+	logger   *slog.Logger
+	download *http.Client // Fetches log archives, which are served from another host
+
+	mu        sync.Mutex // Guards rateLimit, updated by concurrent requests
+	rateLimit RateLimit
+}
+
+// ClientOption configures a client created by NewClient
+type ClientOption func(*clientConfig)
+
+// clientConfig holds the settings applied by ClientOptions
+type clientConfig struct {
+	cache  HTTPCache
+	tokens TokenSource
+	logger *slog.Logger
+}
+
+// WithHTTPCache sets the cache used for conditional requests.
+// By default responses are cached in memory for the lifetime of the client.
+func WithHTTPCache(cache HTTPCache) ClientOption {
+	return func(c *clientConfig) {
+		c.cache = cache
+	}
+}
+
+// TokenSource provides tokens that may change during the life of a client,
+// such as GitHub App installation tokens, which expire after an hour.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// WithTokenSource authenticates every request with a token from src, so the
+// token can be refreshed without recreating the client. It takes precedence
+// over the static token passed to NewClient.
+func WithTokenSource(src TokenSource) ClientOption {
+	return func(c *clientConfig) {
+		c.tokens = src
+	}
 }
 
 // NewClient creates a new GitHub API client
-func NewClient(token, owner, repoName string) Client {
-	httpClient := &http.Client{}
-	if token != "" {
-		httpClient = &http.Client{
-			Transport: &tokenTransport{token: token},
-		}
+func NewClient(token, owner, repoName string, opts ...ClientOption) Client {
+	cfg := &clientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
+	if cfg.cache == nil {
+		cfg.cache = NewMemoryCache(DefaultHTTPCacheEntries)
+	}
+	if cfg.logger == nil {
+		cfg.logger = discardLogger
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	cache := &cacheTransport{cache: cfg.cache}
+	if token != "" || cfg.tokens != nil {
+		tokens := &tokenTransport{token: token, source: cfg.tokens}
+		transport = tokens
+		cache.identity = tokens.identity
+	}
+	cache.next = &loggingTransport{next: transport, logger: cfg.logger}
+	httpClient := &http.Client{Transport: cache}
 
 	return &realClient{
-		client:    github.NewClient(httpClient),
-		owner:     owner,
-		repoName:  repoName,
-		rateLimit: 5000, // Default rate limit
+		client:   github.NewClient(httpClient),
+		owner:    owner,
+		repoName: repoName,
+		logger:   cfg.logger,
+		// Log downloads go to a signed URL on another host, so they carry no
+		// token and are too large and short-lived to cache
+		download:  &http.Client{Transport: &loggingTransport{logger: cfg.logger}},
+		rateLimit: RateLimit{Limit: DefaultRateLimit, Remaining: DefaultRateLimit},
 	}
 }
 
 // tokenTransport adds authorization header to requests
 type tokenTransport struct {
-	token string
+	token  string
+	source TokenSource // Consulted on every request when set
+}
+
+// current returns the token to send
+func (t *tokenTransport) current(ctx context.Context) (string, error) {
+	if t.source == nil {
+		return t.token, nil
+	}
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+	return token, nil
+}
+
+// identity returns a hash of the current token, which identifies it in
+// cache keys without keeping the token itself
+func (t *tokenTransport) identity(ctx context.Context) (string, error) {
+	token, err := t.current(ctx)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8]), nil
 }
 
 func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", "Bearer "+t.token)
+	token, err := t.current(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
 	return http.DefaultTransport.RoundTrip(req)
 }
 
-// updateRateLimit updates the rate limit from the response.
+// log returns the client's logger
+func (c *realClient) log() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
+
+// updateRateLimit updates the rate limit from the response,
+// including the Retry-After of a secondary rate limit.
 func (c *realClient) updateRateLimit(resp *github.Response) {
-	if resp != nil {
-		c.rateLimit = resp.Rate.Remaining
+	if resp == nil || resp.Response == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resp.Rate.Limit > 0 {
+		c.rateLimit.Limit = resp.Rate.Limit
+		c.rateLimit.Remaining = resp.Rate.Remaining
+		c.rateLimit.Reset = resp.Rate.Reset.Time
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		now := time.Now()
+		c.rateLimit.RetryAt = now.Add(retryAfter(resp.Header, now))
 	}
 }
 
@@ -81,6 +191,9 @@ func (c *realClient) ListRuns(ctx context.Context, repo Repository, opts *ListRu
 		if opts.PerPage > 0 {
 			ghOpts.ListOptions.PerPage = opts.PerPage
 		}
+		if opts.Page > 0 {
+			ghOpts.ListOptions.Page = opts.Page
+		}
 		if opts.Branch != "" {
 			ghOpts.Branch = opts.Branch
 		}
@@ -90,6 +203,9 @@ func (c *realClient) ListRuns(ctx context.Context, repo Repository, opts *ListRu
 		if opts.Event != "" {
 			ghOpts.Event = opts.Event
 		}
+		if opts.HeadSHA != "" {
+			ghOpts.HeadSHA = opts.HeadSHA
+		}
 		if opts.WorkflowID > 0 {
 			runs, resp, err := c.client.Actions.ListWorkflowRunsByID(ctx, repo.Owner, repo.Name, opts.WorkflowID, ghOpts)
 			c.updateRateLimit(resp)
@@ -108,6 +224,17 @@ func (c *realClient) ListRuns(ctx context.Context, repo Repository, opts *ListRu
 	return convertRuns(runs.WorkflowRuns), nil
 }
 
+// GetRun gets a single workflow run by ID.
+func (c *realClient) GetRun(ctx context.Context, repo Repository, runID int64) (*Run, error) {
+	run, resp, err := c.client.Actions.GetWorkflowRunByID(ctx, repo.Owner, repo.Name, runID)
+	c.updateRateLimit(resp)
+	if err != nil {
+		return nil, WrapAPIError(err)
+	}
+	result := convertRun(run)
+	return &result, nil
+}
+
 // CancelRun cancels a workflow run.
 func (c *realClient) CancelRun(ctx context.Context, repo Repository, runID int64) error {
 	resp, err := c.client.Actions.CancelWorkflowRunByID(ctx, repo.Owner, repo.Name, runID)
@@ -152,9 +279,20 @@ func (c *realClient) TriggerWorkflow(ctx context.Context, repo Repository, workf
 	return nil
 }
 
-// ListJobs lists jobs for a workflow run.
+// ListJobs lists jobs of the latest attempt of a workflow run.
 func (c *realClient) ListJobs(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
+	return c.listJobs(ctx, repo, runID, "latest")
+}
+
+// ListJobsAllAttempts lists jobs of every attempt of a workflow run.
+func (c *realClient) ListJobsAllAttempts(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
+	return c.listJobs(ctx, repo, runID, "all")
+}
+
+// listJobs lists jobs for a workflow run using the given attempt filter ("latest" or "all").
+func (c *realClient) listJobs(ctx context.Context, repo Repository, runID int64, filter string) ([]Job, error) {
 	opts := &github.ListWorkflowJobsOptions{
+		Filter:      filter,
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
 	jobs, resp, err := c.client.Actions.ListWorkflowJobs(ctx, repo.Owner, repo.Name, runID, opts)
@@ -163,52 +301,84 @@ func (c *realClient) ListJobs(ctx context.Context, repo Repository, runID int64)
 		return nil, WrapAPIError(err)
 	}
 
-	result := make([]Job, 0, len(jobs.Jobs))
-	for _, j := range jobs.Jobs {
-		steps := make([]Step, 0, len(j.Steps))
-		for _, s := range j.Steps {
-			steps = append(steps, Step{
-				Name:       s.GetName(),
-				Status:     s.GetStatus(),
-				Conclusion: s.GetConclusion(),
-				Number:     int(s.GetNumber()),
-			})
-		}
-		result = append(result, Job{
-			ID:         j.GetID(),
-			Name:       j.GetName(),
-			Status:     j.GetStatus(),
-			Conclusion: j.GetConclusion(),
-			Steps:      steps,
-		})
+	return convertJobs(jobs.Jobs), nil
+}
+
+// ResolveRef returns the SHA of the commit a branch, tag or SHA points to.
+func (c *realClient) ResolveRef(ctx context.Context, repo Repository, ref string) (string, error) {
+	sha, resp, err := c.client.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Name, ref, "")
+	c.updateRateLimit(resp)
+	if err != nil {
+		return "", WrapAPIError(err)
 	}
-	return result, nil
+	return sha, nil
 }
 
-// GetJobLogs gets logs for a job.
-func (c *realClient) GetJobLogs(ctx context.Context, repo Repository, jobID int64) (string, error) {
+// GetJobLogs starts downloading the logs of a job. The logs are streamed
+// from the response, so the caller must close them.
+func (c *realClient) GetJobLogs(ctx context.Context, repo Repository, jobID int64) (io.ReadCloser, error) {
 	url, resp, err := c.client.Actions.GetWorkflowJobLogs(ctx, repo.Owner, repo.Name, jobID, 2)
 	c.updateRateLimit(resp)
 	if err != nil {
-		return "", WrapAPIError(err)
+		return nil, WrapAPIError(err)
 	}
 
-	logResp, err := http.Get(url.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download logs: %w", err)
+	}
+	download := c.download
+	if download == nil {
+		download = http.DefaultClient
+	}
+	logResp, err := download.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to download logs: %w", err)
+		return nil, fmt.Errorf("failed to download logs: %w", err)
+	}
+	if logResp.StatusCode != http.StatusOK {
+		_ = logResp.Body.Close()
+		return nil, fmt.Errorf("failed to download logs: %s", logResp.Status)
+	}
+
+	return logResp.Body, nil
+}
+
+// GetJobAnnotations gets the annotations of a job's check run.
+// The check run of a job shares the job's ID.
+func (c *realClient) GetJobAnnotations(ctx context.Context, repo Repository, jobID int64) ([]Annotation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	annotations, resp, err := c.client.Checks.ListCheckRunAnnotations(ctx, repo.Owner, repo.Name, jobID, opts)
+	c.updateRateLimit(resp)
+	if err != nil {
+		return nil, WrapAPIError(err)
 	}
-	defer func() { _ = logResp.Body.Close() }()
 
-	body, err := io.ReadAll(logResp.Body)
+	return convertAnnotations(annotations), nil
+}
+
+// GetJobSummary gets the output of a job's check run.
+// The check run of a job shares the job's ID.
+func (c *realClient) GetJobSummary(ctx context.Context, repo Repository, jobID int64) (JobSummary, error) {
+	checkRun, resp, err := c.client.Checks.GetCheckRun(ctx, repo.Owner, repo.Name, jobID)
+	c.updateRateLimit(resp)
 	if err != nil {
-		return "", fmt.Errorf("failed to read logs: %w", err)
+		return JobSummary{}, WrapAPIError(err)
 	}
 
-	return string(body), nil
+	return convertCheckRunOutput(checkRun.GetOutput()), nil
 }
 
 // RateLimitRemaining returns the remaining rate limit.
 func (c *realClient) RateLimitRemaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit.Remaining
+}
+
+// RateLimit returns the last known rate limit state.
+func (c *realClient) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.rateLimit
 }
 
@@ -216,17 +386,91 @@ func (c *realClient) RateLimitRemaining() int {
 func convertRuns(ghRuns []*github.WorkflowRun) []Run {
 	result := make([]Run, 0, len(ghRuns))
 	for _, r := range ghRuns {
-		result = append(result, Run{
-			ID:         r.GetID(),
-			RunNumber:  r.GetRunNumber(),
-			Name:       r.GetName(),
-			Status:     r.GetStatus(),
-			Conclusion: r.GetConclusion(),
-			Branch:     r.GetHeadBranch(),
-			Event:      r.GetEvent(),
-			Actor:      r.GetActor().GetLogin(),
-			URL:        r.GetHTMLURL(),
-			CreatedAt:  r.GetCreatedAt().Time,
+		result = append(result, convertRun(r))
+	}
+	return result
+}
+
+// convertRun converts a single GitHub API run to our Run type.
+func convertRun(r *github.WorkflowRun) Run {
+	prs := make([]PullRequest, 0, len(r.PullRequests))
+	for _, pr := range r.PullRequests {
+		prs = append(prs, PullRequest{
+			Number:     pr.GetNumber(),
+			HeadBranch: pr.GetHead().GetRef(),
+			BaseBranch: pr.GetBase().GetRef(),
+		})
+	}
+	return Run{
+		ID:           r.GetID(),
+		RunNumber:    r.GetRunNumber(),
+		RunAttempt:   r.GetRunAttempt(),
+		Name:         r.GetName(),
+		DisplayTitle: r.GetDisplayTitle(),
+		Status:       r.GetStatus(),
+		Conclusion:   r.GetConclusion(),
+		Branch:       r.GetHeadBranch(),
+		HeadSHA:      r.GetHeadSHA(),
+		Event:        r.GetEvent(),
+		Actor:        r.GetActor().GetLogin(),
+		URL:          r.GetHTMLURL(),
+		CreatedAt:    r.GetCreatedAt().Time,
+		UpdatedAt:    r.GetUpdatedAt().Time,
+		RunStartedAt: r.GetRunStartedAt().Time,
+		PullRequests: prs,
+	}
+}
+
+// convertJobs converts GitHub API jobs to our Job type.
+func convertJobs(ghJobs []*github.WorkflowJob) []Job {
+	result := make([]Job, 0, len(ghJobs))
+	for _, j := range ghJobs {
+		steps := make([]Step, 0, len(j.Steps))
+		for _, s := range j.Steps {
+			steps = append(steps, Step{
+				Name:        s.GetName(),
+				Status:      s.GetStatus(),
+				Conclusion:  s.GetConclusion(),
+				Number:      int(s.GetNumber()),
+				StartedAt:   s.GetStartedAt().Time,
+				CompletedAt: s.GetCompletedAt().Time,
+			})
+		}
+		result = append(result, Job{
+			ID:          j.GetID(),
+			Name:        j.GetName(),
+			Status:      j.GetStatus(),
+			Conclusion:  j.GetConclusion(),
+			Steps:       steps,
+			RunAttempt:  int(j.GetRunAttempt()),
+			CreatedAt:   j.GetCreatedAt().Time,
+			StartedAt:   j.GetStartedAt().Time,
+			CompletedAt: j.GetCompletedAt().Time,
+		})
+	}
+	return result
+}
+
+// convertCheckRunOutput converts GitHub API check run output to our JobSummary type.
+func convertCheckRunOutput(output *github.CheckRunOutput) JobSummary {
+	return JobSummary{
+		Title:   output.GetTitle(),
+		Summary: output.GetSummary(),
+		Text:    output.GetText(),
+	}
+}
+
+// convertAnnotations converts GitHub API check run annotations to our Annotation type.
+func convertAnnotations(ghAnnotations []*github.CheckRunAnnotation) []Annotation {
+	result := make([]Annotation, 0, len(ghAnnotations))
+	for _, a := range ghAnnotations {
+		result = append(result, Annotation{
+			Path:      a.GetPath(),
+			StartLine: a.GetStartLine(),
+			EndLine:   a.GetEndLine(),
+			Level:     a.GetAnnotationLevel(),
+			Title:     a.GetTitle(),
+			Message:   a.GetMessage(),
 		})
 	}
 	return result
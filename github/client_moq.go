@@ -5,6 +5,7 @@ package github
 
 import (
 	"context"
+	"io"
 	"sync"
 )
 
@@ -21,18 +22,36 @@ var _ Client = &MockClient{}
 //			CancelRunFunc: func(ctx context.Context, repo Repository, runID int64) error {
 //				panic("mock out the CancelRun method")
 //			},
-//			GetJobLogsFunc: func(ctx context.Context, repo Repository, jobID int64) (string, error) {
+//			CheckPermissionsFunc: func(ctx context.Context, repo Repository) (Permissions, error) {
+//				panic("mock out the CheckPermissions method")
+//			},
+//			GetJobAnnotationsFunc: func(ctx context.Context, repo Repository, jobID int64) ([]Annotation, error) {
+//				panic("mock out the GetJobAnnotations method")
+//			},
+//			GetJobLogsFunc: func(ctx context.Context, repo Repository, jobID int64) (io.ReadCloser, error) {
 //				panic("mock out the GetJobLogs method")
 //			},
+//			GetJobSummaryFunc: func(ctx context.Context, repo Repository, jobID int64) (JobSummary, error) {
+//				panic("mock out the GetJobSummary method")
+//			},
+//			GetRunFunc: func(ctx context.Context, repo Repository, runID int64) (*Run, error) {
+//				panic("mock out the GetRun method")
+//			},
 //			ListJobsFunc: func(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
 //				panic("mock out the ListJobs method")
 //			},
+//			ListJobsAllAttemptsFunc: func(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
+//				panic("mock out the ListJobsAllAttempts method")
+//			},
 //			ListRunsFunc: func(ctx context.Context, repo Repository, opts *ListRunsOpts) ([]Run, error) {
 //				panic("mock out the ListRuns method")
 //			},
 //			ListWorkflowsFunc: func(ctx context.Context, repo Repository) ([]Workflow, error) {
 //				panic("mock out the ListWorkflows method")
 //			},
+//			RateLimitFunc: func() RateLimit {
+//				panic("mock out the RateLimit method")
+//			},
 //			RateLimitRemainingFunc: func() int {
 //				panic("mock out the RateLimitRemaining method")
 //			},
@@ -42,6 +61,9 @@ var _ Client = &MockClient{}
 //			RerunWorkflowFunc: func(ctx context.Context, repo Repository, runID int64) error {
 //				panic("mock out the RerunWorkflow method")
 //			},
+//			ResolveRefFunc: func(ctx context.Context, repo Repository, ref string) (string, error) {
+//				panic("mock out the ResolveRef method")
+//			},
 //			TriggerWorkflowFunc: func(ctx context.Context, repo Repository, workflowFile string, ref string, inputs map[string]interface{}) error {
 //				panic("mock out the TriggerWorkflow method")
 //			},
@@ -55,18 +77,36 @@ type MockClient struct {
 	// CancelRunFunc mocks the CancelRun method.
 	CancelRunFunc func(ctx context.Context, repo Repository, runID int64) error
 
+	// CheckPermissionsFunc mocks the CheckPermissions method.
+	CheckPermissionsFunc func(ctx context.Context, repo Repository) (Permissions, error)
+
+	// GetJobAnnotationsFunc mocks the GetJobAnnotations method.
+	GetJobAnnotationsFunc func(ctx context.Context, repo Repository, jobID int64) ([]Annotation, error)
+
 	// GetJobLogsFunc mocks the GetJobLogs method.
-	GetJobLogsFunc func(ctx context.Context, repo Repository, jobID int64) (string, error)
+	GetJobLogsFunc func(ctx context.Context, repo Repository, jobID int64) (io.ReadCloser, error)
+
+	// GetJobSummaryFunc mocks the GetJobSummary method.
+	GetJobSummaryFunc func(ctx context.Context, repo Repository, jobID int64) (JobSummary, error)
+
+	// GetRunFunc mocks the GetRun method.
+	GetRunFunc func(ctx context.Context, repo Repository, runID int64) (*Run, error)
 
 	// ListJobsFunc mocks the ListJobs method.
 	ListJobsFunc func(ctx context.Context, repo Repository, runID int64) ([]Job, error)
 
+	// ListJobsAllAttemptsFunc mocks the ListJobsAllAttempts method.
+	ListJobsAllAttemptsFunc func(ctx context.Context, repo Repository, runID int64) ([]Job, error)
+
 	// ListRunsFunc mocks the ListRuns method.
 	ListRunsFunc func(ctx context.Context, repo Repository, opts *ListRunsOpts) ([]Run, error)
 
 	// ListWorkflowsFunc mocks the ListWorkflows method.
 	ListWorkflowsFunc func(ctx context.Context, repo Repository) ([]Workflow, error)
 
+	// RateLimitFunc mocks the RateLimit method.
+	RateLimitFunc func() RateLimit
+
 	// RateLimitRemainingFunc mocks the RateLimitRemaining method.
 	RateLimitRemainingFunc func() int
 
@@ -76,6 +116,9 @@ type MockClient struct {
 	// RerunWorkflowFunc mocks the RerunWorkflow method.
 	RerunWorkflowFunc func(ctx context.Context, repo Repository, runID int64) error
 
+	// ResolveRefFunc mocks the ResolveRef method.
+	ResolveRefFunc func(ctx context.Context, repo Repository, ref string) (string, error)
+
 	// TriggerWorkflowFunc mocks the TriggerWorkflow method.
 	TriggerWorkflowFunc func(ctx context.Context, repo Repository, workflowFile string, ref string, inputs map[string]interface{}) error
 
@@ -90,6 +133,22 @@ type MockClient struct {
 			// RunID is the runID argument value.
 			RunID int64
 		}
+		// CheckPermissions holds details about calls to the CheckPermissions method.
+		CheckPermissions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+		}
+		// GetJobAnnotations holds details about calls to the GetJobAnnotations method.
+		GetJobAnnotations []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+			// JobID is the jobID argument value.
+			JobID int64
+		}
 		// GetJobLogs holds details about calls to the GetJobLogs method.
 		GetJobLogs []struct {
 			// Ctx is the ctx argument value.
@@ -99,6 +158,24 @@ type MockClient struct {
 			// JobID is the jobID argument value.
 			JobID int64
 		}
+		// GetJobSummary holds details about calls to the GetJobSummary method.
+		GetJobSummary []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+			// JobID is the jobID argument value.
+			JobID int64
+		}
+		// GetRun holds details about calls to the GetRun method.
+		GetRun []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+			// RunID is the runID argument value.
+			RunID int64
+		}
 		// ListJobs holds details about calls to the ListJobs method.
 		ListJobs []struct {
 			// Ctx is the ctx argument value.
@@ -108,6 +185,15 @@ type MockClient struct {
 			// RunID is the runID argument value.
 			RunID int64
 		}
+		// ListJobsAllAttempts holds details about calls to the ListJobsAllAttempts method.
+		ListJobsAllAttempts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+			// RunID is the runID argument value.
+			RunID int64
+		}
 		// ListRuns holds details about calls to the ListRuns method.
 		ListRuns []struct {
 			// Ctx is the ctx argument value.
@@ -124,6 +210,9 @@ type MockClient struct {
 			// Repo is the repo argument value.
 			Repo Repository
 		}
+		// RateLimit holds details about calls to the RateLimit method.
+		RateLimit []struct {
+		}
 		// RateLimitRemaining holds details about calls to the RateLimitRemaining method.
 		RateLimitRemaining []struct {
 		}
@@ -145,6 +234,15 @@ type MockClient struct {
 			// RunID is the runID argument value.
 			RunID int64
 		}
+		// ResolveRef holds details about calls to the ResolveRef method.
+		ResolveRef []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Repo is the repo argument value.
+			Repo Repository
+			// Ref is the ref argument value.
+			Ref string
+		}
 		// TriggerWorkflow holds details about calls to the TriggerWorkflow method.
 		TriggerWorkflow []struct {
 			// Ctx is the ctx argument value.
@@ -159,15 +257,22 @@ type MockClient struct {
 			Inputs map[string]interface{}
 		}
 	}
-	lockCancelRun          sync.RWMutex
-	lockGetJobLogs         sync.RWMutex
-	lockListJobs           sync.RWMutex
-	lockListRuns           sync.RWMutex
-	lockListWorkflows      sync.RWMutex
-	lockRateLimitRemaining sync.RWMutex
-	lockRerunFailedJobs    sync.RWMutex
-	lockRerunWorkflow      sync.RWMutex
-	lockTriggerWorkflow    sync.RWMutex
+	lockCancelRun           sync.RWMutex
+	lockCheckPermissions    sync.RWMutex
+	lockGetJobAnnotations   sync.RWMutex
+	lockGetJobLogs          sync.RWMutex
+	lockGetJobSummary       sync.RWMutex
+	lockGetRun              sync.RWMutex
+	lockListJobs            sync.RWMutex
+	lockListJobsAllAttempts sync.RWMutex
+	lockListRuns            sync.RWMutex
+	lockListWorkflows       sync.RWMutex
+	lockRateLimit           sync.RWMutex
+	lockRateLimitRemaining  sync.RWMutex
+	lockRerunFailedJobs     sync.RWMutex
+	lockRerunWorkflow       sync.RWMutex
+	lockResolveRef          sync.RWMutex
+	lockTriggerWorkflow     sync.RWMutex
 }
 
 // CancelRun calls CancelRunFunc.
@@ -210,8 +315,84 @@ func (mock *MockClient) CancelRunCalls() []struct {
 	return calls
 }
 
+// CheckPermissions calls CheckPermissionsFunc.
+func (mock *MockClient) CheckPermissions(ctx context.Context, repo Repository) (Permissions, error) {
+	if mock.CheckPermissionsFunc == nil {
+		panic("MockClient.CheckPermissionsFunc: method is nil but Client.CheckPermissions was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Repo Repository
+	}{
+		Ctx:  ctx,
+		Repo: repo,
+	}
+	mock.lockCheckPermissions.Lock()
+	mock.calls.CheckPermissions = append(mock.calls.CheckPermissions, callInfo)
+	mock.lockCheckPermissions.Unlock()
+	return mock.CheckPermissionsFunc(ctx, repo)
+}
+
+// CheckPermissionsCalls gets all the calls that were made to CheckPermissions.
+// Check the length with:
+//
+//	len(mockedClient.CheckPermissionsCalls())
+func (mock *MockClient) CheckPermissionsCalls() []struct {
+	Ctx  context.Context
+	Repo Repository
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Repo Repository
+	}
+	mock.lockCheckPermissions.RLock()
+	calls = mock.calls.CheckPermissions
+	mock.lockCheckPermissions.RUnlock()
+	return calls
+}
+
+// GetJobAnnotations calls GetJobAnnotationsFunc.
+func (mock *MockClient) GetJobAnnotations(ctx context.Context, repo Repository, jobID int64) ([]Annotation, error) {
+	if mock.GetJobAnnotationsFunc == nil {
+		panic("MockClient.GetJobAnnotationsFunc: method is nil but Client.GetJobAnnotations was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Repo  Repository
+		JobID int64
+	}{
+		Ctx:   ctx,
+		Repo:  repo,
+		JobID: jobID,
+	}
+	mock.lockGetJobAnnotations.Lock()
+	mock.calls.GetJobAnnotations = append(mock.calls.GetJobAnnotations, callInfo)
+	mock.lockGetJobAnnotations.Unlock()
+	return mock.GetJobAnnotationsFunc(ctx, repo, jobID)
+}
+
+// GetJobAnnotationsCalls gets all the calls that were made to GetJobAnnotations.
+// Check the length with:
+//
+//	len(mockedClient.GetJobAnnotationsCalls())
+func (mock *MockClient) GetJobAnnotationsCalls() []struct {
+	Ctx   context.Context
+	Repo  Repository
+	JobID int64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Repo  Repository
+		JobID int64
+	}
+	mock.lockGetJobAnnotations.RLock()
+	calls = mock.calls.GetJobAnnotations
+	mock.lockGetJobAnnotations.RUnlock()
+	return calls
+}
+
 // GetJobLogs calls GetJobLogsFunc.
-func (mock *MockClient) GetJobLogs(ctx context.Context, repo Repository, jobID int64) (string, error) {
+func (mock *MockClient) GetJobLogs(ctx context.Context, repo Repository, jobID int64) (io.ReadCloser, error) {
 	if mock.GetJobLogsFunc == nil {
 		panic("MockClient.GetJobLogsFunc: method is nil but Client.GetJobLogs was just called")
 	}
@@ -250,6 +431,86 @@ func (mock *MockClient) GetJobLogsCalls() []struct {
 	return calls
 }
 
+// GetJobSummary calls GetJobSummaryFunc.
+func (mock *MockClient) GetJobSummary(ctx context.Context, repo Repository, jobID int64) (JobSummary, error) {
+	if mock.GetJobSummaryFunc == nil {
+		panic("MockClient.GetJobSummaryFunc: method is nil but Client.GetJobSummary was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Repo  Repository
+		JobID int64
+	}{
+		Ctx:   ctx,
+		Repo:  repo,
+		JobID: jobID,
+	}
+	mock.lockGetJobSummary.Lock()
+	mock.calls.GetJobSummary = append(mock.calls.GetJobSummary, callInfo)
+	mock.lockGetJobSummary.Unlock()
+	return mock.GetJobSummaryFunc(ctx, repo, jobID)
+}
+
+// GetJobSummaryCalls gets all the calls that were made to GetJobSummary.
+// Check the length with:
+//
+//	len(mockedClient.GetJobSummaryCalls())
+func (mock *MockClient) GetJobSummaryCalls() []struct {
+	Ctx   context.Context
+	Repo  Repository
+	JobID int64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Repo  Repository
+		JobID int64
+	}
+	mock.lockGetJobSummary.RLock()
+	calls = mock.calls.GetJobSummary
+	mock.lockGetJobSummary.RUnlock()
+	return calls
+}
+
+// GetRun calls GetRunFunc.
+func (mock *MockClient) GetRun(ctx context.Context, repo Repository, runID int64) (*Run, error) {
+	if mock.GetRunFunc == nil {
+		panic("MockClient.GetRunFunc: method is nil but Client.GetRun was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Repo  Repository
+		RunID int64
+	}{
+		Ctx:   ctx,
+		Repo:  repo,
+		RunID: runID,
+	}
+	mock.lockGetRun.Lock()
+	mock.calls.GetRun = append(mock.calls.GetRun, callInfo)
+	mock.lockGetRun.Unlock()
+	return mock.GetRunFunc(ctx, repo, runID)
+}
+
+// GetRunCalls gets all the calls that were made to GetRun.
+// Check the length with:
+//
+//	len(mockedClient.GetRunCalls())
+func (mock *MockClient) GetRunCalls() []struct {
+	Ctx   context.Context
+	Repo  Repository
+	RunID int64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Repo  Repository
+		RunID int64
+	}
+	mock.lockGetRun.RLock()
+	calls = mock.calls.GetRun
+	mock.lockGetRun.RUnlock()
+	return calls
+}
+
 // ListJobs calls ListJobsFunc.
 func (mock *MockClient) ListJobs(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
 	if mock.ListJobsFunc == nil {
@@ -290,6 +551,46 @@ func (mock *MockClient) ListJobsCalls() []struct {
 	return calls
 }
 
+// ListJobsAllAttempts calls ListJobsAllAttemptsFunc.
+func (mock *MockClient) ListJobsAllAttempts(ctx context.Context, repo Repository, runID int64) ([]Job, error) {
+	if mock.ListJobsAllAttemptsFunc == nil {
+		panic("MockClient.ListJobsAllAttemptsFunc: method is nil but Client.ListJobsAllAttempts was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Repo  Repository
+		RunID int64
+	}{
+		Ctx:   ctx,
+		Repo:  repo,
+		RunID: runID,
+	}
+	mock.lockListJobsAllAttempts.Lock()
+	mock.calls.ListJobsAllAttempts = append(mock.calls.ListJobsAllAttempts, callInfo)
+	mock.lockListJobsAllAttempts.Unlock()
+	return mock.ListJobsAllAttemptsFunc(ctx, repo, runID)
+}
+
+// ListJobsAllAttemptsCalls gets all the calls that were made to ListJobsAllAttempts.
+// Check the length with:
+//
+//	len(mockedClient.ListJobsAllAttemptsCalls())
+func (mock *MockClient) ListJobsAllAttemptsCalls() []struct {
+	Ctx   context.Context
+	Repo  Repository
+	RunID int64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Repo  Repository
+		RunID int64
+	}
+	mock.lockListJobsAllAttempts.RLock()
+	calls = mock.calls.ListJobsAllAttempts
+	mock.lockListJobsAllAttempts.RUnlock()
+	return calls
+}
+
 // ListRuns calls ListRunsFunc.
 func (mock *MockClient) ListRuns(ctx context.Context, repo Repository, opts *ListRunsOpts) ([]Run, error) {
 	if mock.ListRunsFunc == nil {
@@ -366,6 +667,33 @@ func (mock *MockClient) ListWorkflowsCalls() []struct {
 	return calls
 }
 
+// RateLimit calls RateLimitFunc.
+func (mock *MockClient) RateLimit() RateLimit {
+	if mock.RateLimitFunc == nil {
+		panic("MockClient.RateLimitFunc: method is nil but Client.RateLimit was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRateLimit.Lock()
+	mock.calls.RateLimit = append(mock.calls.RateLimit, callInfo)
+	mock.lockRateLimit.Unlock()
+	return mock.RateLimitFunc()
+}
+
+// RateLimitCalls gets all the calls that were made to RateLimit.
+// Check the length with:
+//
+//	len(mockedClient.RateLimitCalls())
+func (mock *MockClient) RateLimitCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRateLimit.RLock()
+	calls = mock.calls.RateLimit
+	mock.lockRateLimit.RUnlock()
+	return calls
+}
+
 // RateLimitRemaining calls RateLimitRemainingFunc.
 func (mock *MockClient) RateLimitRemaining() int {
 	if mock.RateLimitRemainingFunc == nil {
@@ -473,6 +801,46 @@ func (mock *MockClient) RerunWorkflowCalls() []struct {
 	return calls
 }
 
+// ResolveRef calls ResolveRefFunc.
+func (mock *MockClient) ResolveRef(ctx context.Context, repo Repository, ref string) (string, error) {
+	if mock.ResolveRefFunc == nil {
+		panic("MockClient.ResolveRefFunc: method is nil but Client.ResolveRef was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Repo Repository
+		Ref  string
+	}{
+		Ctx:  ctx,
+		Repo: repo,
+		Ref:  ref,
+	}
+	mock.lockResolveRef.Lock()
+	mock.calls.ResolveRef = append(mock.calls.ResolveRef, callInfo)
+	mock.lockResolveRef.Unlock()
+	return mock.ResolveRefFunc(ctx, repo, ref)
+}
+
+// ResolveRefCalls gets all the calls that were made to ResolveRef.
+// Check the length with:
+//
+//	len(mockedClient.ResolveRefCalls())
+func (mock *MockClient) ResolveRefCalls() []struct {
+	Ctx  context.Context
+	Repo Repository
+	Ref  string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Repo Repository
+		Ref  string
+	}
+	mock.lockResolveRef.RLock()
+	calls = mock.calls.ResolveRef
+	mock.lockResolveRef.RUnlock()
+	return calls
+}
+
 // TriggerWorkflow calls TriggerWorkflowFunc.
 func (mock *MockClient) TriggerWorkflow(ctx context.Context, repo Repository, workflowFile string, ref string, inputs map[string]interface{}) error {
 	if mock.TriggerWorkflowFunc == nil {
@@ -1,6 +1,14 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -126,6 +134,47 @@ func TestConvertRuns(t *testing.T) {
 	}
 }
 
+func TestConvertRun_ExtendedFields(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ghRun := &github.WorkflowRun{
+		ID:           github.Ptr(int64(1)),
+		RunAttempt:   github.Ptr(2),
+		DisplayTitle: github.Ptr("Add watch command"),
+		HeadSHA:      github.Ptr("0123456789abcdef"),
+		CreatedAt:    &github.Timestamp{Time: created},
+		UpdatedAt:    &github.Timestamp{Time: created.Add(3 * time.Minute)},
+		RunStartedAt: &github.Timestamp{Time: created.Add(time.Minute)},
+		PullRequests: []*github.PullRequest{
+			{
+				Number: github.Ptr(42),
+				Head:   &github.PullRequestBranch{Ref: github.Ptr("feature")},
+				Base:   &github.PullRequestBranch{Ref: github.Ptr("main")},
+			},
+		},
+	}
+
+	r := convertRun(ghRun)
+
+	if r.RunAttempt != 2 {
+		t.Errorf("RunAttempt = %d, want 2", r.RunAttempt)
+	}
+	if r.DisplayTitle != "Add watch command" {
+		t.Errorf("DisplayTitle = %q, want %q", r.DisplayTitle, "Add watch command")
+	}
+	if r.HeadSHA != "0123456789abcdef" {
+		t.Errorf("HeadSHA = %q, want %q", r.HeadSHA, "0123456789abcdef")
+	}
+	if !r.UpdatedAt.Equal(created.Add(3*time.Minute)) || !r.RunStartedAt.Equal(created.Add(time.Minute)) {
+		t.Errorf("UpdatedAt/RunStartedAt = %v/%v, want %v/%v", r.UpdatedAt, r.RunStartedAt, created.Add(3*time.Minute), created.Add(time.Minute))
+	}
+	if len(r.PullRequests) != 1 {
+		t.Fatalf("PullRequests has %d entries, want 1", len(r.PullRequests))
+	}
+	if pr := r.PullRequests[0]; pr.Number != 42 || pr.HeadBranch != "feature" || pr.BaseBranch != "main" {
+		t.Errorf("PullRequests[0] = %+v, want #42 feature -> main", pr)
+	}
+}
+
 func TestConvertRuns_EmptyInput(t *testing.T) {
 	runs := convertRuns(nil)
 	if len(runs) != 0 {
@@ -137,3 +186,188 @@ func TestConvertRuns_EmptyInput(t *testing.T) {
 		t.Errorf("convertRuns([]) returned %d runs, want 0", len(runs))
 	}
 }
+
+func TestConvertJobs(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	started := created.Add(20 * time.Second)
+	completed := created.Add(3 * time.Minute)
+
+	ghJobs := []*github.WorkflowJob{
+		{
+			ID:          github.Ptr(int64(42)),
+			Name:        github.Ptr("build"),
+			Status:      github.Ptr("completed"),
+			Conclusion:  github.Ptr("success"),
+			CreatedAt:   &github.Timestamp{Time: created},
+			StartedAt:   &github.Timestamp{Time: started},
+			CompletedAt: &github.Timestamp{Time: completed},
+			RunAttempt:  github.Ptr(int64(2)),
+			Steps: []*github.TaskStep{
+				{
+					Name:        github.Ptr("Checkout"),
+					Status:      github.Ptr("completed"),
+					Conclusion:  github.Ptr("success"),
+					Number:      github.Ptr(int64(1)),
+					StartedAt:   &github.Timestamp{Time: started},
+					CompletedAt: &github.Timestamp{Time: started.Add(5 * time.Second)},
+				},
+			},
+		},
+	}
+
+	jobs := convertJobs(ghJobs)
+
+	if len(jobs) != 1 {
+		t.Fatalf("convertJobs() returned %d jobs, want 1", len(jobs))
+	}
+	j := jobs[0]
+	if j.RunAttempt != 2 {
+		t.Errorf("RunAttempt = %d, want 2", j.RunAttempt)
+	}
+	if j.ID != 42 || j.Name != "build" {
+		t.Errorf("Job = %+v, want ID 42 named build", j)
+	}
+	if !j.CreatedAt.Equal(created) || !j.StartedAt.Equal(started) || !j.CompletedAt.Equal(completed) {
+		t.Errorf("Job timestamps = %v/%v/%v, want %v/%v/%v", j.CreatedAt, j.StartedAt, j.CompletedAt, created, started, completed)
+	}
+	if len(j.Steps) != 1 {
+		t.Fatalf("Job.Steps has %d steps, want 1", len(j.Steps))
+	}
+	if got := j.Steps[0].Duration(); got != 5*time.Second {
+		t.Errorf("Step.Duration() = %v, want 5s", got)
+	}
+}
+
+func TestConvertAnnotations(t *testing.T) {
+	ghAnnotations := []*github.CheckRunAnnotation{
+		{
+			Path:            github.Ptr("pkg/main.go"),
+			StartLine:       github.Ptr(12),
+			EndLine:         github.Ptr(14),
+			AnnotationLevel: github.Ptr("failure"),
+			Title:           github.Ptr("go vet"),
+			Message:         github.Ptr("unreachable code"),
+		},
+	}
+
+	annotations := convertAnnotations(ghAnnotations)
+
+	if len(annotations) != 1 {
+		t.Fatalf("convertAnnotations() returned %d annotations, want 1", len(annotations))
+	}
+	a := annotations[0]
+	if a.Path != "pkg/main.go" || a.StartLine != 12 || a.EndLine != 14 {
+		t.Errorf("location = %s:%d-%d, want pkg/main.go:12-14", a.Path, a.StartLine, a.EndLine)
+	}
+	if a.Level != "failure" || a.Title != "go vet" || a.Message != "unreachable code" {
+		t.Errorf("unexpected annotation: %+v", a)
+	}
+}
+
+func TestConvertCheckRunOutput(t *testing.T) {
+	summary := convertCheckRunOutput(&github.CheckRunOutput{
+		Title:   github.Ptr("Coverage"),
+		Summary: github.Ptr("## Coverage\n| pkg | % |"),
+		Text:    github.Ptr("details"),
+	})
+	if summary.Title != "Coverage" || summary.Markdown() != "## Coverage\n| pkg | % |\n\ndetails" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	if empty := convertCheckRunOutput(nil); empty != (JobSummary{}) {
+		t.Errorf("convertCheckRunOutput(nil) = %+v, want empty", empty)
+	}
+}
+
+// countingTokenSource returns a new token on every call
+type countingTokenSource struct{ calls int }
+
+func (s *countingTokenSource) Token(context.Context) (string, error) {
+	s.calls++
+	return "token-" + strconv.Itoa(s.calls), nil
+}
+
+func TestTokenTransport_UsesTokenSource(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	src := &countingTokenSource{}
+	client := &http.Client{Transport: &tokenTransport{token: "static", source: src}}
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	want := []string{"Bearer token-1", "Bearer token-2"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorization headers = %v, want %v", got, want)
+	}
+}
+
+// newLogsServer redirects job log requests to a download URL, as GitHub does,
+// and serves the logs there
+func newLogsServer(t *testing.T, download http.HandlerFunc, opts ...ClientOption) *realClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/repos/owner/repo/actions/jobs/1/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/download?sig=signed", http.StatusFound)
+	})
+	mux.HandleFunc("/download", download)
+
+	client := NewClient("ghp_secret", "owner", "repo", opts...).(*realClient)
+	client.client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client
+}
+
+func TestGetJobLogs(t *testing.T) {
+	client := newLogsServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("download sent Authorization %q, want none", auth)
+		}
+		_, _ = w.Write([]byte("log output"))
+	})
+
+	logs, err := client.GetJobLogs(context.Background(), Repository{Owner: "owner", Name: "repo"}, 1)
+	if err != nil {
+		t.Fatalf("GetJobLogs() error = %v", err)
+	}
+	defer func() { _ = logs.Close() }()
+	body, err := io.ReadAll(logs)
+	if err != nil {
+		t.Fatalf("reading logs: %v", err)
+	}
+	if string(body) != "log output" {
+		t.Errorf("GetJobLogs() = %q, want %q", body, "log output")
+	}
+}
+
+func TestGetJobLogs_DownloadFails(t *testing.T) {
+	client := newLogsServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "expired", http.StatusForbidden)
+	})
+
+	if _, err := client.GetJobLogs(context.Background(), Repository{Owner: "owner", Name: "repo"}, 1); err == nil {
+		t.Error("GetJobLogs() error = nil, want download error")
+	}
+}
+
+func TestGetJobLogs_CancelledDownload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := newLogsServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+
+	_, err := client.GetJobLogs(ctx, Repository{Owner: "owner", Name: "repo"}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetJobLogs() error = %v, want context.Canceled", err)
+	}
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"time"
 
 	ghlib "github.com/google/go-github/v68/github"
@@ -45,6 +46,30 @@ func WrapAPIError(err error) *AppError {
 		return nil
 	}
 
+	// Primary rate limit exhausted: retry once the window resets
+	var rateErr *ghlib.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &AppError{
+			Type:       ErrTypeRateLimit,
+			Message:    "Rate limit exceeded",
+			Cause:      err,
+			Retryable:  true,
+			RetryAfter: max(time.Until(rateErr.Rate.Reset.Time), 0),
+		}
+	}
+
+	// Secondary rate limit: retry after the server-provided delay, if any
+	var abuseErr *ghlib.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return &AppError{
+			Type:       ErrTypeRateLimit,
+			Message:    "Secondary rate limit exceeded",
+			Cause:      err,
+			Retryable:  true,
+			RetryAfter: abuseErr.GetRetryAfter(),
+		}
+	}
+
 	var ghErr *ghlib.ErrorResponse
 	if errors.As(err, &ghErr) {
 		switch ghErr.Response.StatusCode {
@@ -56,12 +81,13 @@ func WrapAPIError(err error) *AppError {
 				Retryable: false,
 			}
 		case 403:
-			if ghErr.Response.Header.Get("X-RateLimit-Remaining") == "0" {
+			if ghErr.Response.Header.Get("X-RateLimit-Remaining") == "0" || ghErr.Response.Header.Get("Retry-After") != "" {
 				return &AppError{
-					Type:      ErrTypeRateLimit,
-					Message:   "Rate limit exceeded",
-					Cause:     err,
-					Retryable: true,
+					Type:       ErrTypeRateLimit,
+					Message:    "Rate limit exceeded",
+					Cause:      err,
+					Retryable:  true,
+					RetryAfter: retryAfter(ghErr.Response.Header, time.Now()),
 				}
 			}
 			return &AppError{
@@ -79,10 +105,11 @@ func WrapAPIError(err error) *AppError {
 			}
 		case 429:
 			return &AppError{
-				Type:      ErrTypeRateLimit,
-				Message:   "Too many requests",
-				Cause:     err,
-				Retryable: true,
+				Type:       ErrTypeRateLimit,
+				Message:    "Too many requests",
+				Cause:      err,
+				Retryable:  true,
+				RetryAfter: retryAfter(ghErr.Response.Header, time.Now()),
 			}
 		default:
 			if ghErr.Response.StatusCode >= 500 {
@@ -116,12 +143,23 @@ func IsRetryable(err error) bool {
 	return false
 }
 
+// MaxRetryAfter is the longest server-requested delay RetryWithBackoff waits.
+// Errors asking to wait longer, such as an exhausted hourly rate limit,
+// are returned instead of blocking the caller.
+const MaxRetryAfter = time.Minute
+
+// retryBaseBackoff is the first backoff delay of RetryWithBackoff.
+// It is a variable so tests can shorten it.
+var retryBaseBackoff = time.Second
+
 // RetryWithBackoff executes fn with exponential backoff retry for retryable errors.
 // It retries up to maxRetries times with exponential backoff starting at 1 second.
-// Maximum backoff is capped at 30 seconds.
+// Maximum backoff is capped at 30 seconds. When the error carries a RetryAfter
+// delay, that delay is waited instead, up to MaxRetryAfter. Retries are
+// logged to the logger of ctx set with ContextWithLogger.
 func RetryWithBackoff(ctx context.Context, maxRetries int, fn func() error) error {
 	var lastErr error
-	backoff := time.Second
+	backoff := retryBaseBackoff
 
 	for i := 0; i <= maxRetries; i++ {
 		lastErr = fn()
@@ -132,8 +170,22 @@ func RetryWithBackoff(ctx context.Context, maxRetries int, fn func() error) erro
 			return lastErr
 		}
 		if i < maxRetries {
+			wait := backoff
+			var appErr *AppError
+			if errors.As(lastErr, &appErr) && appErr.RetryAfter > 0 {
+				if appErr.RetryAfter > MaxRetryAfter {
+					return lastErr
+				}
+				wait = appErr.RetryAfter
+			}
+			loggerFrom(ctx).LogAttrs(ctx, slog.LevelInfo, "retrying request",
+				slog.Int("attempt", i+1),
+				slog.Int("max_retries", maxRetries),
+				slog.Duration("wait", wait),
+				slog.String("error", lastErr.Error()),
+			)
 			select {
-			case <-time.After(backoff):
+			case <-time.After(wait):
 				backoff = backoff * 2
 				if backoff > 30*time.Second {
 					backoff = 30 * time.Second
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -385,3 +386,168 @@ func TestIsRetryable_Nil(t *testing.T) {
 		t.Error("IsRetryable should return false for nil error")
 	}
 }
+
+func TestWrapAPIError_RetryAfterHeader(t *testing.T) {
+	ghErr := &ghlib.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{"Retry-After": []string{"7"}},
+		},
+		Message: "Too Many Requests",
+	}
+
+	appErr := WrapAPIError(ghErr)
+	if appErr.RetryAfter != 7*time.Second {
+		t.Errorf("AppError.RetryAfter = %v, want 7s", appErr.RetryAfter)
+	}
+}
+
+func TestWrapAPIError_403_SecondaryRateLimit(t *testing.T) {
+	ghErr := &ghlib.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: 403,
+			Header:     http.Header{"Retry-After": []string{"30"}},
+		},
+		Message: "You have exceeded a secondary rate limit",
+	}
+
+	appErr := WrapAPIError(ghErr)
+	if appErr.Type != ErrTypeRateLimit {
+		t.Errorf("AppError.Type = %v, want %v", appErr.Type, ErrTypeRateLimit)
+	}
+	if appErr.RetryAfter != 30*time.Second {
+		t.Errorf("AppError.RetryAfter = %v, want 30s", appErr.RetryAfter)
+	}
+}
+
+func TestWrapAPIError_RateLimitError(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	rateErr := &ghlib.RateLimitError{
+		Rate:     ghlib.Rate{Limit: 5000, Remaining: 0, Reset: ghlib.Timestamp{Time: reset}},
+		Response: &http.Response{StatusCode: 403},
+		Message:  "API rate limit exceeded",
+	}
+
+	appErr := WrapAPIError(rateErr)
+	if appErr.Type != ErrTypeRateLimit {
+		t.Errorf("AppError.Type = %v, want %v", appErr.Type, ErrTypeRateLimit)
+	}
+	if !appErr.Retryable {
+		t.Error("rate limit error should be retryable")
+	}
+	if appErr.RetryAfter < 9*time.Minute || appErr.RetryAfter > 10*time.Minute {
+		t.Errorf("AppError.RetryAfter = %v, want about 10m", appErr.RetryAfter)
+	}
+}
+
+func TestWrapAPIError_AbuseRateLimitError(t *testing.T) {
+	wait := 45 * time.Second
+	abuseErr := &ghlib.AbuseRateLimitError{
+		Response:   &http.Response{StatusCode: 403},
+		Message:    "secondary rate limit",
+		RetryAfter: &wait,
+	}
+
+	appErr := WrapAPIError(abuseErr)
+	if appErr.Type != ErrTypeRateLimit {
+		t.Errorf("AppError.Type = %v, want %v", appErr.Type, ErrTypeRateLimit)
+	}
+	if appErr.RetryAfter != wait {
+		t.Errorf("AppError.RetryAfter = %v, want %v", appErr.RetryAfter, wait)
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	orig := retryBaseBackoff
+	retryBaseBackoff = time.Millisecond
+	t.Cleanup(func() { retryBaseBackoff = orig })
+
+	retryable := &AppError{Type: ErrTypeServer, Retryable: true}
+
+	t.Run("retries until success", func(t *testing.T) {
+		calls := 0
+		err := RetryWithBackoff(context.Background(), 3, func() error {
+			calls++
+			if calls < 3 {
+				return retryable
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("RetryWithBackoff() = %v, want nil", err)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		calls := 0
+		err := RetryWithBackoff(context.Background(), 2, func() error {
+			calls++
+			return retryable
+		})
+		if err != retryable {
+			t.Errorf("RetryWithBackoff() = %v, want %v", err, retryable)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("does not retry non-retryable errors", func(t *testing.T) {
+		calls := 0
+		authErr := &AppError{Type: ErrTypeAuth}
+		_ = RetryWithBackoff(context.Background(), 3, func() error {
+			calls++
+			return authErr
+		})
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("waits for RetryAfter", func(t *testing.T) {
+		calls := 0
+		start := time.Now()
+		err := RetryWithBackoff(context.Background(), 1, func() error {
+			calls++
+			if calls == 1 {
+				return &AppError{Type: ErrTypeRateLimit, Retryable: true, RetryAfter: 50 * time.Millisecond}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("RetryWithBackoff() = %v, want nil", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("retried after %v, want at least 50ms", elapsed)
+		}
+	})
+
+	t.Run("returns when RetryAfter exceeds the maximum", func(t *testing.T) {
+		calls := 0
+		rateErr := &AppError{Type: ErrTypeRateLimit, Retryable: true, RetryAfter: MaxRetryAfter + time.Second}
+		err := RetryWithBackoff(context.Background(), 3, func() error {
+			calls++
+			return rateErr
+		})
+		if err != rateErr {
+			t.Errorf("RetryWithBackoff() = %v, want %v", err, rateErr)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RetryWithBackoff(ctx, 3, func() error {
+			return retryable
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RetryWithBackoff() = %v, want context.Canceled", err)
+		}
+	})
+}
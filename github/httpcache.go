@@ -0,0 +1,404 @@
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPCacheEntries is the number of responses kept by the in-memory HTTP cache
+const DefaultHTTPCacheEntries = 500
+
+// CachedResponse is a response body stored with its validators
+type CachedResponse struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// HTTPCache stores responses for conditional requests, keyed by request.
+type HTTPCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// memoryCache is an HTTPCache that keeps the most recently used responses in memory
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Most recently used at the front, holds keys
+	entries    map[string]*list.Element
+	responses  map[string]*CachedResponse
+}
+
+// NewMemoryCache creates an in-memory HTTP cache holding up to maxEntries responses.
+func NewMemoryCache(maxEntries int) HTTPCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultHTTPCacheEntries
+	}
+	return &memoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		responses:  make(map[string]*CachedResponse),
+	}
+}
+
+func (c *memoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return c.responses[key], true
+}
+
+func (c *memoryCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[key] = c.order.PushFront(key)
+	}
+	c.responses[key] = resp
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		k := oldest.Value.(string)
+		c.order.Remove(oldest)
+		delete(c.entries, k)
+		delete(c.responses, k)
+	}
+}
+
+// Disk cache limits
+const (
+	// DefaultHTTPCacheMaxBytes is the default size cap of the disk cache (32 MiB)
+	DefaultHTTPCacheMaxBytes = 32 << 20
+	// DefaultHTTPCacheMaxAge is how long an unused response is kept on disk
+	DefaultHTTPCacheMaxAge = 7 * 24 * time.Hour
+)
+
+// cacheFileExt is the extension of responses persisted by diskCache
+const cacheFileExt = ".json"
+
+// diskCache is an HTTPCache backed by an in-memory cache and persisted as
+// one JSON file per response, so validators survive restarts. Like the log
+// cache it is bounded by size and evicts the least recently used responses
+// first, tracking recency with file modification times. Responses unused
+// for longer than maxAge are evicted too.
+type diskCache struct {
+	mu       sync.Mutex // Guards the files in dir, size and loaded
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	memory   HTTPCache
+	size     int64 // Total size of the files in dir
+	loaded   bool  // size has been initialized from dir
+}
+
+// NewDiskCache creates an HTTP cache persisted in dir, holding at most
+// maxBytes of responses for at most maxAge since their last use.
+// Non-positive limits use DefaultHTTPCacheMaxBytes and DefaultHTTPCacheMaxAge.
+// The directory is created when the first response is stored.
+func NewDiskCache(dir string, maxBytes int64, maxAge time.Duration) HTTPCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultHTTPCacheMaxBytes
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultHTTPCacheMaxAge
+	}
+	return &diskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+		memory:   NewMemoryCache(DefaultHTTPCacheEntries),
+	}
+}
+
+// path returns the file a key is stored in
+func (c *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+cacheFileExt)
+}
+
+func (c *diskCache) Get(key string) (*CachedResponse, bool) {
+	if resp, ok := c.memory.Get(key); ok {
+		return resp, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if time.Since(info.ModTime()) > c.maxAge {
+		if os.Remove(path) == nil {
+			c.size -= info.Size()
+		}
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var resp CachedResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	c.memory.Set(key, &resp)
+	return &resp, true
+}
+
+func (c *diskCache) Set(key string, resp *CachedResponse) {
+	c.memory.Set(key, resp)
+
+	// Persisting is best effort: the in-memory copy still serves this session
+	data, err := json.Marshal(resp)
+	if err != nil || int64(len(data)) > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	path := c.path(key)
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	c.size += int64(len(data)) - replaced
+	if c.size > c.maxBytes {
+		c.evict()
+	}
+}
+
+// cachedFile is a persisted response found in the cache directory
+type cachedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// scan lists the responses in dir, removing those unused for longer than
+// maxAge. The caller must hold c.mu.
+func (c *diskCache) scan() []cachedFile {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	var files []cachedFile
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != cacheFileExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, e.Name())
+		if now.Sub(info.ModTime()) > c.maxAge {
+			_ = os.Remove(path)
+			continue
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+	}
+	return files
+}
+
+// load drops expired responses and totals the size of the rest, once, when
+// the cache is first used. Later changes keep the total up to date, so the
+// directory is only listed again to evict. The caller must hold c.mu.
+func (c *diskCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	for _, f := range c.scan() {
+		c.size += f.size
+	}
+}
+
+// evict removes responses unused for longer than maxAge, then the least
+// recently used ones until the total size is within the cap. The total is
+// recomputed from the directory, which other processes may have changed.
+// The caller must hold c.mu.
+func (c *diskCache) evict() {
+	files := c.scan()
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	c.size = total
+}
+
+// cacheTransport makes GET requests conditional on the ETag or Last-Modified
+// value of a previously cached response. When the server answers
+// 304 Not Modified, which does not count against the primary rate limit,
+// the cached body is served instead.
+type cacheTransport struct {
+	cache HTTPCache
+	next  http.RoundTripper
+	// identity names the credentials requests are sent with, so responses
+	// are only served to the token they were fetched with. Nil for
+	// anonymous requests.
+	identity func(ctx context.Context) (string, error)
+}
+
+// cacheKey identifies a request in the cache. Accept is included because
+// the same URL returns different representations for different media types,
+// and the identity because different tokens may see different data.
+func cacheKey(req *http.Request, identity string) string {
+	return req.URL.String() + " " + req.Header.Get("Accept") + " " + identity
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	// Only plain GET requests are cached
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return next.RoundTrip(req)
+	}
+
+	var identity string
+	if t.identity != nil {
+		var err error
+		if identity, err = t.identity(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	key := cacheKey(req, identity)
+	cached, ok := t.cache.Get(key)
+	if ok {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		return cachedHTTPResponse(req, resp, cached), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.cache.Set(key, &CachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cachedHTTPResponse builds a 200 response from a cached body. Headers of the
+// 304 response, such as the current rate limit, take precedence over the
+// cached ones.
+func cachedHTTPResponse(req *http.Request, notModified *http.Response, cached *CachedResponse) *http.Response {
+	header := cached.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for k, v := range notModified.Header {
+		header[k] = v
+	}
+	header.Set("Content-Length", strconv.Itoa(len(cached.Body)))
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// DefaultHTTPCacheDir returns the directory used to persist the HTTP cache,
+// under the user cache directory ($XDG_CACHE_HOME on Linux).
+func DefaultHTTPCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazyactions", "http"), nil
+}
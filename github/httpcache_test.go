@@ -0,0 +1,270 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newConditionalServer serves a fixed body with an ETag and answers
+// 304 Not Modified when the request carries the matching validator
+func newConditionalServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.Header().Set("X-RateLimit-Remaining", "4998")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"total_count":1}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, client *http.Client, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestCacheTransport_ServesCachedBodyOnNotModified(t *testing.T) {
+	var hits int32
+	server := newConditionalServer(t, &hits)
+	client := &http.Client{Transport: &cacheTransport{cache: NewMemoryCache(0)}}
+
+	first := get(t, client, server.URL)
+	firstBody, _ := io.ReadAll(first.Body)
+
+	second := get(t, client, server.URL)
+	secondBody, _ := io.ReadAll(second.Body)
+
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+	if second.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200 for a cached response", second.StatusCode)
+	}
+	if string(secondBody) != string(firstBody) {
+		t.Errorf("cached body = %q, want %q", secondBody, firstBody)
+	}
+	if got := second.Header.Get("X-RateLimit-Remaining"); got != "4998" {
+		t.Errorf("X-RateLimit-Remaining = %q, want the value from the 304 response", got)
+	}
+	if got := second.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want the cached value", got)
+	}
+}
+
+func TestCacheTransport_SkipsNonGetRequests(t *testing.T) {
+	var hits int32
+	server := newConditionalServer(t, &hits)
+	cache := NewMemoryCache(0)
+	client := &http.Client{Transport: &cacheTransport{cache: cache}}
+
+	resp, err := client.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if _, ok := cache.Get(server.URL + " "); ok {
+		t.Error("POST responses should not be cached")
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CachedResponse{ETag: "a"})
+	cache.Set("b", &CachedResponse{ETag: "b"})
+	cache.Get("a") // a is now more recent than b
+	cache.Set("c", &CachedResponse{ETag: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("entry %q should be kept", key)
+		}
+	}
+}
+
+func TestDiskCache_PersistsAcrossInstances(t *testing.T) {
+	dir := t.TempDir() + "/http"
+	NewDiskCache(dir, 0, 0).Set("key", &CachedResponse{
+		ETag:   `"v1"`,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"ok":true}`),
+	})
+
+	resp, ok := NewDiskCache(dir, 0, 0).Get("key")
+	if !ok {
+		t.Fatal("entry should be read back from disk")
+	}
+	if resp.ETag != `"v1"` || string(resp.Body) != `{"ok":true}` || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected entry: %+v", resp)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("cache dir has %d files, want 1 (no leftover temp files)", len(entries))
+	}
+}
+
+// cacheFiles lists the persisted responses in dir
+func cacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestDiskCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	body := []byte(strings.Repeat("x", 400))
+	// Bodies are base64 encoded, so each entry takes about 560 bytes and two fit
+	cache := NewDiskCache(dir, 1200, 0).(*diskCache)
+	cache.Set("a", &CachedResponse{ETag: "a", Body: body})
+	cache.Set("b", &CachedResponse{ETag: "b", Body: body})
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(cache.path("a"), old, old.Add(time.Minute))
+	_ = os.Chtimes(cache.path("b"), old, old)
+	cache.Set("c", &CachedResponse{ETag: "c", Body: body})
+
+	if len(cacheFiles(t, dir)) != 2 {
+		t.Fatalf("cache dir has %v, want 2 entries", cacheFiles(t, dir))
+	}
+	if _, err := os.Stat(cache.path("b")); err == nil {
+		t.Error("least recently used entry should be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := os.Stat(cache.path(key)); err != nil {
+			t.Errorf("entry %q should be kept: %v", key, err)
+		}
+	}
+}
+
+func TestDiskCache_DropsExpiredEntries(t *testing.T) {
+	dir := t.TempDir()
+	NewDiskCache(dir, 0, time.Hour).Set("key", &CachedResponse{ETag: `"v1"`})
+	cache := NewDiskCache(dir, 0, time.Hour).(*diskCache)
+	old := time.Now().Add(-2 * time.Hour)
+	_ = os.Chtimes(cache.path("key"), old, old)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("an entry unused for longer than the max age should miss")
+	}
+	if files := cacheFiles(t, dir); len(files) != 0 {
+		t.Errorf("expired entry should be removed, cache dir has %v", files)
+	}
+}
+
+func TestDiskCache_TracksSize(t *testing.T) {
+	dir := t.TempDir()
+	NewDiskCache(dir, 0, time.Hour).Set("kept", &CachedResponse{ETag: "kept"})
+	NewDiskCache(dir, 0, time.Hour).Set("expired", &CachedResponse{ETag: "expired"})
+	cache := NewDiskCache(dir, 0, time.Hour).(*diskCache)
+	old := time.Now().Add(-2 * time.Hour)
+	_ = os.Chtimes(cache.path("expired"), old, old)
+
+	// Opening the cache drops expired entries and totals the rest
+	cache.Set("new", &CachedResponse{ETag: "new", Body: []byte("body")})
+	if _, err := os.Stat(cache.path("expired")); err == nil {
+		t.Error("expired entry should be removed when the cache is opened")
+	}
+	// Replacing an entry only counts its new size
+	cache.Set("new", &CachedResponse{ETag: "new", Body: []byte("longer body")})
+
+	var want int64
+	for _, name := range cacheFiles(t, dir) {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want += info.Size()
+	}
+	if cache.size != want {
+		t.Errorf("size = %d, want %d", cache.size, want)
+	}
+}
+
+func TestCacheTransport_KeysByIdentity(t *testing.T) {
+	var hits int32
+	server := newConditionalServer(t, &hits)
+	cache := NewMemoryCache(0)
+	identity := "alice"
+	client := &http.Client{Transport: &cacheTransport{
+		cache:    cache,
+		identity: func(context.Context) (string, error) { return identity, nil },
+	}}
+
+	_ = get(t, client, server.URL)
+	identity = "bob"
+	resp := get(t, client, server.URL)
+	if resp.Header.Get("X-RateLimit-Remaining") != "4999" {
+		t.Error("a response cached for one token should not be served to another")
+	}
+	if _, ok := cache.Get(cacheKey(resp.Request, "alice")); !ok {
+		t.Error("response should be cached under the first identity")
+	}
+}
+
+func TestTokenTransport_Identity(t *testing.T) {
+	a, _ := (&tokenTransport{token: "ghp_one"}).identity(context.Background())
+	b, _ := (&tokenTransport{token: "ghp_two"}).identity(context.Background())
+	if a == b {
+		t.Error("different tokens should have different identities")
+	}
+	if strings.Contains(a, "ghp_one") {
+		t.Errorf("identity %q should not contain the token", a)
+	}
+}
+
+func TestNewClient_WithHTTPCache(t *testing.T) {
+	cache := NewMemoryCache(1)
+	client := NewClient("token", "owner", "repo", WithHTTPCache(cache)).(*realClient)
+
+	transport, ok := client.client.Client().Transport.(*cacheTransport)
+	if !ok {
+		t.Fatalf("transport = %T, want *cacheTransport", client.client.Client().Transport)
+	}
+	if transport.cache != cache {
+		t.Error("client should use the given cache")
+	}
+	logging, ok := transport.next.(*loggingTransport)
+	if !ok {
+		t.Fatalf("cache should wrap the logging transport, got %T", transport.next)
+	}
+	if _, ok := logging.next.(*tokenTransport); !ok {
+		t.Errorf("logging should wrap the token transport, got %T", logging.next)
+	}
+}
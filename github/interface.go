@@ -1,6 +1,9 @@
 package github
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 //go:generate moq -out client_moq.go -fmt . Client:MockClient
 
@@ -12,17 +15,32 @@ type Client interface {
 
 	// Runs
 	ListRuns(ctx context.Context, repo Repository, opts *ListRunsOpts) ([]Run, error)
+	GetRun(ctx context.Context, repo Repository, runID int64) (*Run, error)
 	CancelRun(ctx context.Context, repo Repository, runID int64) error
 	RerunWorkflow(ctx context.Context, repo Repository, runID int64) error
 	RerunFailedJobs(ctx context.Context, repo Repository, runID int64) error
 	TriggerWorkflow(ctx context.Context, repo Repository, workflowFile, ref string, inputs map[string]interface{}) error
 
+	// Commits
+	ResolveRef(ctx context.Context, repo Repository, ref string) (string, error)
+
 	// Jobs
 	ListJobs(ctx context.Context, repo Repository, runID int64) ([]Job, error)
+	ListJobsAllAttempts(ctx context.Context, repo Repository, runID int64) ([]Job, error)
 
 	// Logs
-	GetJobLogs(ctx context.Context, repo Repository, jobID int64) (string, error)
+	GetJobLogs(ctx context.Context, repo Repository, jobID int64) (io.ReadCloser, error)
+
+	// Annotations
+	GetJobAnnotations(ctx context.Context, repo Repository, jobID int64) ([]Annotation, error)
+
+	// Summaries
+	GetJobSummary(ctx context.Context, repo Repository, jobID int64) (JobSummary, error)
+
+	// Permissions
+	CheckPermissions(ctx context.Context, repo Repository) (Permissions, error)
 
 	// Rate limiting
 	RateLimitRemaining() int
+	RateLimit() RateLimit
 }
@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// discardLogger is used when no logger is configured
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// WithLogger sets the logger for the HTTP requests made by the client,
+// including log downloads. By default nothing is logged.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *clientConfig) {
+		c.logger = l
+	}
+}
+
+// loggerKey is the context key of the logger used by RetryWithBackoff
+type loggerKey struct{}
+
+// ContextWithLogger returns a context whose retries in RetryWithBackoff are
+// logged to l
+func ContextWithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// loggerFrom returns the logger of ctx, or one that discards everything
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+// rateLimitHeaders are the response headers logged with each request
+var rateLimitHeaders = []struct {
+	header string
+	key    string
+}{
+	{"X-RateLimit-Limit", "ratelimit_limit"},
+	{"X-RateLimit-Remaining", "ratelimit_remaining"},
+	{"X-RateLimit-Used", "ratelimit_used"},
+	{"X-RateLimit-Reset", "ratelimit_reset"},
+	{"X-RateLimit-Resource", "ratelimit_resource"},
+	{"Retry-After", "retry_after"},
+	{"X-GitHub-Request-Id", "request_id"},
+}
+
+// loggingTransport logs every request that reaches the network. It sits
+// below the cache, so 304 responses are logged as such, and above the token
+// transport, so the Authorization header is never seen. Only the host and
+// path of the URL are logged, since the query of log download redirects
+// carries a signature.
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger // Discards logs if nil
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	l := t.logger
+	if l == nil {
+		l = discardLogger
+	}
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	latency := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("host", req.URL.Host),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		l.LogAttrs(req.Context(), slog.LevelWarn, "http request failed", attrs...)
+		return nil, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	for _, h := range rateLimitHeaders {
+		if v := resp.Header.Get(h.header); v != "" {
+			attrs = append(attrs, slog.String(h.key, v))
+		}
+	}
+	level := slog.LevelDebug
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	l.LogAttrs(req.Context(), level, "http request", attrs...)
+	return resp, nil
+}
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureLogs returns a logger writing JSON lines to the returned buffer
+func captureLogs() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// logEntries decodes JSON log lines
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLoggingTransport(t *testing.T) {
+	logger, buf := captureLogs()
+	var hits int32
+	server := newConditionalServer(t, &hits)
+
+	client := &http.Client{Transport: &loggingTransport{next: &tokenTransport{token: "ghp_secret"}, logger: logger}}
+	resp := get(t, client, server.URL+"/repos/o/r/actions/runs?sig=secret")
+	_ = resp.Body.Close()
+
+	entries := logEntries(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d: %s", len(entries), buf)
+	}
+	e := entries[0]
+	if e["msg"] != "http request" || e["method"] != "GET" || e["path"] != "/repos/o/r/actions/runs" {
+		t.Errorf("unexpected log entry %v", e)
+	}
+	if e["status"] != float64(200) {
+		t.Errorf("status = %v, want 200", e["status"])
+	}
+	if e["ratelimit_remaining"] != "4999" {
+		t.Errorf("ratelimit_remaining = %v, want 4999", e["ratelimit_remaining"])
+	}
+	if _, ok := e["latency"]; !ok {
+		t.Error("latency should be logged")
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("log should not contain the token or query: %s", buf)
+	}
+}
+
+func TestNewClient_LogsNotModified(t *testing.T) {
+	logger, buf := captureLogs()
+	var hits int32
+	server := newConditionalServer(t, &hits)
+
+	client := NewClient("", "o", "r", WithLogger(logger)).(*realClient)
+	httpClient := &http.Client{Transport: client.client.Client().Transport}
+	for range 2 {
+		_ = get(t, httpClient, server.URL+"/x").Body.Close()
+	}
+
+	entries := logEntries(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d: %s", len(entries), buf)
+	}
+	if entries[1]["status"] != float64(http.StatusNotModified) {
+		t.Errorf("second request status = %v, want 304 as sent over the network", entries[1]["status"])
+	}
+}
+
+func TestNewClient_LogsDownloads(t *testing.T) {
+	logger, buf := captureLogs()
+	client := newLogsServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("log output"))
+	}, WithLogger(logger))
+
+	logs, err := client.GetJobLogs(context.Background(), Repository{Owner: "owner", Name: "repo"}, 1)
+	if err != nil {
+		t.Fatalf("GetJobLogs() error = %v", err)
+	}
+	_ = logs.Close()
+
+	var paths []string
+	for _, e := range logEntries(t, buf) {
+		if e["msg"] == "http request" {
+			paths = append(paths, e["path"].(string))
+		}
+	}
+	want := []string{"/repos/owner/repo/actions/jobs/1/logs", "/download"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("logged paths = %v, want %v", paths, want)
+	}
+	if strings.Contains(buf.String(), "signed") {
+		t.Errorf("log should not contain the download signature: %s", buf)
+	}
+}
+
+func TestNewClient_LogsPermissions(t *testing.T) {
+	logger, buf := captureLogs()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, workflow")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"permissions":{"push":true,"pull":true}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("", "owner", "repo", WithLogger(logger)).(*realClient)
+	client.client.BaseURL, _ = url.Parse(server.URL + "/")
+	if _, err := client.CheckPermissions(context.Background(), Repository{Owner: "owner", Name: "repo"}); err != nil {
+		t.Fatalf("CheckPermissions() error = %v", err)
+	}
+
+	for _, e := range logEntries(t, buf) {
+		if e["msg"] == "permissions checked" {
+			if e["checked"] != true || e["actions_write"] != true {
+				t.Errorf("unexpected permissions log entry %v", e)
+			}
+			return
+		}
+	}
+	t.Errorf("expected a permissions checked entry, got: %s", buf)
+}
+
+func TestRetryWithBackoff_LogsRetries(t *testing.T) {
+	logger, buf := captureLogs()
+	orig := retryBaseBackoff
+	retryBaseBackoff = time.Millisecond
+	t.Cleanup(func() { retryBaseBackoff = orig })
+
+	calls := 0
+	_ = RetryWithBackoff(ContextWithLogger(context.Background(), logger), 2, func() error {
+		calls++
+		if calls < 3 {
+			return &AppError{Type: ErrTypeServer, Message: "server error", Retryable: true}
+		}
+		return nil
+	})
+
+	entries := logEntries(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 retry log entries, got %d: %s", len(entries), buf)
+	}
+	for i, e := range entries {
+		if e["msg"] != "retrying request" || e["attempt"] != float64(i+1) || e["error"] != "server error" {
+			t.Errorf("unexpected retry log entry %v", e)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// Permissions describes whether the client's token may change workflow runs
+// in a repository, as determined by CheckPermissions.
+type Permissions struct {
+	Checked      bool     // The check completed; unchecked permissions allow everything
+	Scopes       []string // OAuth scopes of a classic token, nil for other tokens
+	ActionsWrite bool     // Runs can be cancelled, rerun and dispatched
+	Hint         string   // What is missing when ActionsWrite is false
+}
+
+// CanWriteActions reports whether write actions should be offered. Actions
+// are only withheld when the check positively found the permission missing.
+func (p Permissions) CanWriteActions() bool {
+	return !p.Checked || p.ActionsWrite
+}
+
+// oauthScopesHeader lists the scopes of classic tokens. Fine-grained tokens,
+// GitHub App tokens and GITHUB_TOKEN do not send it.
+const oauthScopesHeader = "X-OAuth-Scopes"
+
+// CheckPermissions determines whether the token can change workflow runs,
+// using only a read of the repository. Classic tokens are judged by their
+// scopes. For other tokens the repository's permissions only tell whether
+// the user may write to it, not whether the token grants Actions write, so
+// writers are left unchecked and a missing permission surfaces when an
+// action fails.
+func (c *realClient) CheckPermissions(ctx context.Context, repo Repository) (Permissions, error) {
+	r, resp, err := c.client.Repositories.Get(ctx, repo.Owner, repo.Name)
+	c.updateRateLimit(resp)
+	if err != nil {
+		return Permissions{}, WrapAPIError(err)
+	}
+
+	perms := repositoryPermissions(repo, r, resp.Header)
+	c.log().LogAttrs(ctx, slog.LevelDebug, "permissions checked",
+		slog.Bool("checked", perms.Checked),
+		slog.Bool("actions_write", perms.ActionsWrite),
+		slog.Any("scopes", perms.Scopes),
+	)
+	return perms, nil
+}
+
+// repositoryPermissions derives the permissions of a token from the
+// repository and the response headers it was read with
+func repositoryPermissions(repo Repository, r *github.Repository, header http.Header) Permissions {
+	// Read-only collaborators can't change runs, whatever the token allows
+	perms := r.GetPermissions()
+	if len(perms) > 0 && !perms["push"] && !perms["admin"] && !perms["maintain"] {
+		return Permissions{Checked: true, Hint: "you don't have write access to " + repo.Owner + "/" + repo.Name}
+	}
+
+	if values, ok := header[http.CanonicalHeaderKey(oauthScopesHeader)]; ok {
+		return classicPermissions(strings.Join(values, ","), r.GetPrivate())
+	}
+	return Permissions{}
+}
+
+// classicPermissions derives permissions from the comma separated scopes of
+// a classic token. Changing runs needs the repo scope, or public_repo for
+// public repositories, so the hint names the one that is missing.
+func classicPermissions(header string, private bool) Permissions {
+	scopes := []string{}
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	p := Permissions{Checked: true, Scopes: scopes}
+	p.ActionsWrite = slices.Contains(scopes, "repo") || (!private && slices.Contains(scopes, "public_repo"))
+	if !p.ActionsWrite {
+		scope := "repo"
+		if !private {
+			scope = "public_repo"
+		}
+		p.Hint = "token lacks the " + scope + " scope (gh auth refresh -s " + scope + ")"
+	}
+	return p
+}
@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// newPermissionsClient serves the repository, sending scopes as
+// X-OAuth-Scopes unless nil. Any other request fails the test, since the
+// check must not call write endpoints.
+func newPermissionsClient(t *testing.T, repoJSON string, scopes *string) *realClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repos/owner/repo" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if scopes != nil {
+			w.Header().Set("X-OAuth-Scopes", *scopes)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(repoJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(server.URL + "/")
+	return &realClient{client: gh, owner: "owner", repoName: "repo"}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	const writer = `{"private":true,"permissions":{"admin":false,"push":true,"pull":true}}`
+
+	tests := []struct {
+		name        string
+		repoJSON    string
+		scopes      *string
+		wantChecked bool
+		wantWrite   bool
+		wantScopes  []string
+		wantHint    string
+	}{
+		{"classic with repo scope", writer, strPtr("repo, workflow"), true, true, []string{"repo", "workflow"}, ""},
+		{"classic without repo scope", writer, strPtr("read:org"), true, false, []string{"read:org"}, "gh auth refresh -s repo)"},
+		{"classic with no scopes", writer, strPtr(""), true, false, []string{}, "gh auth refresh -s repo)"},
+		{"public_repo on public repository", `{"private":false}`, strPtr("public_repo"), true, true, []string{"public_repo"}, ""},
+		{"public_repo on private repository", `{"private":true}`, strPtr("public_repo"), true, false, []string{"public_repo"}, "gh auth refresh -s repo)"},
+		{"no scopes on public repository", `{"private":false}`, strPtr(""), true, false, []string{}, "gh auth refresh -s public_repo)"},
+		{"fine-grained for a writer", writer, nil, false, true, nil, ""},
+		{"read-only collaborator", `{"permissions":{"push":false,"pull":true}}`, nil, true, false, nil, "write access"},
+		{"app token without permissions", `{"private":true}`, nil, false, true, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newPermissionsClient(t, tt.repoJSON, tt.scopes)
+			perms, err := client.CheckPermissions(context.Background(), Repository{Owner: "owner", Name: "repo"})
+			if err != nil {
+				t.Fatalf("CheckPermissions() error = %v", err)
+			}
+			if perms.Checked != tt.wantChecked {
+				t.Errorf("Checked = %v, want %v", perms.Checked, tt.wantChecked)
+			}
+			if perms.CanWriteActions() != tt.wantWrite {
+				t.Errorf("CanWriteActions() = %v, want %v", perms.CanWriteActions(), tt.wantWrite)
+			}
+			if !tt.wantWrite && !strings.Contains(perms.Hint, tt.wantHint) {
+				t.Errorf("Hint = %q, want it to contain %q", perms.Hint, tt.wantHint)
+			}
+			if tt.wantScopes != nil && len(perms.Scopes) != len(tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", perms.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestCheckPermissions_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(server.URL + "/")
+	client := &realClient{client: gh, owner: "owner", repoName: "repo"}
+
+	perms, err := client.CheckPermissions(context.Background(), Repository{Owner: "owner", Name: "repo"})
+	if err == nil {
+		t.Fatal("CheckPermissions() error = nil, want server error")
+	}
+	if !perms.CanWriteActions() {
+		t.Error("CanWriteActions() = false after a failed check, want true")
+	}
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRateLimit is the primary rate limit of an authenticated user, per hour
+const DefaultRateLimit = 5000
+
+// RateLimit is the last known API rate limit state.
+type RateLimit struct {
+	Limit     int       // Requests allowed per window
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the current window resets, zero if unknown
+	RetryAt   time.Time // When a secondary rate limit (Retry-After) ends, zero if none
+}
+
+// Fraction returns the fraction of the budget left, between 0 and 1.
+// It returns 1 if the limit is unknown.
+func (r RateLimit) Fraction() float64 {
+	if r.Limit <= 0 {
+		return 1
+	}
+	return max(0, min(float64(r.Remaining)/float64(r.Limit), 1))
+}
+
+// retryAfter returns how long to wait before retrying a rate limited request,
+// from the Retry-After header (seconds) of a secondary rate limit, or the
+// X-RateLimit-Reset header (epoch seconds) of an exhausted primary limit.
+// It returns 0 if neither applies.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if header == nil {
+		return 0
+	}
+	if s := header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if d := time.Unix(reset, 0).Sub(now); d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
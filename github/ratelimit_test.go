@@ -0,0 +1,110 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	ghlib "github.com/google/go-github/v68/github"
+)
+
+func TestRateLimit_Fraction(t *testing.T) {
+	tests := []struct {
+		name string
+		rl   RateLimit
+		want float64
+	}{
+		{"unknown limit", RateLimit{}, 1},
+		{"full", RateLimit{Limit: 5000, Remaining: 5000}, 1},
+		{"half", RateLimit{Limit: 5000, Remaining: 2500}, 0.5},
+		{"exhausted", RateLimit{Limit: 5000, Remaining: 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rl.Fraction(); got != tt.want {
+				t.Errorf("Fraction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"nil header", nil, 0},
+		{"no headers", http.Header{}, 0},
+		{"retry-after seconds", http.Header{"Retry-After": []string{"60"}}, time.Minute},
+		{"invalid retry-after", http.Header{"Retry-After": []string{"soon"}}, 0},
+		{
+			name: "exhausted primary limit",
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)},
+			},
+			want: 90 * time.Second,
+		},
+		{
+			name: "reset without exhausted limit",
+			header: http.Header{
+				"X-Ratelimit-Remaining": []string{"10"},
+				"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealClient_UpdateRateLimit(t *testing.T) {
+	client := NewClient("token", "owner", "repo").(*realClient)
+
+	want := RateLimit{Limit: DefaultRateLimit, Remaining: DefaultRateLimit}
+	if got := client.RateLimit(); got != want {
+		t.Errorf("RateLimit() = %+v, want %+v", got, want)
+	}
+
+	// Nil responses are ignored
+	client.updateRateLimit(nil)
+	if got := client.RateLimit(); got != want {
+		t.Errorf("RateLimit() after nil response = %+v, want %+v", got, want)
+	}
+
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	client.updateRateLimit(&ghlib.Response{
+		Response: &http.Response{Header: http.Header{}},
+		Rate:     ghlib.Rate{Limit: 1000, Remaining: 250, Reset: ghlib.Timestamp{Time: reset}},
+	})
+	got := client.RateLimit()
+	if got.Limit != 1000 || got.Remaining != 250 || !got.Reset.Equal(reset) {
+		t.Errorf("RateLimit() = %+v, want limit 1000, remaining 250, reset %v", got, reset)
+	}
+	if !got.RetryAt.IsZero() {
+		t.Errorf("RateLimit().RetryAt = %v, want zero", got.RetryAt)
+	}
+	if remaining := client.RateLimitRemaining(); remaining != 250 {
+		t.Errorf("RateLimitRemaining() = %d, want 250", remaining)
+	}
+
+	// A secondary rate limit sets RetryAt and keeps the last known budget
+	client.updateRateLimit(&ghlib.Response{
+		Response: &http.Response{Header: http.Header{"Retry-After": []string{"60"}}},
+	})
+	got = client.RateLimit()
+	if got.Remaining != 250 {
+		t.Errorf("RateLimit().Remaining = %d, want 250", got.Remaining)
+	}
+	if until := time.Until(got.RetryAt); until < 50*time.Second || until > time.Minute {
+		t.Errorf("RateLimit().RetryAt in %v, want about 1m", until)
+	}
+}
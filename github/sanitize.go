@@ -2,30 +2,376 @@
 package github
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
 	"regexp"
+	"sort"
+	"strings"
 )
 
-// secretPatterns contains regex patterns for detecting secrets in logs.
-var secretPatterns = []*regexp.Regexp{
+// RedactionPlaceholder replaces every redacted value.
+const RedactionPlaceholder = "[REDACTED]"
+
+// Entropy detector settings
+const (
+	// EntropyMinLength is the shortest string the entropy detector considers
+	EntropyMinLength = 24
+	// EntropyThreshold is the Shannon entropy, in bits per character, above
+	// which a string is considered random. Hex strings such as commit SHAs
+	// stay below it.
+	EntropyThreshold = 4.2
+)
+
+// secretRule is a pattern to redact and keywords, one of which a line must
+// contain (ignoring case) for the pattern to match it. Rules without
+// keywords are checked on every line.
+type secretRule struct {
+	pattern  string
+	keywords []string
+}
+
+// secretRules are the built-in rules for detecting secrets in logs.
+var secretRules = []secretRule{
 	// GitHub tokens
-	regexp.MustCompile(`ghp_[a-zA-Z0-9]{36}`),
-	regexp.MustCompile(`github_pat_[a-zA-Z0-9]{22}_[a-zA-Z0-9]{59}`),
-	regexp.MustCompile(`gho_[a-zA-Z0-9]{36}`),
-	regexp.MustCompile(`ghs_[a-zA-Z0-9]{36}`),
+	{`ghp_[a-zA-Z0-9]{36}`, []string{"ghp_"}},
+	{`github_pat_[a-zA-Z0-9]{22}_[a-zA-Z0-9]{59}`, []string{"github_pat_"}},
+	{`gho_[a-zA-Z0-9]{36}`, []string{"gho_"}},
+	{`ghs_[a-zA-Z0-9]{36}`, []string{"ghs_"}},
 	// AWS
-	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
-	regexp.MustCompile(`(?i)(aws_secret_access_key|aws_access_key_id)\s*[=:]\s*['"]?[A-Za-z0-9/+=]{20,}['"]?`),
+	{`AKIA[0-9A-Z]{16}`, []string{"akia"}},
+	{`(?i)(aws_secret_access_key|aws_access_key_id)\s*[=:]\s*['"]?[A-Za-z0-9/+=]{20,}['"]?`, []string{"aws_"}},
 	// Generic secrets
-	regexp.MustCompile(`(?i)(api[_-]?key|apikey|secret|password|token|credential|auth)[=:]\s*['"]?[^\s'"]{8,}['"]?`),
+	{`(?i)(api[_-]?key|apikey|secret|password|token|credential|auth)[=:]\s*['"]?[^\s'"]{8,}['"]?`,
+		[]string{"key", "secret", "password", "token", "credential", "auth"}},
+}
+
+// secretPatterns contains regex patterns for detecting secrets in logs.
+var secretPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(secretRules))
+	for i, rule := range secretRules {
+		patterns[i] = regexp.MustCompile(rule.pattern)
+	}
+	return patterns
+}()
+
+// addMaskRegex matches the workflow command that masks a value in the rest
+// of the job's output
+var addMaskRegex = regexp.MustCompile(`::add-mask::(.+)$`)
+
+// entropyCandidateRegex matches strings the entropy detector considers
+var entropyCandidateRegex = regexp.MustCompile(`[A-Za-z0-9+/=_\-]{` + fmt.Sprint(EntropyMinLength) + `,}`)
+
+// RedactorOptions configures the rules of a Redactor in addition to the
+// built-in secret patterns.
+type RedactorOptions struct {
+	Patterns []string // Regular expressions whose matches are redacted
+	Literals []string // Exact values that are redacted
+	Entropy  bool     // Redact long random-looking strings
+}
+
+// Redactor removes secrets from log content. Besides its rules, values
+// masked by ::add-mask:: commands in a log are redacted in that log.
+//
+// Logs are redacted line by line in a single pass: a line is only matched
+// against the combined rules if it contains one of their keywords, which
+// most lines don't. Matches therefore never span lines.
+// It is safe for concurrent use.
+type Redactor struct {
+	rules   []secretRule
+	entropy bool
+	matcher *matcher
+}
+
+// defaultRedactor applies only the built-in secret patterns
+var defaultRedactor = mustRedactor(RedactorOptions{})
+
+// DefaultRedactor returns a redactor with only the built-in secret patterns.
+func DefaultRedactor() *Redactor {
+	return defaultRedactor
+}
+
+// mustRedactor creates a redactor from options known to be valid
+func mustRedactor(opts RedactorOptions) *Redactor {
+	r, err := NewRedactor(opts)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NewRedactor creates a redactor with the built-in secret patterns and the
+// rules in opts. It returns an error if a pattern does not compile.
+func NewRedactor(opts RedactorOptions) (*Redactor, error) {
+	rules := literalRules(opts.Literals)
+	rules = append(rules, secretRules...)
+	for _, p := range opts.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		// A pattern can only match lines containing its literal prefix
+		var keywords []string
+		if prefix, _ := re.LiteralPrefix(); prefix != "" {
+			keywords = []string{prefix}
+		}
+		rules = append(rules, secretRule{pattern: p, keywords: keywords})
+	}
+	return &Redactor{rules: rules, entropy: opts.Entropy, matcher: newMatcher(rules)}, nil
+}
+
+// literalRules turns values into rules that match them exactly, longest
+// first, so a value is redacted whole before any shorter value it contains
+func literalRules(values []string) []secretRule {
+	var literals []string
+	for _, v := range values {
+		if v != "" {
+			literals = append(literals, v)
+		}
+	}
+	sort.SliceStable(literals, func(i, j int) bool {
+		return len(literals[i]) > len(literals[j])
+	})
+	rules := make([]secretRule, len(literals))
+	for i, v := range literals {
+		rules[i] = secretRule{pattern: regexp.QuoteMeta(v), keywords: []string{v}}
+	}
+	return rules
+}
+
+// matcher matches all rules of a redactor at once
+type matcher struct {
+	combined *regexp.Regexp
+	keywords [][]byte // Lowercase keywords of all rules
+	always   bool     // Some rule has no keywords, so every line is matched
+}
+
+// newMatcher combines rules into a single regular expression. Rules that
+// come first win when several match at the same position.
+func newMatcher(rules []secretRule) *matcher {
+	m := &matcher{}
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = "(?:" + rule.pattern + ")"
+		if len(rule.keywords) == 0 {
+			m.always = true
+		}
+		for _, k := range rule.keywords {
+			m.keywords = append(m.keywords, []byte(strings.ToLower(k)))
+		}
+	}
+	m.combined = regexp.MustCompile(strings.Join(parts, "|"))
+	return m
+}
+
+// mayMatch reports whether line contains a keyword of any rule. lower is
+// a scratch buffer reused between lines.
+func (m *matcher) mayMatch(line string, lower *[]byte) bool {
+	if m.always {
+		return true
+	}
+	buf := (*lower)[:0]
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf = append(buf, c)
+	}
+	*lower = buf
+	for _, k := range m.keywords {
+		if bytes.Contains(buf, k) {
+			return true
+		}
+	}
+	return false
 }
 
-// SanitizeLogs removes potential secrets from log content.
+// RedactStream redacts the lines of one log in order. Values masked by an
+// ::add-mask:: command are redacted from that line on. A stream is not safe
+// for concurrent use.
+type RedactStream struct {
+	redactor *Redactor
+	matcher  *matcher
+	masks    map[string]bool
+	count    int
+	lower    []byte
+}
+
+// NewStream starts redacting a log.
+func (r *Redactor) NewStream() *RedactStream {
+	return &RedactStream{redactor: r, matcher: r.matcher, masks: map[string]bool{}}
+}
+
+// addMask adds a masked value, recompiling the matcher if it is new
+func (s *RedactStream) addMask(value string) {
+	if s.masks[value] {
+		return
+	}
+	s.masks[value] = true
+	masks := make([]string, 0, len(s.masks))
+	for v := range s.masks {
+		masks = append(masks, v)
+	}
+	s.matcher = newMatcher(append(literalRules(masks), s.redactor.rules...))
+}
+
+// Line returns line, which must not contain a newline, with all secrets
+// replaced by RedactionPlaceholder.
+func (s *RedactStream) Line(line string) string {
+	if v, ok := addMaskValue(line); ok {
+		s.addMask(v)
+	}
+	if s.matcher.mayMatch(line, &s.lower) {
+		line = s.matcher.combined.ReplaceAllStringFunc(line, func(string) string {
+			s.count++
+			return RedactionPlaceholder
+		})
+	}
+	if s.redactor.entropy && hasEntropyCandidate(line) {
+		line = entropyCandidateRegex.ReplaceAllStringFunc(line, func(v string) string {
+			if !isHighEntropy(v) {
+				return v
+			}
+			s.count++
+			return RedactionPlaceholder
+		})
+	}
+	return line
+}
+
+// Count returns the number of values redacted so far.
+func (s *RedactStream) Count() int {
+	return s.count
+}
+
+// Redact returns logs with all secrets replaced by RedactionPlaceholder and
+// the number of values replaced. Values masked by ::add-mask:: are
+// redacted from the command on.
+func (r *Redactor) Redact(logs string) (string, int) {
+	s := r.NewStream()
+	var b strings.Builder
+	b.Grow(len(logs))
+	for rest := logs; ; {
+		line, after, more := strings.Cut(rest, "\n")
+		b.WriteString(s.Line(line))
+		if !more {
+			break
+		}
+		b.WriteByte('\n')
+		rest = after
+	}
+	return b.String(), s.count
+}
+
+// RedactLines reads a log from src and calls fn with each redacted line.
+// Values masked by ::add-mask:: are redacted from the command on.
+// It returns the number of values redacted.
+func (r *Redactor) RedactLines(src io.Reader, fn func(line string)) (int, error) {
+	s := r.NewStream()
+	err := s.Lines(src, fn)
+	return s.count, err
+}
+
+// maxLogLineSize is the longest log line RedactStream.Lines reads.
+const maxLogLineSize = 64 << 20
+
+// Lines reads the rest of the log from src and calls fn with each redacted
+// line, without the trailing newline, as strings.Split would split the log.
+// Values masked by ::add-mask:: are collected as the lines pass.
+func (s *RedactStream) Lines(src io.Reader, fn func(line string)) error {
+	newline := false // The last line read ended with a newline
+	sc := bufio.NewScanner(src)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLogLineSize)
+	// Unlike bufio.ScanLines, keep carriage returns, as strings.Split does
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			newline = true
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			newline = false
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	for sc.Scan() {
+		fn(s.Line(sc.Text()))
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	// A log ending with a newline ends with an empty line
+	if newline {
+		fn(s.Line(""))
+	}
+	return nil
+}
+
+// addMaskValue returns the value masked by an ::add-mask:: command in line
+func addMaskValue(line string) (string, bool) {
+	if !strings.Contains(line, "::add-mask::") {
+		return "", false
+	}
+	m := addMaskRegex.FindStringSubmatch(strings.TrimRight(line, "\r"))
+	if m == nil {
+		return "", false
+	}
+	v := strings.TrimSpace(m[1])
+	return v, v != ""
+}
+
+// hasEntropyCandidate reports whether line has a run of at least
+// EntropyMinLength characters the entropy detector considers
+func hasEntropyCandidate(line string) bool {
+	run := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '+' || c == '/' || c == '=' || c == '_' || c == '-' {
+			if run++; run >= EntropyMinLength {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+	return false
+}
+
+// isHighEntropy reports whether s mixes letters and digits and its Shannon
+// entropy exceeds EntropyThreshold
+func isHighEntropy(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") || strings.Trim(s, "0123456789+/=_-") == "" {
+		return false
+	}
+	return shannonEntropy(s) > EntropyThreshold
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per byte
+func shannonEntropy(s string) float64 {
+	var freq [256]int
+	for i := 0; i < len(s); i++ {
+		freq[s[i]]++
+	}
+	entropy := 0.0
+	n := float64(len(s))
+	for _, f := range freq {
+		if f > 0 {
+			p := float64(f) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// SanitizeLogs removes potential secrets from log content using the
+// built-in secret patterns and ::add-mask:: commands.
 // It replaces matched patterns with "[REDACTED]".
 func SanitizeLogs(logs string) string {
-	result := logs
-	for _, pattern := range secretPatterns {
-		result = pattern.ReplaceAllString(result, "[REDACTED]")
-	}
+	result, _ := defaultRedactor.Redact(logs)
 	return result
 }
 
@@ -18,16 +18,29 @@ type Workflow struct {
 
 // Run represents a workflow run.
 type Run struct {
-	ID         int64
-	RunNumber  int    // Sequential run number (e.g., 21 for #21)
-	Name       string
-	Status     string // queued, in_progress, completed
-	Conclusion string // success, failure, cancelled
-	Branch     string
-	Event      string // push, pull_request, workflow_dispatch
-	CreatedAt  time.Time
-	Actor      string
-	URL        string
+	ID           int64
+	RunNumber    int // Sequential run number (e.g., 21 for #21)
+	RunAttempt   int // Attempt number, incremented on each rerun
+	Name         string
+	DisplayTitle string // Commit message title or PR title
+	Status       string // queued, in_progress, completed
+	Conclusion   string // success, failure, cancelled
+	Branch       string
+	HeadSHA      string
+	Event        string // push, pull_request, workflow_dispatch
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	RunStartedAt time.Time // Start of the latest attempt
+	Actor        string
+	URL          string
+	PullRequests []PullRequest
+}
+
+// PullRequest represents a pull request associated with a workflow run.
+type PullRequest struct {
+	Number     int
+	HeadBranch string
+	BaseBranch string
 }
 
 // IsRunning returns true if the run is in progress or queued.
@@ -40,13 +53,51 @@ func (r Run) IsFailed() bool {
 	return r.Conclusion == "failure"
 }
 
+// Duration returns the wall-clock duration of the latest attempt.
+// For a run that is still running, it is measured up to now.
+// A completed run is measured up to its last update.
+func (r Run) Duration() time.Duration {
+	start := r.RunStartedAt
+	if start.IsZero() {
+		start = r.CreatedAt
+	}
+	if r.IsRunning() {
+		return elapsed(start, time.Time{})
+	}
+	if r.UpdatedAt.IsZero() {
+		return 0
+	}
+	return elapsed(start, r.UpdatedAt)
+}
+
+// QueueDuration returns how long the run waited before starting.
+// It is only known for the first attempt, since reruns keep the original creation time.
+func (r Run) QueueDuration() time.Duration {
+	if r.RunAttempt > 1 || r.CreatedAt.IsZero() || r.RunStartedAt.Before(r.CreatedAt) {
+		return 0
+	}
+	return r.RunStartedAt.Sub(r.CreatedAt)
+}
+
+// Title returns the display title of the run, falling back to the branch.
+func (r Run) Title() string {
+	if r.DisplayTitle != "" {
+		return r.DisplayTitle
+	}
+	return r.Branch
+}
+
 // Job represents a job within a workflow run.
 type Job struct {
-	ID         int64
-	Name       string
-	Status     string // queued, in_progress, completed
-	Conclusion string // success, failure, cancelled
-	Steps      []Step
+	ID          int64
+	Name        string
+	Status      string // queued, in_progress, completed
+	Conclusion  string // success, failure, cancelled
+	Steps       []Step
+	RunAttempt  int       // Attempt of the run this job belongs to
+	CreatedAt   time.Time // When the job was queued
+	StartedAt   time.Time // When a runner picked up the job
+	CompletedAt time.Time
 }
 
 // IsCompleted returns true if the job has completed.
@@ -59,12 +110,80 @@ func (j Job) IsQueued() bool {
 	return j.Status == "queued"
 }
 
+// Duration returns how long the job ran.
+// For a job that has not completed yet, it is measured up to now.
+func (j Job) Duration() time.Duration {
+	return elapsed(j.StartedAt, j.CompletedAt)
+}
+
+// QueueDuration returns how long the job waited for a runner.
+func (j Job) QueueDuration() time.Duration {
+	if j.CreatedAt.IsZero() || j.StartedAt.IsZero() || j.StartedAt.Before(j.CreatedAt) {
+		return 0
+	}
+	return j.StartedAt.Sub(j.CreatedAt)
+}
+
 // Step represents a step within a job.
 type Step struct {
-	Name       string
-	Status     string // queued, in_progress, completed
-	Conclusion string // success, failure, skipped
-	Number     int
+	Name        string
+	Status      string // queued, in_progress, completed
+	Conclusion  string // success, failure, skipped
+	Number      int
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Annotation is a check run annotation pointing at a line of a file,
+// such as a compiler error or a test failure.
+type Annotation struct {
+	Path      string // File path relative to the repository root
+	StartLine int
+	EndLine   int
+	Level     string // notice, warning, failure
+	Title     string
+	Message   string
+}
+
+// JobSummary is the Markdown output reported by a job's check run, such as
+// the summary a workflow writes to $GITHUB_STEP_SUMMARY.
+type JobSummary struct {
+	Title   string
+	Summary string // Markdown
+	Text    string // Markdown details following the summary
+}
+
+// Markdown returns the summary followed by the details.
+func (s JobSummary) Markdown() string {
+	switch {
+	case s.Summary == "":
+		return s.Text
+	case s.Text == "":
+		return s.Summary
+	default:
+		return s.Summary + "\n\n" + s.Text
+	}
+}
+
+// Duration returns how long the step ran.
+// For a step that has not completed yet, it is measured up to now.
+func (s Step) Duration() time.Duration {
+	return elapsed(s.StartedAt, s.CompletedAt)
+}
+
+// elapsed returns the time between start and end, using now for a zero end.
+// It returns 0 if start is zero.
+func elapsed(start, end time.Time) time.Duration {
+	if start.IsZero() {
+		return 0
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
 }
 
 // ListRunsOpts represents options for listing workflow runs.
@@ -73,5 +192,7 @@ type ListRunsOpts struct {
 	Branch     string
 	Event      string
 	Status     string
+	HeadSHA    string
 	PerPage    int
+	Page       int
 }
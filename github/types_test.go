@@ -205,3 +205,124 @@ func TestListRunsOpts_Fields(t *testing.T) {
 		t.Errorf("ListRunsOpts.PerPage = %v, want 50", opts.PerPage)
 	}
 }
+
+func TestJob_Durations(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		job       Job
+		wantRun   time.Duration
+		wantQueue time.Duration
+	}{
+		{
+			name: "completed job",
+			job: Job{
+				CreatedAt:   created,
+				StartedAt:   created.Add(30 * time.Second),
+				CompletedAt: created.Add(2 * time.Minute),
+			},
+			wantRun:   90 * time.Second,
+			wantQueue: 30 * time.Second,
+		},
+		{
+			name:      "not started",
+			job:       Job{CreatedAt: created},
+			wantRun:   0,
+			wantQueue: 0,
+		},
+		{
+			name:      "completed before started",
+			job:       Job{StartedAt: created, CompletedAt: created.Add(-time.Second)},
+			wantRun:   0,
+			wantQueue: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.Duration(); got != tt.wantRun {
+				t.Errorf("Job.Duration() = %v, want %v", got, tt.wantRun)
+			}
+			if got := tt.job.QueueDuration(); got != tt.wantQueue {
+				t.Errorf("Job.QueueDuration() = %v, want %v", got, tt.wantQueue)
+			}
+		})
+	}
+}
+
+func TestStep_Duration_Running(t *testing.T) {
+	s := Step{StartedAt: time.Now().Add(-time.Minute)}
+	if got := s.Duration(); got < time.Minute {
+		t.Errorf("Step.Duration() = %v, want at least 1m for a running step", got)
+	}
+}
+
+func TestRun_Durations(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		run       Run
+		wantRun   time.Duration
+		wantQueue time.Duration
+	}{
+		{
+			name: "completed first attempt",
+			run: Run{
+				Status:       "completed",
+				RunAttempt:   1,
+				CreatedAt:    created,
+				RunStartedAt: created.Add(15 * time.Second),
+				UpdatedAt:    created.Add(5 * time.Minute),
+			},
+			wantRun:   5*time.Minute - 15*time.Second,
+			wantQueue: 15 * time.Second,
+		},
+		{
+			name: "rerun has no known queue time",
+			run: Run{
+				Status:       "completed",
+				RunAttempt:   2,
+				CreatedAt:    created,
+				RunStartedAt: created.Add(time.Hour),
+				UpdatedAt:    created.Add(time.Hour + time.Minute),
+			},
+			wantRun:   time.Minute,
+			wantQueue: 0,
+		},
+		{
+			name:      "completed without update time",
+			run:       Run{Status: "completed", CreatedAt: created},
+			wantRun:   0,
+			wantQueue: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.run.Duration(); got != tt.wantRun {
+				t.Errorf("Run.Duration() = %v, want %v", got, tt.wantRun)
+			}
+			if got := tt.run.QueueDuration(); got != tt.wantQueue {
+				t.Errorf("Run.QueueDuration() = %v, want %v", got, tt.wantQueue)
+			}
+		})
+	}
+}
+
+func TestRun_Duration_Running(t *testing.T) {
+	r := Run{Status: "in_progress", RunStartedAt: time.Now().Add(-2 * time.Minute)}
+	if got := r.Duration(); got < 2*time.Minute {
+		t.Errorf("Run.Duration() = %v, want at least 2m for a running run", got)
+	}
+}
+
+func TestRun_Title(t *testing.T) {
+	if got := (Run{Branch: "main", DisplayTitle: "Fix flaky test"}).Title(); got != "Fix flaky test" {
+		t.Errorf("Run.Title() = %q, want display title", got)
+	}
+	if got := (Run{Branch: "main"}).Title(); got != "main" {
+		t.Errorf("Run.Title() = %q, want branch fallback", got)
+	}
+}
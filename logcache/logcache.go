@@ -0,0 +1,159 @@
+// Package logcache provides an on-disk cache of completed job logs.
+//
+// Logs of a completed job never change, so they are stored once, keyed by
+// repository and job ID, and served from disk on later views. The cache is
+// bounded by size and evicts the least recently used logs first. Callers are
+// expected to store sanitized logs so that secrets never reach the disk.
+package logcache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// DefaultMaxBytes is the default size cap of the cache (256 MiB)
+const DefaultMaxBytes = 256 << 20
+
+// logFileExt is the extension of cached log files
+const logFileExt = ".log"
+
+// Store is a size-capped, least recently used cache of job logs on disk.
+// Recency is tracked with file modification times, which are refreshed on
+// every read, so it carries over between sessions.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+// New creates a store in dir that holds at most maxBytes of logs.
+// A non-positive maxBytes uses DefaultMaxBytes. The directory is created
+// when the first log is stored.
+func New(dir string, maxBytes int64) *Store {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Store{dir: dir, maxBytes: maxBytes}
+}
+
+// DefaultDir returns the directory used for cached logs, under the user
+// cache directory ($XDG_CACHE_HOME/lazyactions/logs on Linux).
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazyactions", "logs"), nil
+}
+
+// path returns the file holding the logs of a job
+func (s *Store) path(repo github.Repository, jobID int64) string {
+	return filepath.Join(s.dir, repo.Owner, repo.Name, strconv.FormatInt(jobID, 10)+logFileExt)
+}
+
+// Get returns the cached logs of a job and marks them as recently used.
+func (s *Store) Get(repo github.Repository, jobID int64) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(repo, jobID)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return string(data), true
+}
+
+// Put stores the logs of a completed job, then evicts the least recently
+// used logs until the cache fits within its size cap. Logs larger than
+// the cap are not stored.
+func (s *Store) Put(repo github.Repository, jobID int64, logs string) error {
+	if int64(len(logs)) > s.maxBytes {
+		return nil
+	}
+	if repo.Owner == "" || repo.Name == "" {
+		return errors.New("logcache: repository is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(repo, jobID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("logcache: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see partial logs
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return fmt.Errorf("logcache: %w", err)
+	}
+	_, werr := tmp.WriteString(logs)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("logcache: failed to write logs: %w", errors.Join(werr, cerr))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("logcache: %w", err)
+	}
+
+	return s.evict()
+}
+
+// cachedFile is a cached log file considered for eviction
+type cachedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes the least recently used log files until the total size
+// is within the cap. The caller must hold s.mu.
+func (s *Store) evict() error {
+	var files []cachedFile
+	var total int64
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != logFileExt {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("logcache: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= s.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	return nil
+}
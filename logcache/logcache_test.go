@@ -0,0 +1,101 @@
+package logcache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+var testRepo = github.Repository{Owner: "owner", Name: "repo"}
+
+func TestStore_PutAndGet(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir, 0)
+
+	if _, ok := store.Get(testRepo, 1); ok {
+		t.Fatal("Get() on an empty cache should miss")
+	}
+
+	if err := store.Put(testRepo, 1, "line 1\nline 2"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	logs, ok := store.Get(testRepo, 1)
+	if !ok || logs != "line 1\nline 2" {
+		t.Errorf("Get() = %q, %v, want stored logs", logs, ok)
+	}
+	if _, ok := store.Get(github.Repository{Owner: "other", Name: "repo"}, 1); ok {
+		t.Error("logs should be keyed by repository")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "owner", "repo", "1.log")); err != nil {
+		t.Errorf("logs should be stored under the repository directory: %v", err)
+	}
+
+	// A new store on the same directory sees the logs
+	if _, ok := New(dir, 0).Get(testRepo, 1); !ok {
+		t.Error("logs should persist across stores")
+	}
+}
+
+func TestStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir, 25)
+	logs := strings.Repeat("x", 10)
+
+	for id := int64(1); id <= 2; id++ {
+		if err := store.Put(testRepo, id, logs); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+	}
+	// Make job 1 older than job 2, then read it so it becomes the most recent
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(filepath.Join(dir, "owner", "repo", "1.log"), old, old)
+	_ = os.Chtimes(filepath.Join(dir, "owner", "repo", "2.log"), old.Add(time.Minute), old.Add(time.Minute))
+	store.Get(testRepo, 1)
+
+	if err := store.Put(testRepo, 3, logs); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	if _, ok := store.Get(testRepo, 2); ok {
+		t.Error("least recently used logs should be evicted")
+	}
+	for _, id := range []int64{1, 3} {
+		if _, ok := store.Get(testRepo, id); !ok {
+			t.Errorf("logs of job %d should be kept", id)
+		}
+	}
+}
+
+func TestStore_SkipsLogsLargerThanCap(t *testing.T) {
+	store := New(t.TempDir(), 5)
+
+	if err := store.Put(testRepo, 1, "too large"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if _, ok := store.Get(testRepo, 1); ok {
+		t.Error("logs larger than the cap should not be stored")
+	}
+}
+
+func TestStore_RequiresRepository(t *testing.T) {
+	if err := New(t.TempDir(), 0).Put(github.Repository{}, 1, "logs"); err == nil {
+		t.Error("Put() expected error without a repository")
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache-home")
+
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("DefaultDir() unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(dir, filepath.Join("lazyactions", "logs")) {
+		t.Errorf("DefaultDir() = %q, want a lazyactions/logs directory", dir)
+	}
+}
@@ -96,3 +96,40 @@ func DetectFromPath(path string) (*github.Repository, error) {
 	// Detect the repository
 	return Detect()
 }
+
+// Root returns the top-level directory of the git repository containing
+// the current directory.
+func Root() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotGitRepository
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CurrentBranch returns the name of the branch checked out in the current directory.
+// It returns an error when HEAD is detached.
+func CurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotGitRepository
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", errors.New("HEAD is detached, no current branch")
+	}
+	return branch, nil
+}
+
+// ResolveCommit resolves a git revision (e.g. "HEAD", a short SHA or a tag)
+// to its full commit SHA.
+func ResolveCommit(rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", rev+"^{commit}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %q: %w", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -426,3 +426,83 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCurrentBranchAndResolveCommit(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(origDir)
+
+	tmpDir := t.TempDir()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	cmds := [][]string{
+		{"git", "init", "-b", "feature/watch"},
+		{"git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial"},
+	}
+	for _, args := range cmds {
+		cmd := exec.Command(args[0], args[1:]...)
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to run %v: %v", args, err)
+		}
+	}
+
+	t.Run("current branch", func(t *testing.T) {
+		branch, err := CurrentBranch()
+		if err != nil {
+			t.Fatalf("CurrentBranch() unexpected error: %v", err)
+		}
+		if branch != "feature/watch" {
+			t.Errorf("CurrentBranch() = %q, want %q", branch, "feature/watch")
+		}
+	})
+
+	t.Run("root from subdirectory", func(t *testing.T) {
+		sub := filepath.Join(tmpDir, "pkg", "sub")
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.Chdir(sub); err != nil {
+			t.Fatalf("Failed to change directory: %v", err)
+		}
+		defer os.Chdir(tmpDir)
+
+		root, err := Root()
+		if err != nil {
+			t.Fatalf("Root() unexpected error: %v", err)
+		}
+		want, _ := filepath.EvalSymlinks(tmpDir)
+		if got, _ := filepath.EvalSymlinks(root); got != want {
+			t.Errorf("Root() = %q, want %q", root, tmpDir)
+		}
+	})
+
+	t.Run("resolve HEAD", func(t *testing.T) {
+		sha, err := ResolveCommit("HEAD")
+		if err != nil {
+			t.Fatalf("ResolveCommit() unexpected error: %v", err)
+		}
+		if len(sha) != 40 {
+			t.Errorf("ResolveCommit() = %q, want a 40 character SHA", sha)
+		}
+	})
+
+	t.Run("resolve unknown revision", func(t *testing.T) {
+		if _, err := ResolveCommit("does-not-exist"); err == nil {
+			t.Error("ResolveCommit() expected error for unknown revision, got nil")
+		}
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		cmd := exec.Command("git", "checkout", "--detach")
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to detach HEAD: %v", err)
+		}
+		if _, err := CurrentBranch(); err == nil {
+			t.Error("CurrentBranch() expected error for detached HEAD, got nil")
+		}
+	})
+}
@@ -3,6 +3,8 @@ package integration
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,12 +24,16 @@ func (m *MockClipboard) WriteAll(text string) error {
 }
 
 type mockState struct {
-	workflows []github.Workflow
-	runs      []github.Run
-	jobs      []github.Job
-	logs      string
-	err       error
-	rateLimit int
+	workflows   []github.Workflow
+	runs        []github.Run
+	jobs        []github.Job
+	logs        string
+	annotations []github.Annotation
+	summary     github.JobSummary
+	headSHA     string
+	err         error
+	rateLimit   int
+	permissions github.Permissions
 }
 
 func newMockClient(state *mockState) *github.MockClient {
@@ -42,11 +48,34 @@ func newMockClient(state *mockState) *github.MockClient {
 		ListRunsFunc: func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
 			return state.runs, state.err
 		},
+		GetRunFunc: func(ctx context.Context, repo github.Repository, runID int64) (*github.Run, error) {
+			for _, run := range state.runs {
+				if run.ID == runID {
+					return &run, state.err
+				}
+			}
+			return nil, state.err
+		},
+		ResolveRefFunc: func(ctx context.Context, repo github.Repository, ref string) (string, error) {
+			return state.headSHA, state.err
+		},
 		ListJobsFunc: func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
 			return state.jobs, state.err
 		},
-		GetJobLogsFunc: func(ctx context.Context, repo github.Repository, jobID int64) (string, error) {
-			return state.logs, state.err
+		ListJobsAllAttemptsFunc: func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
+			return state.jobs, state.err
+		},
+		GetJobLogsFunc: func(ctx context.Context, repo github.Repository, jobID int64) (io.ReadCloser, error) {
+			if state.err != nil {
+				return nil, state.err
+			}
+			return io.NopCloser(strings.NewReader(state.logs)), nil
+		},
+		GetJobAnnotationsFunc: func(ctx context.Context, repo github.Repository, jobID int64) ([]github.Annotation, error) {
+			return state.annotations, state.err
+		},
+		GetJobSummaryFunc: func(ctx context.Context, repo github.Repository, jobID int64) (github.JobSummary, error) {
+			return state.summary, state.err
 		},
 		CancelRunFunc: func(ctx context.Context, repo github.Repository, runID int64) error {
 			return state.err
@@ -66,6 +95,16 @@ func newMockClient(state *mockState) *github.MockClient {
 			}
 			return 5000
 		},
+		RateLimitFunc: func() github.RateLimit {
+			remaining := 5000
+			if state.rateLimit > 0 {
+				remaining = state.rateLimit
+			}
+			return github.RateLimit{Limit: github.DefaultRateLimit, Remaining: remaining}
+		},
+		CheckPermissionsFunc: func(ctx context.Context, repo github.Repository) (github.Permissions, error) {
+			return state.permissions, state.err
+		},
 	}
 }
 
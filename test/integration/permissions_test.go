@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/app"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// readOnlyPermissions is a checked token that can't change runs
+var readOnlyPermissions = github.Permissions{Checked: true, Hint: "token lacks the actions:write permission"}
+
+func TestPermissions_ReadOnlyToken(t *testing.T) {
+	setup := func(t *testing.T) *TestApp {
+		ta := NewTestApp(t,
+			WithMockWorkflows(DefaultTestWorkflows()),
+			WithMockRuns([]github.Run{RunningRun()}),
+		)
+		ta.SetSize(160, 40)
+		ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+		ta.App.Update(app.RunsLoadedMsg{Runs: []github.Run{RunningRun()}})
+		ta.App.Update(app.PermissionsCheckedMsg{Permissions: readOnlyPermissions})
+		return ta
+	}
+
+	t.Run("c explains the missing permission instead of confirming", func(t *testing.T) {
+		ta := setup(t)
+		ta.SendKey("l")
+
+		// The flash message is batched with the command that clears it
+		batch, ok := ta.ProcessCmd(ta.SendKey("c")).(tea.BatchMsg)
+		if !ok || len(batch) == 0 {
+			t.Fatal("c should return a flash message")
+		}
+		ta.ProcessCmdAndUpdate(batch[0])
+		view := ta.App.View()
+		if strings.Contains(view, "Cancel this run?") {
+			t.Error("confirm dialog shown for a read-only token")
+		}
+		if !strings.Contains(view, "actions:write") {
+			t.Error("view should show the missing permission")
+		}
+	})
+
+	t.Run("t does not trigger a workflow", func(t *testing.T) {
+		ta := setup(t)
+		ta.ProcessCmdChain(ta.SendKey("t"), 1)
+		if calls := len(ta.Mock().TriggerWorkflowCalls()); calls != 0 {
+			t.Errorf("TriggerWorkflow called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("status bar marks actions read-only", func(t *testing.T) {
+		ta := setup(t)
+		ta.SendKey("l")
+		if !strings.Contains(ta.App.View(), "(read-only)") {
+			t.Error("status bar should mark write actions read-only")
+		}
+	})
+}
+
+func TestPermissions_FailedCheckKeepsActions(t *testing.T) {
+	ta := NewTestApp(t,
+		WithMockWorkflows(DefaultTestWorkflows()),
+		WithMockRuns([]github.Run{RunningRun()}),
+	)
+	ta.SetSize(160, 40)
+	ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+	ta.App.Update(app.RunsLoadedMsg{Runs: []github.Run{RunningRun()}})
+	ta.App.Update(app.PermissionsCheckedMsg{Err: ErrTest})
+
+	ta.SendKey("l")
+	ta.SendKey("c")
+	view := ta.App.View()
+	if !strings.Contains(view, "Cancel this run?") {
+		t.Error("confirm dialog should be shown when the permission check failed")
+	}
+	if strings.Contains(view, "(read-only)") {
+		t.Error("actions should not be marked read-only when the permission check failed")
+	}
+}
@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nnnkkk7/lazyactions/app"
+	"github.com/nnnkkk7/lazyactions/github"
+)
+
+// runsByWorkflow returns one completed run per workflow, titled after the workflow
+func runsByWorkflow(workflowID int64) []github.Run {
+	titles := map[int64]string{1: "ci-run", 2: "deploy-run", 3: "release-run"}
+	return []github.Run{{
+		ID:           workflowID * 100,
+		RunNumber:    int(workflowID),
+		Status:       "completed",
+		Conclusion:   "success",
+		DisplayTitle: titles[workflowID],
+	}}
+}
+
+// holdResponse runs a fetch command and returns its message without
+// delivering it, simulating a response that is still in flight
+func holdResponse(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a fetch command")
+	}
+	msg := cmd()
+	if msg == nil {
+		t.Fatal("fetch command returned no message")
+	}
+	return msg
+}
+
+func TestStaleResponses_Runs(t *testing.T) {
+	t.Run("slow runs response for a previous workflow is dropped", func(t *testing.T) {
+		ta := NewTestApp(t, WithMockWorkflows(DefaultTestWorkflows()))
+		ta.SetSize(160, 40)
+		ta.Mock().ListRunsFunc = func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
+			return runsByWorkflow(opts.WorkflowID), nil
+		}
+
+		_, cmd := ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+		ciRuns := holdResponse(t, cmd)
+
+		// Select the next workflow; its runs arrive first
+		ta.ProcessCmdChain(ta.SendKey("down"), 10)
+		ta.App.Update(ciRuns)
+
+		view := ta.App.View()
+		if !strings.Contains(view, "deploy-run") {
+			t.Error("runs of the selected workflow should be shown")
+		}
+		if strings.Contains(view, "ci-run") {
+			t.Error("stale runs of the previous workflow should be dropped")
+		}
+	})
+
+	t.Run("superseded runs response for the same workflow is dropped", func(t *testing.T) {
+		ta := NewTestApp(t, WithMockWorkflows(DefaultTestWorkflows()))
+		ta.SetSize(160, 40)
+
+		// Each request sees a newer state of the runs
+		var mu sync.Mutex
+		calls := 0
+		ta.Mock().ListRunsFunc = func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			runs := runsByWorkflow(opts.WorkflowID)
+			runs[0].DisplayTitle += "-v" + strconv.Itoa(calls)
+			return runs, nil
+		}
+
+		_, cmd := ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+		oldRuns := holdResponse(t, cmd)
+
+		// Leave the workflow and come back; the newer response arrives first
+		ta.SendKey("down")
+		ta.ProcessCmdChain(ta.SendKey("up"), 10)
+		ta.App.Update(oldRuns)
+
+		view := ta.App.View()
+		if !strings.Contains(view, "ci-run-v2") {
+			t.Error("runs from the latest request should be shown")
+		}
+		if strings.Contains(view, "ci-run-v1") {
+			t.Error("runs from a superseded request should be dropped")
+		}
+	})
+
+	t.Run("error from a previous workflow's request is dropped", func(t *testing.T) {
+		ta := NewTestApp(t, WithMockWorkflows(DefaultTestWorkflows()))
+		ta.SetSize(160, 40)
+
+		_, cmd := ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+		ta.Mock().ListRunsFunc = func(ctx context.Context, repo github.Repository, opts *github.ListRunsOpts) ([]github.Run, error) {
+			if opts.WorkflowID == 1 {
+				return nil, &github.AppError{Type: github.ErrTypeNotFound, Message: "stale failure"}
+			}
+			return runsByWorkflow(opts.WorkflowID), nil
+		}
+		failed := holdResponse(t, cmd)
+
+		ta.ProcessCmdChain(ta.SendKey("down"), 10)
+		ta.App.Update(failed)
+
+		if strings.Contains(ta.App.View(), "stale failure") {
+			t.Error("error from a previous workflow's request should be dropped")
+		}
+	})
+}
+
+func TestStaleResponses_Jobs(t *testing.T) {
+	t.Run("slow jobs response for a previous run is dropped", func(t *testing.T) {
+		runs := []github.Run{
+			{ID: 100, RunNumber: 1, Status: "completed", Conclusion: "success", DisplayTitle: "first"},
+			{ID: 101, RunNumber: 2, Status: "completed", Conclusion: "failure", DisplayTitle: "second"},
+		}
+		ta := NewTestApp(t,
+			WithMockWorkflows(DefaultTestWorkflows()),
+			WithMockRuns(runs),
+		)
+		ta.SetSize(160, 40)
+		ta.Mock().ListJobsFunc = func(ctx context.Context, repo github.Repository, runID int64) ([]github.Job, error) {
+			name := "build-" + map[int64]string{100: "first", 101: "second"}[runID]
+			return []github.Job{{ID: runID * 10, Name: name, Status: "in_progress"}}, nil
+		}
+
+		// Load the runs of the selected workflow, holding the jobs of the first run
+		_, cmd := ta.App.Update(app.WorkflowsLoadedMsg{Workflows: DefaultTestWorkflows()})
+		firstJobs := holdResponse(t, ta.ProcessCmdAndUpdate(cmd))
+
+		// Select the second run; its jobs arrive first
+		ta.SendKey("j")
+		ta.ProcessCmdChain(ta.SendKey("down"), 10)
+		ta.App.Update(firstJobs)
+
+		view := ta.App.View()
+		if !strings.Contains(view, "build-second") {
+			t.Error("jobs of the selected run should be shown")
+		}
+		if strings.Contains(view, "build-first") {
+			t.Error("stale jobs of the previous run should be dropped")
+		}
+	})
+}